@@ -18,3 +18,14 @@ func (h *Hardware) SetTinkID(id string) {
 	}
 	h.Annotations[HardwareIDAnnotation] = id
 }
+
+// GetMACs retrieves the MAC addresses of the Hardware's interfaces.
+func (h *Hardware) GetMACs() []string {
+	var macs []string
+	for _, iface := range h.Spec.Interfaces {
+		if iface.DHCP != nil && iface.DHCP.MAC != "" {
+			macs = append(macs, iface.DHCP.MAC)
+		}
+	}
+	return macs
+}
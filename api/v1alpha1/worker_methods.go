@@ -0,0 +1,9 @@
+package v1alpha1
+
+import "strings"
+
+// WorkerName returns the name of the Worker object for workerID. Worker IDs are typically MAC
+// addresses which aren't valid object names.
+func WorkerName(workerID string) string {
+	return strings.ToLower(strings.ReplaceAll(workerID, ":", "-"))
+}
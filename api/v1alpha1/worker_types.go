@@ -0,0 +1,61 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func init() {
+	SchemeBuilder.Register(&Worker{}, &WorkerList{})
+}
+
+// WorkerStatus describes the worker as of its last heartbeat.
+type WorkerStatus struct {
+	// WorkerID is the ID the worker identifies itself with. It's typically a MAC address of the
+	// Hardware the worker runs on.
+	WorkerID string `json:"workerId"`
+
+	// Version is the version of the worker.
+	// +optional
+	Version string `json:"version,omitempty"`
+
+	// Runtime is the container runtime the worker executes actions with.
+	// +optional
+	Runtime string `json:"runtime,omitempty"`
+
+	// FreeDiskBytes is the disk space available to the worker.
+	// +optional
+	FreeDiskBytes int64 `json:"freeDiskBytes,omitempty"`
+
+	// RunningWorkflows identifies the Workflows the worker is executing.
+	// +optional
+	RunningWorkflows []string `json:"runningWorkflows,omitempty"`
+
+	// LastSeen is the time the worker last sent a heartbeat.
+	LastSeen metav1.Time `json:"lastSeen"`
+}
+
+// +kubebuilder:subresource:status
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:path=workers,scope=Namespaced,categories=tinkerbell,singular=worker
+// +kubebuilder:storageversion
+// +kubebuilder:printcolumn:JSONPath=".status.workerId",name=Worker-ID,type=string
+// +kubebuilder:printcolumn:JSONPath=".status.version",name=Version,type=string
+// +kubebuilder:printcolumn:JSONPath=".status.lastSeen",name=Last-Seen,type=date
+
+// Worker records the liveness of a worker. Workers are maintained by the Tink server from the
+// heartbeats workers send and reside in the namespace of the Hardware they run on.
+type Worker struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Status WorkerStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// WorkerList contains a list of Workers.
+type WorkerList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Worker `json:"items"`
+}
@@ -1,6 +1,8 @@
 package v1alpha1
 
 import (
+	"slices"
+
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -8,6 +10,15 @@ const (
 	// WorkflowIDAnnotation is used by the controller to store the
 	// ID assigned to the workflow by Tinkerbell for migrated workflows.
 	WorkflowIDAnnotation = "workflow.tinkerbell.org/id"
+
+	// WorkflowFinalizer is used by the controller to clean up resources created on behalf of a
+	// Workflow, such as BMC jobs and Hardware netboot settings, before the Workflow is deleted.
+	WorkflowFinalizer = "workflow.tinkerbell.org/finalizer"
+
+	// WorkflowResumeAnnotation requests a failed or timed out Workflow be resumed from the action
+	// that failed. Actions that previously succeeded are not run again. The controller removes the
+	// annotation once the request has been handled.
+	WorkflowResumeAnnotation = "workflow.tinkerbell.org/resume"
 )
 
 // TinkID returns the Tinkerbell ID associated with this Workflow.
@@ -59,14 +70,15 @@ func (w *Workflow) getTaskActionInfo() taskInfo {
 		}
 	INNER:
 		for ai, action := range task.Actions {
-			// Find the first non-successful action
+			// Find the first action that's yet to succeed or be skipped.
 			switch action.Status { //nolint:exhaustive // WorkflowStateWaiting is only used in Workflows not Actions.
-			case WorkflowStateSuccess:
+			case WorkflowStateSuccess, WorkflowStateSkipped:
 				actionIndex++
 				continue
 			case WorkflowStatePending, WorkflowStateRunning, WorkflowStateFailed, WorkflowStateTimeout:
 				taskIndex = ti
-				actionTaskIndex = ai
+				actionTaskIndex = currentGroupAction(task.Actions, ai)
+				actionIndex += actionTaskIndex - ai
 				found = true
 				break INNER
 			}
@@ -90,6 +102,63 @@ func (w *Workflow) getTaskActionInfo() taskInfo {
 	return ti
 }
 
+// currentGroupAction returns the index of the action that best describes the state of the group
+// containing actions[first], the group's first unsuccessful action. Failed actions take precedence
+// over running actions which take precedence over actions yet to run. Actions without a group are
+// their own group.
+func currentGroupAction(actions []Action, first int) int {
+	group := actions[first].Group
+	if group == "" {
+		return first
+	}
+
+	current := first
+	for i := first; i < len(actions) && actions[i].Group == group; i++ {
+		switch actions[i].Status { //nolint:exhaustive // Remaining states don't take precedence.
+		case WorkflowStateFailed, WorkflowStateTimeout:
+			return i
+		case WorkflowStateRunning:
+			if actions[current].Status != WorkflowStateRunning {
+				current = i
+			}
+		}
+	}
+	return current
+}
+
+// GetCurrentActionGroup returns the indices of the actions in the current action's task that
+// run concurrently with the current action. Actions without a group are returned alone.
+func (w *Workflow) GetCurrentActionGroup() []int {
+	ti := w.getTaskActionInfo()
+	if ti.CurrentAction == "" {
+		return nil
+	}
+
+	actions := w.Status.Tasks[ti.CurrentTaskIndex].Actions
+	current := slices.IndexFunc(actions, func(a Action) bool { return a.Name == ti.CurrentAction })
+	return ActionGroup(actions, current)
+}
+
+// ActionGroup returns the indices of the actions grouped with actions[i], including i. Groups are
+// formed by adjacent actions with the same non-empty Group.
+func ActionGroup(actions []Action, i int) []int {
+	start, end := i, i+1
+	if group := actions[i].Group; group != "" {
+		for start > 0 && actions[start-1].Group == group {
+			start--
+		}
+		for end < len(actions) && actions[end].Group == group {
+			end++
+		}
+	}
+
+	indices := make([]int, 0, end-start)
+	for j := start; j < end; j++ {
+		indices = append(indices, j)
+	}
+	return indices
+}
+
 func (w *Workflow) GetCurrentWorker() string {
 	return w.getTaskActionInfo().CurrentWorker
 }
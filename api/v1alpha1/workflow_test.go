@@ -399,6 +399,62 @@ func TestWorkflowMethods(t *testing.T) {
 				CurrentActionIndex:   0,
 			},
 		},
+		{
+			"group with running and failed actions",
+			&Workflow{
+				Status: WorkflowStatus{
+					Tasks: []Task{
+						{
+							Name:       "wipe",
+							WorkerAddr: "3c:ec:ef:4c:4f:54",
+							Actions: []Action{
+								{Name: "prepare", Status: WorkflowStateSuccess},
+								{Name: "wipe-0", Group: "wipe", Status: WorkflowStateSuccess},
+								{Name: "wipe-1", Group: "wipe", Status: WorkflowStateRunning},
+								{Name: "wipe-2", Group: "wipe", Status: WorkflowStateFailed},
+								{Name: "reboot", Status: WorkflowStatePending},
+							},
+						},
+					},
+				},
+			},
+			taskInfo{
+				TotalNumberOfActions: 5,
+				CurrentTaskIndex:     0,
+				CurrentTask:          "wipe",
+				CurrentWorker:        "3c:ec:ef:4c:4f:54",
+				CurrentAction:        "wipe-2",
+				CurrentActionState:   WorkflowStateFailed,
+				CurrentActionIndex:   3,
+			},
+		},
+		{
+			"group with pending and running actions",
+			&Workflow{
+				Status: WorkflowStatus{
+					Tasks: []Task{
+						{
+							Name:       "wipe",
+							WorkerAddr: "3c:ec:ef:4c:4f:54",
+							Actions: []Action{
+								{Name: "wipe-0", Group: "wipe", Status: WorkflowStatePending},
+								{Name: "wipe-1", Group: "wipe", Status: WorkflowStateRunning},
+								{Name: "reboot", Status: WorkflowStateRunning},
+							},
+						},
+					},
+				},
+			},
+			taskInfo{
+				TotalNumberOfActions: 3,
+				CurrentTaskIndex:     0,
+				CurrentTask:          "wipe",
+				CurrentWorker:        "3c:ec:ef:4c:4f:54",
+				CurrentAction:        "wipe-1",
+				CurrentActionState:   WorkflowStateRunning,
+				CurrentActionIndex:   1,
+			},
+		},
 	}
 	for _, tc := range cases {
 		t.Run(tc.name, func(t *testing.T) {
@@ -410,6 +466,34 @@ func TestWorkflowMethods(t *testing.T) {
 	}
 }
 
+func TestGetCurrentActionGroup(t *testing.T) {
+	wf := &Workflow{
+		Status: WorkflowStatus{
+			Tasks: []Task{
+				{
+					Name: "wipe",
+					Actions: []Action{
+						{Name: "prepare", Status: WorkflowStateSuccess},
+						{Name: "wipe-0", Group: "wipe", Status: WorkflowStateSuccess},
+						{Name: "wipe-1", Group: "wipe", Status: WorkflowStateRunning},
+						{Name: "wipe-2", Group: "wipe", Status: WorkflowStatePending},
+						{Name: "reboot", Group: "reboot", Status: WorkflowStatePending},
+					},
+				},
+			},
+		},
+	}
+
+	if diff := cmp.Diff([]int{1, 2, 3}, wf.GetCurrentActionGroup()); diff != "" {
+		t.Fatal(diff)
+	}
+
+	wf.Status.Tasks[0].Actions[0].Status = WorkflowStatePending
+	if diff := cmp.Diff([]int{0}, wf.GetCurrentActionGroup()); diff != "" {
+		t.Fatal(diff)
+	}
+}
+
 func TestSetCondition(t *testing.T) {
 	tests := map[string]struct {
 		ExistingConditions []WorkflowCondition
@@ -24,6 +24,7 @@ const (
 	WorkflowStateSuccess   = WorkflowState("STATE_SUCCESS")
 	WorkflowStateFailed    = WorkflowState("STATE_FAILED")
 	WorkflowStateTimeout   = WorkflowState("STATE_TIMEOUT")
+	WorkflowStateSkipped   = WorkflowState("STATE_SKIPPED")
 
 	NetbootJobFailed        WorkflowConditionType = "NetbootJobFailed"
 	NetbootJobComplete      WorkflowConditionType = "NetbootJobComplete"
@@ -33,6 +34,9 @@ const (
 	ToggleAllowNetbootTrue  WorkflowConditionType = "AllowNetbootTrue"
 	ToggleAllowNetbootFalse WorkflowConditionType = "AllowNetbootFalse"
 	TemplateRenderedSuccess WorkflowConditionType = "TemplateRenderedSuccess"
+	WorkflowResumed         WorkflowConditionType = "WorkflowResumed"
+	WorkerLost              WorkflowConditionType = "WorkerLost"
+	ISOEjectFailed          WorkflowConditionType = "ISOEjectFailed"
 
 	TemplateRenderingSuccessful TemplateRendering = "successful"
 	TemplateRenderingFailed     TemplateRendering = "failed"
@@ -204,6 +208,46 @@ type Action struct {
 	StartedAt   *metav1.Time      `json:"startedAt,omitempty"`
 	Seconds     int64             `json:"seconds,omitempty"`
 	Message     string            `json:"message,omitempty"`
+
+	// Retries is the number of times the action is retried after failing.
+	Retries int `json:"retries,omitempty"`
+
+	// Backoff is the number of seconds to wait before the first retry. The delay doubles for each
+	// subsequent retry.
+	Backoff int64 `json:"backoff,omitempty"`
+
+	// RetryOn restricts retries to failures with a matching exit code or failure reason. When
+	// empty, all failures are retried.
+	RetryOn []string `json:"retryOn,omitempty"`
+
+	// Attempts records each attempt at running the action.
+	Attempts []ActionAttempt `json:"attempts,omitempty"`
+
+	// Group runs the action concurrently with adjacent actions of the same task and Group. The
+	// action following a group starts once all actions in the group have finished. The group
+	// fails if any of its actions fail.
+	Group string `json:"group,omitempty"`
+
+	// When is a condition that must evaluate to true for the action to run. It's a Go template
+	// pipeline, without delimiters, evaluated immediately before the action runs. Hardware data is
+	// interpolated when the template is rendered while outputs of earlier actions are available via
+	// .Outputs. Actions whose condition evaluates to false are skipped. Outputs of actions that
+	// haven't run evaluate to empty strings. Hyphenated action names are referenced using index,
+	// for example `eq (index .Outputs "detect-disks" "type") "nvme"`.
+	When string `json:"when,omitempty"`
+
+	// Outputs are the outputs published by the action. Later actions receive them as
+	// environment variables and conditions reference them using .Outputs.
+	Outputs map[string]string `json:"outputs,omitempty"`
+}
+
+// ActionAttempt describes a single attempt at running an action.
+type ActionAttempt struct {
+	Attempt   int64         `json:"attempt"`
+	Status    WorkflowState `json:"status,omitempty"`
+	StartedAt *metav1.Time  `json:"startedAt,omitempty"`
+	Seconds   int64         `json:"seconds,omitempty"`
+	Message   string        `json:"message,omitempty"`
 }
 
 // HasCondition checks if the cType condition is present with status cStatus on a bmj.
@@ -0,0 +1,66 @@
+package v1alpha2
+
+import (
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type AgentStatus struct {
+	// AgentID is the ID the agent identifies itself with. It's typically a MAC address of the
+	// Hardware the agent runs on.
+	AgentID string `json:"agentId"`
+
+	// Version is the version of the agent.
+	// +optional
+	Version string `json:"version,omitempty"`
+
+	// Runtime is the container runtime the agent executes actions with.
+	// +optional
+	Runtime string `json:"runtime,omitempty"`
+
+	// FreeDiskBytes is the disk space available to the agent.
+	// +optional
+	FreeDiskBytes int64 `json:"freeDiskBytes,omitempty"`
+
+	// RunningWorkflows identifies the Workflows the agent is executing.
+	// +optional
+	RunningWorkflows []string `json:"runningWorkflows,omitempty"`
+
+	// LastSeen is the time the agent last sent a heartbeat.
+	LastSeen metav1.Time `json:"lastSeen"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:categories=tinkerbell
+// +kubebuilder:printcolumn:name="Agent ID",type=string,JSONPath=".status.agentId"
+// +kubebuilder:printcolumn:name="Version",type=string,JSONPath=".status.version"
+// +kubebuilder:printcolumn:name="Last Seen",type=date,JSONPath=".status.lastSeen"
+
+// Agent records the liveness of an agent. Agents are maintained by the Tink server from the
+// heartbeats agents send and reside in the namespace of the Hardware they run on.
+type Agent struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Status AgentStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+type AgentList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Agent `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&Agent{}, &AgentList{})
+}
+
+// AgentName returns the name of the Agent object for agentID. Agent IDs are typically MAC
+// addresses which aren't valid object names.
+func AgentName(agentID string) string {
+	return strings.ToLower(strings.ReplaceAll(agentID, ":", "-"))
+}
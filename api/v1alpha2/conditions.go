@@ -43,3 +43,28 @@ type Condition struct {
 
 // Conditions define a list of observations of a particular resource.
 type Conditions []Condition
+
+// Get retrieves the condition identified by t. If no such condition exists, Get returns nil.
+func (c Conditions) Get(t ConditionType) *Condition {
+	for i := range c {
+		if c[i].Type == t {
+			return &c[i]
+		}
+	}
+	return nil
+}
+
+// Set adds or replaces the condition identified by cond.Type. If the existing condition has the
+// same status, its LastTransition is preserved.
+func (c *Conditions) Set(cond Condition) {
+	existing := c.Get(cond.Type)
+	if existing == nil {
+		*c = append(*c, cond)
+		return
+	}
+
+	if existing.Status == cond.Status {
+		cond.LastTransition = existing.LastTransition
+	}
+	*existing = cond
+}
@@ -6,8 +6,8 @@ import (
 
 type TemplateSpec struct {
 	// Actions defines the set of actions to be run on a target machine. Actions are run sequentially
-	// in the order they are specified. At least 1 action must be specified. Names of actions
-	// must be unique within a Template.
+	// in the order they are specified unless grouped. At least 1 action must be specified. Names of
+	// actions must be unique within a Template.
 	// +kubebuilder:validation:MinItems=1
 	Actions []Action `json:"actions,omitempty"`
 
@@ -52,6 +52,38 @@ type Action struct {
 	// Namespace defines the Linux namespaces this container should execute in.
 	// +optional
 	Namespace *Namespace `json:"namespaces,omitempty"`
+
+	// Retries is the number of times the action is retried after failing. Defaults to 0.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	// +optional
+	Retries int `json:"retries,omitempty"`
+
+	// Backoff is the delay before the first retry. The delay doubles for each subsequent retry.
+	// +optional
+	Backoff *metav1.Duration `json:"backoff,omitempty"`
+
+	// RetryOn restricts retries to failures with a matching exit code or failure reason. When
+	// empty, all failures are retried.
+	// +optional
+	RetryOn []string `json:"retryOn,omitempty"`
+
+	// Group runs the action concurrently with adjacent actions of the same Group. The action
+	// following a group starts once all actions in the group have finished. The group fails if any
+	// of its actions fail.
+	// +optional
+	Group string `json:"group,omitempty"`
+
+	// When is a condition that must evaluate to true for the action to run. It's a Go template
+	// pipeline, without delimiters, evaluated immediately before the action runs. Hardware data and
+	// template parameters are interpolated when the template is rendered while outputs of earlier
+	// actions are available via .Outputs. Actions whose condition evaluates to false are skipped.
+	// For example, "gt {{ len .Hardware.StorageDevices }} 1" runs the action on Hardware with more
+	// than one storage device. Outputs of actions that haven't run evaluate to empty strings.
+	// Hyphenated action names are referenced using index, for example
+	// `eq (index .Outputs "detect-disks" "type") "nvme"`.
+	// +optional
+	When string `json:"when,omitempty"`
 }
 
 // Volume is a specification for mounting a volume in an action. Volumes take the form
@@ -76,7 +108,8 @@ type Namespace struct {
 	// +optional
 	Network *string `json:"network,omitempty"`
 
-	// PID defines the PID namespace
+	// PID defines the PID namespace, identified by a process in the namespace. Only 1, the host's
+	// PID namespace, is supported.
 	// +optional
 	PID *int `json:"pid,omitempty"`
 }
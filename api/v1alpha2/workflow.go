@@ -1,6 +1,8 @@
 package v1alpha2
 
 import (
+	"slices"
+
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
@@ -24,14 +26,20 @@ type WorkflowSpec struct {
 	// +kubebuilder:default=0
 	// +kubebuilder:validation:Minimum=0
 	TimeoutSeconds int64 `json:"timeout,omitempty"`
+
+	// Cancel requests the Workflow be canceled. Workflows that have not been dispatched to an agent
+	// are canceled immediately. Workflows dispatched to an agent transition to Cancelling until the
+	// agent confirms it has stopped executing the Workflow.
+	// +optional
+	Cancel bool `json:"cancel,omitempty"`
 }
 
 type WorkflowStatus struct {
 	// Actions is a list of action states.
 	Actions []ActionStatus `json:"actions"`
 
-	// StartedAt is the time the first action was requested. Nil indicates the Workflow has not
-	// started.
+	// StartedAt is the time the Workflow was first scheduled on an agent. The Workflow timeout is
+	// measured from StartedAt. Nil indicates the Workflow has not started.
 	// +optional
 	StartedAt *metav1.Time `json:"startedAt,omitempty"`
 
@@ -71,6 +79,36 @@ type ActionStatus struct {
 	// FailureMessage is a free-form user friendly message describing why the Action entered the
 	// ActionStateFailed state. Typically, this is an elaboration on the Reason.
 	FailureMessage string `json:"failureMessage,omitempty"`
+
+	// Attempts records each attempt at running the action, in order.
+	// +optional
+	Attempts []ActionAttempt `json:"attempts,omitempty"`
+
+	// Outputs are the outputs published by the action. Later actions receive them as
+	// environment variables and conditions reference them using .Outputs.
+	// +optional
+	Outputs map[string]string `json:"outputs,omitempty"`
+}
+
+// ActionAttempt describes a single attempt at running an action.
+type ActionAttempt struct {
+	// Attempt is the 1-based attempt number.
+	Attempt int `json:"attempt"`
+
+	// StartedAt is the time the attempt was started.
+	StartedAt metav1.Time `json:"startedAt"`
+
+	// FinishedAt is the time the attempt finished. Nil indicates the attempt is in progress.
+	// +optional
+	FinishedAt *metav1.Time `json:"finishedAt,omitempty"`
+
+	// FailureReason is the reason the attempt failed.
+	// +optional
+	FailureReason string `json:"failureReason,omitempty"`
+
+	// FailureMessage describes why the attempt failed.
+	// +optional
+	FailureMessage string `json:"failureMessage,omitempty"`
 }
 
 // State describes the point in time state of a Workflow.
@@ -101,6 +139,58 @@ const (
 	WorkflowStateCanceled WorkflowState = "Canceled"
 )
 
+// IsTerminal returns true if the state is one a Workflow cannot transition out of.
+func (s WorkflowState) IsTerminal() bool {
+	switch s {
+	case WorkflowStateSucceeded, WorkflowStateFailed, WorkflowStateCanceled:
+		return true
+	}
+	return false
+}
+
+const (
+	// WorkflowConditionTemplateRendered indicates the Template has been rendered into the Workflow
+	// Actions.
+	WorkflowConditionTemplateRendered ConditionType = "TemplateRendered"
+
+	// WorkflowConditionTimedOut indicates the Workflow exceeded its TimeoutSeconds.
+	WorkflowConditionTimedOut ConditionType = "TimedOut"
+
+	// WorkflowConditionAgentLost indicates the agent executing the Workflow stopped sending
+	// heartbeats.
+	WorkflowConditionAgentLost ConditionType = "AgentLost"
+
+	// WorkflowConditionRejected indicates the agent rejected the Workflow, typically because it's
+	// busy. Rejected Workflows are dispatched again after a backoff.
+	WorkflowConditionRejected ConditionType = "Rejected"
+)
+
+const (
+	// WorkflowReasonTemplateNotFound indicates the referenced Template does not exist.
+	WorkflowReasonTemplateNotFound = "TemplateNotFound"
+
+	// WorkflowReasonHardwareNotFound indicates the referenced Hardware does not exist.
+	WorkflowReasonHardwareNotFound = "HardwareNotFound"
+
+	// WorkflowReasonRenderFailed indicates the Template could not be rendered.
+	WorkflowReasonRenderFailed = "RenderFailed"
+
+	// WorkflowReasonTimeout indicates the Workflow, or one of its Actions, failed because the
+	// Workflow exceeded its TimeoutSeconds.
+	WorkflowReasonTimeout = "Timeout"
+
+	// WorkflowReasonHeartbeatTimeout indicates the Workflow failed because its agent sent no
+	// heartbeat within the agent timeout.
+	WorkflowReasonHeartbeatTimeout = "HeartbeatTimeout"
+
+	// WorkflowReasonWorkflowLost indicates the Workflow failed because its agent reconnected
+	// without it, typically because the agent restarted.
+	WorkflowReasonWorkflowLost = "WorkflowLost"
+
+	// WorkflowReasonAgentRejected indicates the agent rejected the Workflow.
+	WorkflowReasonAgentRejected = "AgentRejected"
+)
+
 // ActionState describes a point in time state of an Action.
 type ActionState string
 
@@ -117,8 +207,16 @@ const (
 	// ActionStatFailed indicates an Action failed to execute. Users may inspect the associated
 	// Workflow resource to gain deeper insights into why the action failed.
 	ActionStateFailed ActionState = "Failed"
+
+	// ActionStateSkipped indicates an Action wasn't executed because its condition evaluated to
+	// false.
+	ActionStateSkipped ActionState = "Skipped"
 )
 
+// WorkflowFinalizer is used by the controller to ensure in-flight Workflows are canceled on the
+// agent before the Workflow is deleted.
+const WorkflowFinalizer = "workflow.tinkerbell.org/finalizer"
+
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
 // +kubebuilder:resource:categories=tinkerbell,shortName=wf
@@ -145,6 +243,45 @@ type WorkflowList struct {
 	Items           []Workflow `json:"items,omitempty"`
 }
 
+// GroupFailed returns true if an action has failed and every action grouped with it has finished.
+// Actions grouped with a failed action continue until they finish so the workflow cannot fail
+// before then.
+func (w *Workflow) GroupFailed() bool {
+	actions := w.Status.Actions
+	i := slices.IndexFunc(actions, func(a ActionStatus) bool { return a.State == ActionStateFailed })
+	if i == -1 {
+		return false
+	}
+
+	for _, j := range ActionGroup(actions, i) {
+		switch actions[j].State { //nolint:exhaustive // Remaining states are terminal.
+		case ActionStatePending, ActionStateRunning:
+			return false
+		}
+	}
+	return true
+}
+
+// ActionGroup returns the indices of the actions grouped with actions[i], including i. Groups are
+// formed by adjacent actions with the same non-empty Group.
+func ActionGroup(actions []ActionStatus, i int) []int {
+	start, end := i, i+1
+	if group := actions[i].Rendered.Group; group != "" {
+		for start > 0 && actions[start-1].Rendered.Group == group {
+			start--
+		}
+		for end < len(actions) && actions[end].Rendered.Group == group {
+			end++
+		}
+	}
+
+	indices := make([]int, 0, end-start)
+	for j := start; j < end; j++ {
+		indices = append(indices, j)
+	}
+	return indices
+}
+
 func init() {
 	SchemeBuilder.Register(&Workflow{}, &WorkflowList{})
 }
@@ -6,8 +6,11 @@ import (
 	"github.com/tinkerbell/tink/internal/cli"
 )
 
+// version is set at build time.
+var version = "devel"
+
 func main() {
-	if err := cli.NewAgent().Execute(); err != nil {
+	if err := cli.NewAgent(version).Execute(); err != nil {
 		os.Exit(-1)
 	}
 }
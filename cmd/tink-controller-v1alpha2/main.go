@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/go-logr/logr"
 	"github.com/go-logr/zapr"
@@ -40,6 +41,8 @@ type Config struct {
 	MetricsAddr          string
 	ProbeAddr            string
 	EnableLeaderElection bool
+	AgentTimeout         time.Duration
+	CancelGracePeriod    time.Duration
 }
 
 func (c *Config) AddFlags(fs *pflag.FlagSet) {
@@ -53,6 +56,12 @@ func (c *Config) AddFlags(fs *pflag.FlagSet) {
 	fs.BoolVar(&c.EnableLeaderElection, "leader-elect", false,
 		"Enable leader election for controller manager. "+
 			"Enabling this will ensure there is only one active controller manager.")
+	fs.DurationVar(&c.AgentTimeout, "agent-timeout", 5*time.Minute,
+		"Fail scheduled and running workflows whose agent hasn't sent a heartbeat for this long. "+
+			"Workflows whose agent has never sent a heartbeat are unaffected. Zero disables the check.")
+	fs.DurationVar(&c.CancelGracePeriod, "cancel-grace-period", time.Minute,
+		"The time a canceled or deleted workflow waits for its agent to confirm cancellation "+
+			"before it's canceled or released regardless.")
 }
 
 func main() {
@@ -122,7 +131,12 @@ func NewRootCommand() *cobra.Command {
 				return err
 			}
 
-			if err := workflow.NewReconciler(mgr.GetClient()).SetupWithManager(mgr); err != nil {
+			reconciler := workflow.NewReconciler(
+				mgr.GetClient(),
+				workflow.WithAgentTimeout(config.AgentTimeout),
+				workflow.WithCancelGracePeriod(config.CancelGracePeriod),
+			)
+			if err := reconciler.SetupWithManager(mgr); err != nil {
 				return err
 			}
 
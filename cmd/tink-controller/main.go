@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/go-logr/logr"
 	"github.com/go-logr/zapr"
@@ -11,6 +12,7 @@ import (
 	"github.com/spf13/pflag"
 	"github.com/spf13/viper"
 	"github.com/tinkerbell/tink/internal/deprecated/controller"
+	"github.com/tinkerbell/tink/internal/deprecated/workflow"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 	"k8s.io/client-go/rest"
@@ -32,6 +34,7 @@ type Config struct {
 	ProbeAddr            string
 	EnableLeaderElection bool
 	LogLevel             int
+	WorkerTimeout        time.Duration
 }
 
 func (c *Config) AddFlags(fs *pflag.FlagSet) {
@@ -47,6 +50,9 @@ func (c *Config) AddFlags(fs *pflag.FlagSet) {
 			"Enabling this will ensure there is only one active controller manager.")
 	fs.IntVar(&c.LogLevel, "log-level", 0, "Log level (0: info, 1: debug)")
 	fs.StringVar(&c.Namespace, "namespace", "", "The namespace to watch for resources. Use empty string (with a ClusterRole) to watch all namespaces.")
+	fs.DurationVar(&c.WorkerTimeout, "worker-timeout", 5*time.Minute,
+		"Fail running workflows whose worker hasn't sent a heartbeat for this long. "+
+			"Workflows whose worker has never sent a heartbeat are unaffected. Zero disables the check.")
 }
 
 func main() {
@@ -110,7 +116,7 @@ func NewRootCommand() *cobra.Command {
 
 			ctrl.SetLogger(logger)
 
-			mgr, err := controller.NewManager(cfg, options)
+			mgr, err := controller.NewManager(cfg, options, workflow.WithWorkerTimeout(config.WorkerTimeout))
 			if err != nil {
 				return fmt.Errorf("controller manager: %w", err)
 			}
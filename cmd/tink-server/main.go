@@ -2,11 +2,15 @@ package main
 
 import (
 	"context"
+	"crypto/tls"
+	"errors"
 	"fmt"
+	"net/http"
 	"os"
 	"os/signal"
 	"strings"
 	"syscall"
+	"time"
 
 	"github.com/equinix-labs/otel-init-go/otelinit"
 	"github.com/go-logr/logr"
@@ -14,10 +18,14 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
 	"github.com/spf13/viper"
+	"github.com/tinkerbell/tink/internal/bolt"
 	"github.com/tinkerbell/tink/internal/grpcserver"
 	"github.com/tinkerbell/tink/internal/httpserver"
+	workflowproto "github.com/tinkerbell/tink/internal/proto/workflow/v2"
 	"github.com/tinkerbell/tink/internal/server"
 	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 )
 
 // version is set at build time.
@@ -33,12 +41,28 @@ type Config struct {
 	KubeconfigPath string
 	KubeAPI        string
 	KubeNamespace  string
+
+	BoltPath         string
+	BoltAPIAuthority string
+	WorkerTimeout    time.Duration
+
+	ActionLogDir string
+
+	TLSCertFile     string
+	TLSKeyFile      string
+	TLSClientCAFile string
+	AuthTokenFile   string
+
+	HTTPWorkflowAuthority string
 }
 
-const backendKubernetes = "kubernetes"
+const (
+	backendKubernetes = "kubernetes"
+	backendBolt       = "bolt"
+)
 
 func backends() []string {
-	return []string{backendKubernetes}
+	return []string{backendKubernetes, backendBolt}
 }
 
 func (c *Config) AddFlags(fs *pflag.FlagSet) {
@@ -48,6 +72,64 @@ func (c *Config) AddFlags(fs *pflag.FlagSet) {
 	fs.StringVar(&c.KubeconfigPath, "kubeconfig", "", "The path to the Kubeconfig. Only takes effect if `--backend=kubernetes`")
 	fs.StringVar(&c.KubeAPI, "kubernetes", "", "The Kubernetes API URL, used for in-cluster client construction. Only takes effect if `--backend=kubernetes`")
 	fs.StringVar(&c.KubeNamespace, "kube-namespace", "", "The Kubernetes namespace to target")
+	fs.StringVar(&c.TLSCertFile, "tls-cert-file", "", "The path to a PEM encoded certificate used to serve gRPC over TLS")
+	fs.StringVar(&c.TLSKeyFile, "tls-key-file", "", "The path to the PEM encoded key for `--tls-cert-file`")
+	fs.StringVar(&c.TLSClientCAFile, "tls-client-ca-file", "", "The path to PEM encoded CAs used to verify client certificates. Clients are identified by the certificate common name, which must match their worker or agent ID")
+	fs.StringVar(&c.AuthTokenFile, "auth-token-file", "", "The path to a file of bearer tokens, one 'token,identity' pair per line. The identity must match the worker or agent ID. Requires TLS")
+	fs.StringVar(&c.ActionLogDir, "action-log-dir", "", "The directory action logs uploaded by workers are written to. Uploads are rejected when empty")
+	fs.StringVar(&c.HTTPWorkflowAuthority, "http-workflow-authority", "", "The address used to expose the workflow API for HTTP transport agents, for example :42115. When TLS is configured it serves TLS and uses the same authentication as the gRPC server. Disabled when empty. Only takes effect if `--backend=kubernetes`")
+	fs.StringVar(&c.BoltPath, "bolt-path", "tink.db", "The path to the bolt database file. Only takes effect if `--backend=bolt`")
+	fs.StringVar(&c.BoltAPIAuthority, "bolt-api-authority", "127.0.0.1:42116", "The address used to expose the HTTP API that manages hardware, templates and workflows in the bolt database. It's served over plain HTTP without authentication so it should only be exposed to trusted networks. Disabled when empty. Only takes effect if `--backend=bolt`")
+	fs.DurationVar(&c.WorkerTimeout, "worker-timeout", 5*time.Minute, "Fail running workflows whose worker hasn't sent a heartbeat for this long. Workflows whose worker has never sent a heartbeat are unaffected. Zero disables the check. Only takes effect if `--backend=bolt`")
+}
+
+// serverSecurity creates the TLS config and authenticators shared by the gRPC and HTTP servers.
+// The TLS config is nil when TLS isn't configured.
+func (c *Config) serverSecurity() (*tls.Config, []grpcserver.Authenticator, error) {
+	if c.TLSCertFile == "" {
+		if c.TLSClientCAFile != "" || c.AuthTokenFile != "" {
+			return nil, nil, errors.New("authentication requires --tls-cert-file")
+		}
+		return nil, nil, nil
+	}
+
+	tlsConfig, err := grpcserver.NewTLSConfig(c.TLSCertFile, c.TLSKeyFile, c.TLSClientCAFile)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var authenticators []grpcserver.Authenticator
+	if c.TLSClientCAFile != "" {
+		authenticators = append(authenticators, grpcserver.ClientCertAuthenticator{})
+	}
+	if c.AuthTokenFile != "" {
+		tokens, err := grpcserver.LoadTokenFile(c.AuthTokenFile)
+		if err != nil {
+			return nil, nil, fmt.Errorf("load auth tokens: %w", err)
+		}
+		authenticators = append(authenticators, grpcserver.TokenAuthenticator{Tokens: tokens})
+
+		// Token authenticated clients needn't present a certificate. Clients that do must still
+		// present a verifiable certificate.
+		if c.TLSClientCAFile != "" {
+			tlsConfig.ClientAuth = tls.VerifyClientCertIfGiven
+		}
+	}
+
+	return tlsConfig, authenticators, nil
+}
+
+// grpcServerOptions creates the TLS and authentication options for the gRPC server.
+func grpcServerOptions(tlsConfig *tls.Config, authenticators []grpcserver.Authenticator) []grpc.ServerOption {
+	if tlsConfig == nil {
+		return nil
+	}
+
+	opts := []grpc.ServerOption{grpc.Creds(credentials.NewTLS(tlsConfig))}
+	if len(authenticators) > 0 {
+		opts = append(opts, grpcserver.WithAuthentication(authenticators...)...)
+	}
+	return opts
 }
 
 func (c *Config) PopulateFromLegacyEnvVar() {
@@ -105,13 +187,18 @@ func NewRootCommand() *cobra.Command {
 			// TODO(gianarb): I think we can do better in terms of
 			// graceful shutdown and error management but I want to
 			// figure this out in another PR
-			errCh := make(chan error, 2)
+			errCh := make(chan error, 4)
 			var registrar grpcserver.Registrar
+			var boltAPI http.Handler
+
+			var actionLogs server.ActionLogStore
+			if config.ActionLogDir != "" {
+				actionLogs = server.NewFileActionLogStore(config.ActionLogDir)
+			}
 
 			switch config.Backend {
 			case backendKubernetes:
-				var err error
-				registrar, err = server.NewKubeBackedServer(
+				srv, err := server.NewKubeBackedServer(
 					logger,
 					config.KubeconfigPath,
 					config.KubeAPI,
@@ -120,16 +207,43 @@ func NewRootCommand() *cobra.Command {
 				if err != nil {
 					return err
 				}
+				srv.ActionLogs = actionLogs
+				registrar = srv
+			case backendBolt:
+				store, err := bolt.Open(config.BoltPath)
+				if err != nil {
+					return err
+				}
+				defer store.Close()
+				srv := server.NewBoltBackedServer(logger, store)
+				srv.ActionLogs = actionLogs
+				srv.WorkerTimeout = config.WorkerTimeout
+				go srv.RunTimeoutChecks(ctx)
+				registrar = srv
+				if config.BoltAPIAuthority != "" {
+					boltAPI = server.NewBoltHTTPHandler(logger, srv)
+				}
 			default:
 				return fmt.Errorf("invalid backend: %s", config.Backend)
 			}
 
+			tlsConfig, authenticators, err := config.serverSecurity()
+			if err != nil {
+				return err
+			}
+
+			workflowSrv, ok := registrar.(workflowproto.WorkflowServiceServer)
+			if config.HTTPWorkflowAuthority != "" && !ok {
+				return fmt.Errorf("--http-workflow-authority requires --backend=%v", backendKubernetes)
+			}
+
 			// Start the gRPC server in the background
 			addr, err := grpcserver.SetupGRPC(
 				ctx,
 				registrar,
 				config.GRPCAuthority,
 				errCh,
+				grpcServerOptions(tlsConfig, authenticators)...,
 			)
 			if err != nil {
 				return err
@@ -137,6 +251,20 @@ func NewRootCommand() *cobra.Command {
 			logger.Info("started listener", "address", addr)
 
 			httpserver.SetupHTTP(ctx, logger, config.HTTPAuthority, errCh)
+			servers := 2
+
+			// The workflow API is served on its own listener so the metrics, health and version
+			// endpoints remain plain HTTP without client authentication.
+			if config.HTTPWorkflowAuthority != "" {
+				handler := server.NewWorkflowHTTPHandler(logger, workflowSrv, authenticators...)
+				httpserver.SetupHandler(ctx, logger, config.HTTPWorkflowAuthority, handler, tlsConfig, errCh)
+				servers++
+			}
+
+			if boltAPI != nil {
+				httpserver.SetupHandler(ctx, logger, config.BoltAPIAuthority, boltAPI, nil, errCh)
+				servers++
+			}
 
 			select {
 			case err := <-errCh:
@@ -146,14 +274,11 @@ func NewRootCommand() *cobra.Command {
 				closer()
 			}
 
-			// wait for grpc server to shutdown
-			err = <-errCh
-			if err != nil {
-				return err
-			}
-			err = <-errCh
-			if err != nil {
-				return err
+			// wait for the servers to shutdown
+			for range servers {
+				if err := <-errCh; err != nil {
+					return err
+				}
 			}
 			return nil
 		},
@@ -58,6 +58,9 @@ func NewRootCommand(version string) *cobra.Command {
 				viper.GetString("tinkerbell-grpc-authority"),
 				viper.GetBool("tinkerbell-tls"),
 				viper.GetBool("tinkerbell-insecure-tls"),
+				client.WithRootCA(viper.GetString("tinkerbell-tls-ca-file")),
+				client.WithClientCertificate(viper.GetString("tinkerbell-tls-cert-file"), viper.GetString("tinkerbell-tls-key-file")),
+				client.WithBearerToken(viper.GetString("tinkerbell-token")),
 			)
 			if err != nil {
 				return err
@@ -77,7 +80,11 @@ func NewRootCommand(version string) *cobra.Command {
 					Password: pwd,
 				})
 
-			logCapturer := worker.NewDockerLogCapturer(dockerClient, logger, os.Stdout)
+			var logCapturerOpts []worker.LogCapturerOption
+			if viper.GetBool("upload-action-logs") {
+				logCapturerOpts = append(logCapturerOpts, worker.WithLogUpload(workflowClient))
+			}
+			logCapturer := worker.NewDockerLogCapturer(dockerClient, logger, os.Stdout, logCapturerOpts...)
 
 			w := worker.NewWorker(
 				workerID,
@@ -88,7 +95,8 @@ func NewRootCommand(version string) *cobra.Command {
 				worker.WithMaxFileSize(maxFileSize),
 				worker.WithRetries(retryInterval, retries),
 				worker.WithLogCapture(captureActionLogs),
-				worker.WithPrivileged(true))
+				worker.WithPrivileged(true),
+				worker.WithHeartbeat(viper.GetDuration("heartbeat-interval"), version))
 
 			logger.Info("starting to process workflow actions", "workerID", workerID)
 			err = w.ProcessWorkflowActions(cmd.Context())
@@ -104,8 +112,14 @@ func NewRootCommand(version string) *cobra.Command {
 	rootCmd.Flags().Int("max-retry", defaultRetryCount, "Maximum number of retries to attempt (MAX_RETRY)")
 	rootCmd.Flags().Int64("max-file-size", defaultMaxFileSize, "Maximum file size in bytes (MAX_FILE_SIZE)")
 	rootCmd.Flags().Bool("capture-action-logs", true, "Capture action container output as part of worker logs")
+	rootCmd.Flags().Bool("upload-action-logs", false, "Upload captured action container output to the server. Requires --capture-action-logs (UPLOAD_ACTION_LOGS)")
+	rootCmd.Flags().Duration("heartbeat-interval", worker.DefaultHeartbeatInterval, "The interval at which heartbeats are sent to the server. Zero disables heartbeats (HEARTBEAT_INTERVAL)")
 	rootCmd.Flags().Bool("tinkerbell-tls", true, "Connect to server via TLS or not (TINKERBELL_TLS)")
 	rootCmd.Flags().Bool("tinkerbell-insecure-tls", false, "When connecting via TLS, enable insecure TLS via InsecureSkipVerify (TINKERBELL_INSECURE_TLS)")
+	rootCmd.Flags().String("tinkerbell-tls-ca-file", "", "When connecting via TLS, verify the server with the CAs in this file instead of the system CAs (TINKERBELL_TLS_CA_FILE)")
+	rootCmd.Flags().String("tinkerbell-tls-cert-file", "", "When connecting via TLS, present this client certificate. Its common name must match the worker id (TINKERBELL_TLS_CERT_FILE)")
+	rootCmd.Flags().String("tinkerbell-tls-key-file", "", "The key for the client certificate (TINKERBELL_TLS_KEY_FILE)")
+	rootCmd.Flags().String("tinkerbell-token", "", "Bearer token used to authenticate with the server. Requires TLS (TINKERBELL_TOKEN)")
 	rootCmd.Flags().StringP("docker-registry", "r", "", "Sets the Docker registry (DOCKER_REGISTRY)")
 	rootCmd.Flags().StringP("registry-username", "u", "", "Sets the registry username (REGISTRY_USERNAME)")
 	rootCmd.Flags().StringP("registry-password", "p", "", "Sets the registry-password (REGISTRY_PASSWORD)")
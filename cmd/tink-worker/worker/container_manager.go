@@ -139,6 +139,17 @@ func (m *containerManager) WaitForFailedContainer(ctx context.Context, id string
 	}
 }
 
+func (m *containerManager) GetExitCode(ctx context.Context, id string) (int, error) {
+	info, err := m.cli.ContainerInspect(ctx, id)
+	if err != nil {
+		return 0, errors.Wrap(err, "DOCKER INSPECT")
+	}
+	if info.ContainerJSONBase == nil || info.State == nil {
+		return 0, errors.New("DOCKER INSPECT: missing container state")
+	}
+	return info.State.ExitCode, nil
+}
+
 func (m *containerManager) RemoveContainer(ctx context.Context, id string) error {
 	// create options for removing container
 	opts := container.RemoveOptions{
@@ -77,7 +77,11 @@ func (c *fakeDockerClient) ContainerInspect(context.Context, string) (dockertype
 	if c.err != nil {
 		return dockertypes.ContainerJSON{}, c.err
 	}
-	return dockertypes.ContainerJSON{}, nil
+	return dockertypes.ContainerJSON{
+		ContainerJSONBase: &dockertypes.ContainerJSONBase{
+			State: &dockertypes.ContainerState{ExitCode: c.statusCode},
+		},
+	}, nil
 }
 
 func (c *fakeDockerClient) ContainerWait(context.Context, string, container.WaitCondition) (<-chan container.WaitResponse, <-chan error) {
@@ -354,6 +358,54 @@ func TestContainerManagerWaitFailed(t *testing.T) {
 	}
 }
 
+func TestContainerManagerGetExitCode(t *testing.T) {
+	cases := []struct {
+		name           string
+		containerID    string
+		dockerResponse int
+		clientErr      error
+		wantCode       int
+		wantErr        error
+	}{
+		{
+			name:           "Happy Path",
+			containerID:    "nomedalforchewie",
+			dockerResponse: 3,
+			wantCode:       3,
+		},
+		{
+			name:        "inspect failure",
+			containerID: "nomedalforchewie",
+			clientErr:   errors.New("You missed the shot"),
+			wantErr:     errors.New("DOCKER INSPECT: You missed the shot"),
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			logger := zapr.NewLogger(zap.Must(zap.NewDevelopment()))
+			mgr := NewContainerManager(logger, newFakeDockerClient(tc.containerID, "", 0, tc.dockerResponse, tc.clientErr, nil), RegistryConnDetails{Registry: ""})
+
+			got, gotErr := mgr.GetExitCode(context.Background(), tc.containerID)
+			if gotErr != nil {
+				if tc.wantErr == nil {
+					t.Errorf(`Got unexpected error: %v"`, gotErr)
+				} else if gotErr.Error() != tc.wantErr.Error() {
+					t.Errorf(`Got unexpected error: got "%v" wanted "%v"`, gotErr, tc.wantErr)
+				}
+				return
+			}
+			if tc.wantErr != nil {
+				t.Errorf("Missing expected error: %v", tc.wantErr)
+				return
+			}
+			if got != tc.wantCode {
+				t.Errorf("Unexpected response: got %d wanted %d", got, tc.wantCode)
+			}
+		})
+	}
+}
+
 func TestContainerManagerRemove(t *testing.T) {
 	cases := []struct {
 		name        string
@@ -0,0 +1,85 @@
+package worker
+
+import (
+	"context"
+	"time"
+
+	"github.com/tinkerbell/tink/internal/agent/disk"
+	"github.com/tinkerbell/tink/internal/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// DefaultHeartbeatInterval is the interval at which heartbeats are sent when unspecified.
+	DefaultHeartbeatInterval = 30 * time.Second
+
+	// heartbeatRuntime is the container runtime reported in heartbeats.
+	heartbeatRuntime = "docker"
+)
+
+// WithHeartbeat sends a heartbeat reporting version to the server every interval while
+// processing workflow actions. Zero or negative intervals disable heartbeats.
+func WithHeartbeat(interval time.Duration, version string) Option {
+	return func(w *Worker) {
+		w.heartbeatInterval = interval
+		w.version = version
+	}
+}
+
+// sendHeartbeats sends a heartbeat every heartbeatInterval until ctx is cancelled. Failed
+// heartbeats are logged and the next heartbeat is sent regardless unless the server doesn't
+// implement heartbeats.
+func (w *Worker) sendHeartbeats(ctx context.Context) {
+	l := w.logger.WithValues("workerID", w.workerID)
+	ticker := time.NewTicker(w.heartbeatInterval)
+	defer ticker.Stop()
+
+	for {
+		free, err := disk.FreeBytes(w.dataDir)
+		if err != nil {
+			l.Info("could not determine free disk space", "path", w.dataDir, "error", err)
+		}
+
+		var running []string
+		if wfID := w.runningWorkflow(); wfID != "" {
+			running = append(running, wfID)
+		}
+
+		_, err = w.tinkClient.WorkerHeartbeat(ctx, &proto.WorkerHeartbeatRequest{
+			WorkerId:           w.workerID,
+			Version:            w.version,
+			Runtime:            heartbeatRuntime,
+			FreeDiskBytes:      free,
+			RunningWorkflowIds: running,
+		})
+		switch {
+		case status.Code(err) == codes.Unimplemented:
+			l.Info("server doesn't support heartbeats; no longer sending them")
+			return
+		case err != nil && ctx.Err() == nil:
+			l.Info("failed to send heartbeat", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// setRunningWorkflow records wfID as the workflow the worker is executing. An empty wfID
+// indicates the worker is idle.
+func (w *Worker) setRunningWorkflow(wfID string) {
+	w.runningMu.Lock()
+	defer w.runningMu.Unlock()
+	w.running = wfID
+}
+
+// runningWorkflow returns the workflow the worker is executing or an empty string if idle.
+func (w *Worker) runningWorkflow() string {
+	w.runningMu.Lock()
+	defer w.runningMu.Unlock()
+	return w.running
+}
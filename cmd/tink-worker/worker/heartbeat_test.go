@@ -0,0 +1,91 @@
+package worker
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/google/go-cmp/cmp"
+	"github.com/tinkerbell/tink/internal/proto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/testing/protocmp"
+)
+
+// fakeHeartbeatClient is a WorkflowServiceClient that forwards heartbeats to a channel.
+type fakeHeartbeatClient struct {
+	proto.WorkflowServiceClient
+	heartbeats chan *proto.WorkerHeartbeatRequest
+	err        error
+}
+
+func (c *fakeHeartbeatClient) WorkerHeartbeat(ctx context.Context, req *proto.WorkerHeartbeatRequest, _ ...grpc.CallOption) (*proto.Empty, error) {
+	select {
+	case c.heartbeats <- req:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	return &proto.Empty{}, c.err
+}
+
+func TestSendHeartbeats(t *testing.T) {
+	client := &fakeHeartbeatClient{heartbeats: make(chan *proto.WorkerHeartbeatRequest)}
+	w := NewWorker("worker", client, nil, nil, logr.Discard(),
+		WithDataDir(t.TempDir()),
+		WithHeartbeat(time.Millisecond, "v1.0.0"))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go w.sendHeartbeats(ctx)
+
+	first := <-client.heartbeats
+	if first.GetFreeDiskBytes() == 0 {
+		t.Fatal("expected free disk space to be reported")
+	}
+	want := &proto.WorkerHeartbeatRequest{
+		WorkerId:      "worker",
+		Version:       "v1.0.0",
+		Runtime:       "docker",
+		FreeDiskBytes: first.GetFreeDiskBytes(),
+	}
+	if diff := cmp.Diff(want, first, protocmp.Transform()); diff != "" {
+		t.Fatal(diff)
+	}
+
+	w.setRunningWorkflow("default/workflow")
+	for hb := range client.heartbeats {
+		if len(hb.GetRunningWorkflowIds()) > 0 {
+			if diff := cmp.Diff([]string{"default/workflow"}, hb.GetRunningWorkflowIds()); diff != "" {
+				t.Fatal(diff)
+			}
+			break
+		}
+	}
+}
+
+func TestSendHeartbeats_Unimplemented(t *testing.T) {
+	client := &fakeHeartbeatClient{
+		heartbeats: make(chan *proto.WorkerHeartbeatRequest, 1),
+		err:        status.Error(codes.Unimplemented, "unknown method"),
+	}
+	w := NewWorker("worker", client, nil, nil, logr.Discard(),
+		WithDataDir(t.TempDir()),
+		WithHeartbeat(time.Millisecond, "v1.0.0"))
+
+	done := make(chan struct{})
+	go func() {
+		w.sendHeartbeats(context.Background())
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected heartbeats to stop when the server doesn't implement them")
+	}
+	if len(client.heartbeats) != 1 {
+		t.Fatalf("expected a single heartbeat, got %v", len(client.heartbeats))
+	}
+}
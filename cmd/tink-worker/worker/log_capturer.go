@@ -9,6 +9,7 @@ import (
 	"github.com/docker/docker/api/types/container"
 	"github.com/docker/docker/client"
 	"github.com/go-logr/logr"
+	"github.com/tinkerbell/tink/internal/proto"
 )
 
 // DockerLogCapturer is a LogCapturer that can stream docker container logs to an io.Writer.
@@ -16,6 +17,20 @@ type DockerLogCapturer struct {
 	dockerClient client.ContainerAPIClient
 	logger       logr.Logger
 	writer       io.Writer
+
+	// uploadClient, when set, is used to upload captured logs to the server.
+	uploadClient proto.WorkflowServiceClient
+}
+
+// LogCapturerOption configures a DockerLogCapturer.
+type LogCapturerOption func(*DockerLogCapturer)
+
+// WithLogUpload uploads captured logs to the server using client in addition to writing them
+// to the capturer's writer.
+func WithLogUpload(client proto.WorkflowServiceClient) LogCapturerOption {
+	return func(l *DockerLogCapturer) {
+		l.uploadClient = client
+	}
 }
 
 // getLogger is a helper function to get logging out of a context, or use the default logger.
@@ -29,12 +44,16 @@ func (l *DockerLogCapturer) getLogger(ctx context.Context) logr.Logger {
 }
 
 // NewDockerLogCapturer returns a LogCapturer that can stream container logs to a given writer.
-func NewDockerLogCapturer(cli client.ContainerAPIClient, logger logr.Logger, writer io.Writer) *DockerLogCapturer {
-	return &DockerLogCapturer{
+func NewDockerLogCapturer(cli client.ContainerAPIClient, logger logr.Logger, writer io.Writer, opts ...LogCapturerOption) *DockerLogCapturer {
+	l := &DockerLogCapturer{
 		dockerClient: cli,
 		logger:       logger,
 		writer:       writer,
 	}
+	for _, opt := range opts {
+		opt(l)
+	}
+	return l
 }
 
 // CaptureLogs streams container logs to the capturer's writer.
@@ -51,8 +70,24 @@ func (l *DockerLogCapturer) CaptureLogs(ctx context.Context, id string) {
 	}
 	defer reader.Close()
 
+	writer := l.writer
+	if l.uploadClient != nil {
+		uploader, err := newActionLogUploader(ctx, l.uploadClient, l.getLogger(ctx))
+		switch {
+		case err != nil:
+			l.getLogger(ctx).Error(err, "failed to upload logs for container", "containerID", id)
+		case uploader != nil:
+			defer func() {
+				if err := uploader.Close(); err != nil {
+					l.getLogger(ctx).Error(err, "failed to upload logs for container", "containerID", id)
+				}
+			}()
+			writer = io.MultiWriter(l.writer, uploader)
+		}
+	}
+
 	scanner := bufio.NewScanner(reader)
 	for scanner.Scan() {
-		fmt.Fprintln(l.writer, scanner.Text())
+		fmt.Fprintln(writer, scanner.Text())
 	}
 }
@@ -12,8 +12,12 @@ import (
 	"github.com/docker/docker/client"
 	"github.com/go-logr/logr"
 	"github.com/go-logr/zapr"
+	"github.com/google/go-cmp/cmp"
 	"github.com/pkg/errors"
+	"github.com/tinkerbell/tink/internal/proto"
 	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/testing/protocmp"
 )
 
 type fakeDockerLoggerClient struct {
@@ -101,11 +105,65 @@ func TestLogCapturerContextLogger(t *testing.T) {
 				ctx = context.WithValue(ctx, loggingContextKey, tc.logger())
 			}
 			clogger := &DockerLogCapturer{
-				newFakeDockerLoggerClient("", nil),
-				logger,
-				os.Stdout,
+				dockerClient: newFakeDockerLoggerClient("", nil),
+				logger:       logger,
+				writer:       os.Stdout,
 			}
 			clogger.getLogger(ctx)
 		})
 	}
 }
+
+// fakeUploadClient is a WorkflowServiceClient that records uploaded action log chunks.
+type fakeUploadClient struct {
+	proto.WorkflowServiceClient
+	grpc.ClientStream
+	chunks []*proto.ActionLogChunk
+	closed bool
+}
+
+func (c *fakeUploadClient) UploadActionLogs(context.Context, ...grpc.CallOption) (proto.WorkflowService_UploadActionLogsClient, error) {
+	return c, nil
+}
+
+func (c *fakeUploadClient) Send(chunk *proto.ActionLogChunk) error {
+	c.chunks = append(c.chunks, chunk)
+	return nil
+}
+
+func (c *fakeUploadClient) CloseAndRecv() (*proto.Empty, error) {
+	c.closed = true
+	return &proto.Empty{}, nil
+}
+
+func TestLogCapturerUpload(t *testing.T) {
+	client := &fakeUploadClient{}
+	var writer bytes.Buffer
+	clogger := NewDockerLogCapturer(
+		newFakeDockerLoggerClient("line1\nline2\n", nil),
+		logr.Discard(),
+		&writer,
+		WithLogUpload(client),
+	)
+
+	ctx := withActionLogTags(context.Background(), "default/wf", &proto.WorkflowAction{
+		TaskName: "os-installation",
+		Name:     "disk-wipe",
+		WorkerId: "worker",
+	})
+	clogger.CaptureLogs(ctx, "container")
+
+	if got := writer.String(); got != "line1\nline2\n" {
+		t.Fatalf("Wrong content written to buffer. Expected 'line1\\nline2\\n', got '%s'", got)
+	}
+	want := []*proto.ActionLogChunk{
+		{WorkflowId: "default/wf", TaskName: "os-installation", ActionName: "disk-wipe", WorkerId: "worker", Data: []byte("line1\n")},
+		{WorkflowId: "default/wf", TaskName: "os-installation", ActionName: "disk-wipe", WorkerId: "worker", Data: []byte("line2\n")},
+	}
+	if diff := cmp.Diff(want, client.chunks, protocmp.Transform()); diff != "" {
+		t.Fatalf("unexpected chunks (-want +got):\n%s", diff)
+	}
+	if !client.closed {
+		t.Fatal("expected upload stream to be closed")
+	}
+}
@@ -0,0 +1,77 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	"github.com/go-logr/logr"
+	"github.com/tinkerbell/tink/internal/proto"
+)
+
+// actionLogTagsKey is the context key for the action a container's logs belong to.
+type actionLogTagsKey struct{}
+
+// withActionLogTags returns a copy of ctx identifying the action whose container logs are
+// captured with it.
+func withActionLogTags(ctx context.Context, wfID string, action *proto.WorkflowAction) context.Context {
+	return context.WithValue(ctx, actionLogTagsKey{}, &proto.ActionLogChunk{
+		WorkflowId: wfID,
+		TaskName:   action.GetTaskName(),
+		ActionName: action.GetName(),
+		WorkerId:   action.GetWorkerId(),
+	})
+}
+
+// actionLogUploader is an io.WriteCloser that uploads each write as an action log chunk. Upload
+// failures are logged and stop further uploads but never fail writes so local log capture is
+// unaffected.
+type actionLogUploader struct {
+	stream proto.WorkflowService_UploadActionLogsClient
+	tags   *proto.ActionLogChunk
+	logger logr.Logger
+	failed bool
+}
+
+// newActionLogUploader opens an upload stream for the action identified in ctx. It returns nil
+// if ctx doesn't identify an action.
+func newActionLogUploader(ctx context.Context, client proto.WorkflowServiceClient, logger logr.Logger) (*actionLogUploader, error) {
+	tags, ok := ctx.Value(actionLogTagsKey{}).(*proto.ActionLogChunk)
+	if !ok {
+		return nil, nil
+	}
+
+	stream, err := client.UploadActionLogs(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &actionLogUploader{stream: stream, tags: tags, logger: logger}, nil
+}
+
+func (u *actionLogUploader) Write(p []byte) (int, error) {
+	if u.failed {
+		return len(p), nil
+	}
+
+	err := u.stream.Send(&proto.ActionLogChunk{
+		WorkflowId: u.tags.GetWorkflowId(),
+		TaskName:   u.tags.GetTaskName(),
+		ActionName: u.tags.GetActionName(),
+		WorkerId:   u.tags.GetWorkerId(),
+		Data:       append([]byte(nil), p...),
+	})
+	if err != nil {
+		u.failed = true
+		// The cause of a failed send is reported when the stream is closed.
+		if !errors.Is(err, io.EOF) {
+			u.logger.Error(err, "failed to upload action logs")
+		}
+	}
+	return len(p), nil
+}
+
+// Close completes the upload.
+func (u *actionLogUploader) Close() error {
+	_, err := u.stream.CloseAndRecv()
+	return err
+}
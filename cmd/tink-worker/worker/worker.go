@@ -4,12 +4,23 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
 	"strconv"
+	"sync"
 	"time"
 
 	"github.com/go-logr/logr"
 	"github.com/pkg/errors"
+	"github.com/tinkerbell/tink/internal/agent/condition"
+	"github.com/tinkerbell/tink/internal/agent/failure"
+	"github.com/tinkerbell/tink/internal/agent/output"
 	"github.com/tinkerbell/tink/internal/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	googleproto "google.golang.org/protobuf/proto"
 )
 
 const (
@@ -20,6 +31,16 @@ const (
 	errReportActionStatus = "failed to report action status"
 
 	msgTurn = "it's turn for a different worker: %s"
+
+	// reasonTimeout is the failure reason used to match retry policies for actions that time out.
+	reasonTimeout = "Timeout"
+
+	// reasonInvalidOutput is the failure reason used when the outputs written by an action can't
+	// be parsed.
+	reasonInvalidOutput = "InvalidOutput"
+
+	// maxRetryBackoff is the maximum delay between attempts of an action.
+	maxRetryBackoff = 10 * time.Minute
 )
 
 type loggingContext string
@@ -85,6 +106,7 @@ type ContainerManager interface {
 	StartContainer(ctx context.Context, id string) error
 	WaitForContainer(ctx context.Context, id string) (proto.State, error)
 	WaitForFailedContainer(ctx context.Context, id string, failedActionStatus chan proto.State)
+	GetExitCode(ctx context.Context, id string) (int, error)
 	RemoveContainer(ctx context.Context, id string) error
 	PullImage(ctx context.Context, image string) error
 }
@@ -105,6 +127,13 @@ type Worker struct {
 
 	retries       int
 	retryInterval time.Duration
+
+	heartbeatInterval time.Duration
+	version           string
+
+	// runningMu guards running, the ID of the workflow being executed.
+	runningMu sync.Mutex
+	running   string
 }
 
 // NewWorker creates a new Worker, creating a new Docker registry client.
@@ -137,7 +166,7 @@ func NewWorker(
 }
 
 // getLogger is a helper function to get logging out of a context, or use the default logger.
-func (w Worker) getLogger(ctx context.Context) logr.Logger {
+func (w *Worker) getLogger(ctx context.Context) logr.Logger {
 	loggerIface := ctx.Value(loggingContextKey)
 	if loggerIface == nil {
 		return w.logger
@@ -146,17 +175,25 @@ func (w Worker) getLogger(ctx context.Context) logr.Logger {
 	return l
 }
 
-// execute executes a workflow action, optionally capturing logs.
-func (w *Worker) execute(ctx context.Context, wfID string, action *proto.WorkflowAction) (proto.State, error) {
+// execute executes a workflow action, optionally capturing logs. The action receives outputs
+// published by earlier actions as environment variables and execute returns the outputs it
+// published.
+func (w *Worker) execute(ctx context.Context, wfID string, action *proto.WorkflowAction, outputs map[string]map[string]string) (proto.State, map[string]string, error) {
 	l := w.getLogger(ctx).WithValues("workflowID", wfID, "workerID", action.GetWorkerId(), "actionName", action.GetName(), "actionImage", action.GetImage())
 
 	if err := w.containerManager.PullImage(ctx, action.GetImage()); err != nil {
-		return proto.State_STATE_RUNNING, errors.Wrap(err, "pull image")
+		return proto.State_STATE_RUNNING, nil, errors.Wrap(err, "pull image")
+	}
+
+	outputPath := w.outputPath(wfID, action)
+	if err := output.Create(outputPath); err != nil {
+		return proto.State_STATE_RUNNING, nil, errors.Wrap(err, "create output file")
 	}
+	defer os.Remove(outputPath)
 
-	id, err := w.containerManager.CreateContainer(ctx, action.Command, wfID, action, w.captureLogs, w.createPrivileged)
+	id, err := w.containerManager.CreateContainer(ctx, action.Command, wfID, withOutputs(action, outputPath, outputs), w.captureLogs, w.createPrivileged)
 	if err != nil {
-		return proto.State_STATE_RUNNING, errors.Wrap(err, "create container")
+		return proto.State_STATE_RUNNING, nil, errors.Wrap(err, "create container")
 	}
 
 	l.Info("container created", "containerID", id, "command", action.Command)
@@ -173,11 +210,11 @@ func (w *Worker) execute(ctx context.Context, wfID string, action *proto.Workflo
 
 	err = w.containerManager.StartContainer(timeCtx, id)
 	if err != nil {
-		return proto.State_STATE_RUNNING, errors.Wrap(err, "start container")
+		return proto.State_STATE_RUNNING, nil, errors.Wrap(err, "start container")
 	}
 
 	if w.captureLogs {
-		go w.logCapturer.CaptureLogs(ctx, id)
+		go w.logCapturer.CaptureLogs(withActionLogTags(ctx, wfID, action), id)
 	}
 
 	st, err := w.containerManager.WaitForContainer(timeCtx, id)
@@ -194,12 +231,16 @@ func (w *Worker) execute(ctx context.Context, wfID string, action *proto.Workflo
 	}()
 
 	if err != nil {
-		return st, errors.Wrap(err, "wait container")
+		return st, nil, errors.Wrap(err, "wait container")
 	}
 
 	if st == proto.State_STATE_SUCCESS {
 		l.Info("action container exited with success", "status", st)
-		return st, nil
+		published, err := output.ReadFile(outputPath)
+		if err != nil {
+			return proto.State_STATE_FAILED, nil, failure.WithReason(errors.Wrap(err, "read action outputs"), reasonInvalidOutput)
+		}
+		return st, published, nil
 	}
 
 	if st == proto.State_STATE_TIMEOUT && action.OnTimeout != nil {
@@ -216,7 +257,66 @@ func (w *Worker) execute(ctx context.Context, wfID string, action *proto.Workflo
 	}
 
 	l.Info("action container exited", "status", st)
-	return st, nil
+	if st == proto.State_STATE_FAILED {
+		code, err := w.containerManager.GetExitCode(ctx, id)
+		if err != nil {
+			l.Error(err, "get container exit code", "containerID", id)
+			return st, nil, nil
+		}
+		return st, nil, failure.WithExitCode(fmt.Errorf("action container exited with status %d", code), code)
+	}
+	return st, nil, nil
+}
+
+// outputPath returns the path of the file action publishes outputs to. The file resides in the
+// worker's data directory so it can be mounted into the action container.
+func (w *Worker) outputPath(wfID string, action *proto.WorkflowAction) string {
+	return filepath.Join(w.dataDir, wfID, "outputs", makeValidContainerName(action.GetTaskName()+"_"+action.GetName()))
+}
+
+// withOutputs returns a copy of action that publishes outputs to the file at path and receives
+// outputs as environment variables. Environment variables defined by the action take precedence
+// over outputs.
+func withOutputs(action *proto.WorkflowAction, path string, outputs map[string]map[string]string) *proto.WorkflowAction {
+	action = googleproto.Clone(action).(*proto.WorkflowAction)
+
+	var env []string
+	for k, v := range output.Env(outputs) {
+		env = append(env, k+"="+v)
+	}
+	sort.Strings(env)
+	action.Environment = append(append(env, action.GetEnvironment()...), output.EnvVar+"="+output.MountPath)
+	action.Volumes = append(action.Volumes, path+":"+output.MountPath)
+	return action
+}
+
+// shouldRetry determines if the attempt that finished with st and err should be retried according
+// to the action's retry policy.
+func shouldRetry(action *proto.WorkflowAction, attempt int64, st proto.State, err error) bool {
+	if attempt > action.GetRetries() {
+		return false
+	}
+	if err == nil {
+		err = fmt.Errorf("action finished with status %v", st)
+	}
+	if st == proto.State_STATE_TIMEOUT {
+		err = failure.WithReason(err, reasonTimeout)
+	}
+	return failure.Retryable(err, action.GetRetryOn())
+}
+
+// retryBackoff returns the delay before the attempt following attempt. The delay doubles for each
+// subsequent attempt up to maxRetryBackoff.
+func retryBackoff(action *proto.WorkflowAction, attempt int64) time.Duration {
+	if action.GetBackoff() >= int64(maxRetryBackoff/time.Second) {
+		return maxRetryBackoff
+	}
+
+	delay := time.Duration(action.GetBackoff()) * time.Second
+	for i := int64(1); i < attempt && delay > 0 && delay < maxRetryBackoff; i++ {
+		delay *= 2
+	}
+	return min(delay, maxRetryBackoff)
 }
 
 // executeReaction executes special case OnTimeout/OnFailure actions.
@@ -229,7 +329,7 @@ func (w *Worker) executeReaction(ctx context.Context, reaction string, cmd []str
 	l.Info("container created", "containerID", id, "actionStatus", reaction, "command", cmd)
 
 	if w.captureLogs {
-		go w.logCapturer.CaptureLogs(ctx, id)
+		go w.logCapturer.CaptureLogs(withActionLogTags(ctx, wfID, action), id)
 	}
 
 	st := make(chan proto.State)
@@ -245,6 +345,11 @@ func (w *Worker) executeReaction(ctx context.Context, reaction string, cmd []str
 
 // ProcessWorkflowActions gets all Workflow contexts and processes their actions.
 func (w *Worker) ProcessWorkflowActions(ctx context.Context) error {
+	if w.heartbeatInterval > 0 {
+		go w.sendHeartbeats(ctx)
+	}
+
+	reported := reportedStates{}
 	for {
 		l := w.logger.WithValues("workerID", w.workerID)
 		select {
@@ -252,7 +357,9 @@ func (w *Worker) ProcessWorkflowActions(ctx context.Context) error {
 			return nil
 		default:
 		}
-		res, err := w.tinkClient.GetWorkflowContexts(ctx, &proto.WorkflowContextRequest{WorkerId: w.workerID})
+		// Servers that don't support watching close the stream once the current contexts are sent
+		// in which case we fall back to polling every retryInterval.
+		res, err := w.tinkClient.GetWorkflowContexts(ctx, &proto.WorkflowContextRequest{WorkerId: w.workerID, Watch: true})
 		if err != nil {
 			l.Error(err, errGetWfContext)
 			<-time.After(w.retryInterval)
@@ -273,9 +380,16 @@ func (w *Worker) ProcessWorkflowActions(ctx context.Context) error {
 				break
 			}
 			wfID := wfContext.GetWorkflowId()
-			l = l.WithValues("workflowID", wfID)
+			l := l.WithValues("workflowID", wfID)
 			ctx := context.WithValue(ctx, loggingContextKey, l)
 
+			if wfContext.GetCurrentAction() == "" {
+				reported.reset(wfID)
+			} else if reported.contains(wfContext) {
+				// The context reflects a status we reported so we've already acted on it.
+				continue
+			}
+
 			actions, err := w.tinkClient.GetWorkflowActions(ctx, &proto.WorkflowActionsRequest{WorkflowId: wfID})
 			if err != nil {
 				l.Error(err, errGetWfActions)
@@ -292,7 +406,7 @@ func (w *Worker) ProcessWorkflowActions(ctx context.Context) error {
 				}
 			} else {
 				switch wfContext.GetCurrentActionState() {
-				case proto.State_STATE_SUCCESS:
+				case proto.State_STATE_SUCCESS, proto.State_STATE_SKIPPED:
 					if isLastAction(wfContext, actions) {
 						continue
 					}
@@ -311,92 +425,301 @@ func (w *Worker) ProcessWorkflowActions(ctx context.Context) error {
 				}
 			}
 
+			outputs := actionOutputs(actions.GetActionList())
+			if turn {
+				w.setRunningWorkflow(wfID)
+			}
 			for turn {
-				l.Info("starting action")
-				action := actions.GetActionList()[actionIndex]
-				l := l.WithValues(
-					"actionName", action.GetName(),
-					"taskName", action.GetTaskName(),
-				)
-				ctx := context.WithValue(ctx, loggingContextKey, l)
-				if wfContext.GetCurrentActionState() != proto.State_STATE_RUNNING {
-					actionStatus := &proto.WorkflowActionStatus{
-						WorkflowId:   wfID,
-						TaskName:     action.GetTaskName(),
-						ActionName:   action.GetName(),
-						ActionStatus: proto.State_STATE_RUNNING,
-						Seconds:      0,
-						Message:      "Started execution",
-						WorkerId:     action.GetWorkerId(),
-					}
-					w.reportActionStatus(ctx, l, actionStatus)
-					l.Info("sent action status", "status", actionStatus.ActionStatus, "duration", strconv.FormatInt(actionStatus.Seconds, 10))
-				}
-
-				// start executing the action
-				start := time.Now()
-				st, err := w.execute(ctx, wfID, action)
-				elapsed := time.Since(start)
-
-				actionStatus := &proto.WorkflowActionStatus{
-					WorkflowId: wfID,
-					TaskName:   action.GetTaskName(),
-					ActionName: action.GetName(),
-					Seconds:    int64(elapsed.Seconds()),
-					WorkerId:   action.GetWorkerId(),
-				}
-
-				if err != nil || st != proto.State_STATE_SUCCESS {
-					if st == proto.State_STATE_TIMEOUT {
-						actionStatus.ActionStatus = proto.State_STATE_TIMEOUT
-					} else {
-						actionStatus.ActionStatus = proto.State_STATE_FAILED
-					}
-					l = l.WithValues("actionStatus", actionStatus.ActionStatus.String())
-					l.Error(err, "execute workflow")
-					w.reportActionStatus(ctx, l, actionStatus)
+				group := actionGroup(actions.GetActionList(), actionIndex)
+				if !w.executeGroup(ctx, l, wfContext, actions.GetActionList(), group, outputs, reported) {
 					break
 				}
 
-				actionStatus.ActionStatus = proto.State_STATE_SUCCESS
-				actionStatus.Message = "finished execution successfully"
-				w.reportActionStatus(ctx, l, actionStatus)
-				l.Info("sent action status")
-
-				if len(actions.GetActionList()) == actionIndex+1 {
+				last := group[len(group)-1]
+				if len(actions.GetActionList()) == last+1 {
 					l.Info("reached to end of workflow")
 					break
 				}
 
-				nextAction := actions.GetActionList()[actionIndex+1]
+				nextAction := actions.GetActionList()[last+1]
 				if nextAction.GetWorkerId() != w.workerID {
 					l.Info(fmt.Sprintf(msgTurn, nextAction.GetWorkerId()))
 					turn = false
 				} else {
-					actionIndex++
+					actionIndex = last + 1
 				}
 			}
+			w.setRunningWorkflow("")
 		}
 		// sleep before asking for new workflows
 		<-time.After(w.retryInterval)
 	}
 }
 
+// executeGroup executes the actions identified by group concurrently and waits for them to
+// finish. outputs are the outputs published by earlier actions keyed by action name; outputs
+// published by the group are added once all its actions have finished. It returns true if all
+// actions succeeded.
+func (w *Worker) executeGroup(ctx context.Context, l logr.Logger, wfContext *proto.WorkflowContext, actions []*proto.WorkflowAction, group []int, outputs map[string]map[string]string, reported reportedStates) bool {
+	var mtx sync.Mutex
+	report := func(actionIndex int, state proto.State) {
+		mtx.Lock()
+		defer mtx.Unlock()
+		reported.add(wfContext.GetWorkflowId(), actionIndex, state)
+	}
+
+	succeeded := make([]bool, len(group))
+	published := make([]map[string]string, len(group))
+	if len(group) == 1 {
+		succeeded[0], published[0] = w.executeAction(ctx, l, wfContext, group[0], actions[group[0]], outputs, report)
+	} else {
+		l.Info("starting action group", "group", actions[group[0]].GetGroup(), "actions", len(group))
+		var wg sync.WaitGroup
+		for i, actionIndex := range group {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				succeeded[i], published[i] = w.executeAction(ctx, l, wfContext, actionIndex, actions[actionIndex], outputs, report)
+			}()
+		}
+		wg.Wait()
+	}
+
+	for i, actionIndex := range group {
+		if published[i] != nil {
+			outputs[actions[actionIndex].GetName()] = published[i]
+		}
+	}
+
+	return !slices.Contains(succeeded, false)
+}
+
+// executeAction executes the action at actionIndex, retrying according to its retry policy, and
+// reports its status. Actions whose condition evaluates to false are skipped. It returns true if
+// the action succeeded or was skipped, and the outputs published by the action.
+func (w *Worker) executeAction(ctx context.Context, l logr.Logger, wfContext *proto.WorkflowContext, actionIndex int, action *proto.WorkflowAction, outputs map[string]map[string]string, report func(int, proto.State)) (bool, map[string]string) {
+	wfID := wfContext.GetWorkflowId()
+
+	l.Info("starting action")
+	l = l.WithValues(
+		"actionName", action.GetName(),
+		"taskName", action.GetTaskName(),
+	)
+	ctx = context.WithValue(ctx, loggingContextKey, l)
+
+	if run, err := condition.Evaluate(action.GetWhen(), condition.Data{Outputs: outputs}); err != nil || !run {
+		actionStatus := &proto.WorkflowActionStatus{
+			WorkflowId:   wfID,
+			TaskName:     action.GetTaskName(),
+			ActionName:   action.GetName(),
+			ActionStatus: proto.State_STATE_SKIPPED,
+			Message:      "condition evaluated to false",
+			WorkerId:     action.GetWorkerId(),
+		}
+		if err != nil {
+			actionStatus.ActionStatus = proto.State_STATE_FAILED
+			actionStatus.Message = err.Error()
+			l.Error(err, "evaluate action condition")
+		}
+		w.reportActionStatus(ctx, l, actionStatus)
+		report(actionIndex, actionStatus.ActionStatus)
+		l.Info("sent action status", "status", actionStatus.ActionStatus)
+		return err == nil, nil
+	}
+
+	// An action that's already running is being resumed so its running status needn't be
+	// reported again.
+	resumed := int64(actionIndex) == wfContext.GetCurrentActionIndex() &&
+		wfContext.GetCurrentActionState() == proto.State_STATE_RUNNING
+
+	var (
+		st        proto.State
+		published map[string]string
+		err       error
+		elapsed   time.Duration
+		attempt   int64
+	)
+	for attempt = 1; ; attempt++ {
+		if attempt > 1 || !resumed {
+			actionStatus := &proto.WorkflowActionStatus{
+				WorkflowId:   wfID,
+				TaskName:     action.GetTaskName(),
+				ActionName:   action.GetName(),
+				ActionStatus: proto.State_STATE_RUNNING,
+				Seconds:      0,
+				Message:      "Started execution",
+				WorkerId:     action.GetWorkerId(),
+				Attempt:      attempt,
+			}
+			if attempt > 1 {
+				// The server uses the message to describe why the previous attempt failed.
+				actionStatus.Message = attemptFailureMessage(st, err)
+			}
+			w.reportActionStatus(ctx, l, actionStatus)
+			report(actionIndex, actionStatus.ActionStatus)
+			l.Info("sent action status", "status", actionStatus.ActionStatus, "duration", strconv.FormatInt(actionStatus.Seconds, 10), "attempt", attempt)
+		}
+
+		// start executing the action
+		start := time.Now()
+		st, published, err = w.execute(ctx, wfID, action, outputs)
+		elapsed = time.Since(start)
+
+		if (err == nil && st == proto.State_STATE_SUCCESS) || !shouldRetry(action, attempt, st, err) {
+			break
+		}
+
+		backoff := retryBackoff(action, attempt)
+		l.Info("action failed; retrying", "error", err, "status", st.String(), "attempt", attempt, "backoff", backoff.String())
+		select {
+		case <-ctx.Done():
+			return false, nil
+		case <-time.After(backoff):
+		}
+	}
+
+	actionStatus := &proto.WorkflowActionStatus{
+		WorkflowId: wfID,
+		TaskName:   action.GetTaskName(),
+		ActionName: action.GetName(),
+		Seconds:    int64(elapsed.Seconds()),
+		WorkerId:   action.GetWorkerId(),
+		Attempt:    attempt,
+	}
+
+	if err != nil || st != proto.State_STATE_SUCCESS {
+		if st == proto.State_STATE_TIMEOUT {
+			actionStatus.ActionStatus = proto.State_STATE_TIMEOUT
+		} else {
+			actionStatus.ActionStatus = proto.State_STATE_FAILED
+		}
+		actionStatus.Message = attemptFailureMessage(st, err)
+		l = l.WithValues("actionStatus", actionStatus.ActionStatus.String())
+		l.Error(err, "execute workflow")
+		w.reportActionStatus(ctx, l, actionStatus)
+		report(actionIndex, actionStatus.ActionStatus)
+		return false, nil
+	}
+
+	actionStatus.ActionStatus = proto.State_STATE_SUCCESS
+	actionStatus.Message = "finished execution successfully"
+	actionStatus.Outputs = published
+	w.reportActionStatus(ctx, l, actionStatus)
+	report(actionIndex, actionStatus.ActionStatus)
+	l.Info("sent action status")
+	return true, published
+}
+
+// actionOutputs returns the outputs published by actions that have already succeeded keyed by
+// action name.
+func actionOutputs(actions []*proto.WorkflowAction) map[string]map[string]string {
+	outputs := map[string]map[string]string{}
+	for _, action := range actions {
+		if len(action.GetOutputs()) > 0 {
+			outputs[action.GetName()] = action.GetOutputs()
+		}
+	}
+	return outputs
+}
+
+// actionGroup returns the indices of the actions executed concurrently with actions[i], including
+// i. Groups are formed by adjacent actions in the same task with the same non-empty group. The
+// worker doesn't know which actions in a group have already succeeded so an interrupted group is
+// executed again in full.
+func actionGroup(actions []*proto.WorkflowAction, i int) []int {
+	grouped := func(j int) bool {
+		return actions[i].GetGroup() != "" &&
+			actions[j].GetGroup() == actions[i].GetGroup() &&
+			actions[j].GetTaskName() == actions[i].GetTaskName()
+	}
+
+	start, end := i, i+1
+	for start > 0 && grouped(start-1) {
+		start--
+	}
+	for end < len(actions) && grouped(end) {
+		end++
+	}
+
+	group := make([]int, 0, end-start)
+	for j := start; j < end; j++ {
+		group = append(group, j)
+	}
+	return group
+}
+
+// attemptFailureMessage describes why an attempt at running an action failed.
+func attemptFailureMessage(st proto.State, err error) string {
+	if err != nil {
+		return err.Error()
+	}
+	return fmt.Sprintf("action finished with status %v", st)
+}
+
+// reportedStates records the action states a worker has reported for each workflow. Watching
+// servers push contexts for changes made by the worker's own reports; the worker has already
+// acted on these so they're skipped.
+type reportedStates map[string]map[reportedState]struct{}
+
+type reportedState struct {
+	actionIndex int64
+	state       proto.State
+}
+
+func (r reportedStates) add(wfID string, actionIndex int, state proto.State) {
+	if r[wfID] == nil {
+		r[wfID] = map[reportedState]struct{}{}
+	}
+	r[wfID][reportedState{actionIndex: int64(actionIndex), state: state}] = struct{}{}
+}
+
+func (r reportedStates) contains(wfContext *proto.WorkflowContext) bool {
+	_, ok := r[wfContext.GetWorkflowId()][reportedState{
+		actionIndex: wfContext.GetCurrentActionIndex(),
+		state:       wfContext.GetCurrentActionState(),
+	}]
+	return ok
+}
+
+// reset forgets the states reported for wfID, such as when a workflow is resumed.
+func (r reportedStates) reset(wfID string) {
+	delete(r, wfID)
+}
+
 func isLastAction(wfContext *proto.WorkflowContext, actions *proto.WorkflowActionList) bool {
 	return int(wfContext.GetCurrentActionIndex()) == len(actions.GetActionList())-1
 }
 
-// reportActionStatus reports the status of an action to the Tinkerbell server and retries forever on error.
+// reportActionStatus reports actionStatus to the server, retrying until it is accepted or
+// rejected. Rejections indicate the status no longer applies to the workflow so retrying
+// cannot succeed.
 func (w *Worker) reportActionStatus(ctx context.Context, l logr.Logger, actionStatus *proto.WorkflowActionStatus) {
 	for {
 		l.Info("reporting Action Status")
 		_, err := w.tinkClient.ReportActionStatus(ctx, actionStatus)
-		if err != nil {
-			l.Error(err, errReportActionStatus)
-			<-time.After(w.retryInterval)
+		if err == nil {
+			return
+		}
+		if isRejection(err) {
+			l.Error(err, errReportActionStatus, "code", status.Code(err).String())
+			return
+		}
 
-			continue
+		l.Error(err, errReportActionStatus)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(w.retryInterval):
 		}
-		return
 	}
 }
+
+// isRejection determines if err is the server rejecting a request as opposed to a transient
+// failure such as an update conflict.
+func isRejection(err error) bool {
+	switch status.Code(err) {
+	case codes.InvalidArgument, codes.NotFound, codes.FailedPrecondition, codes.PermissionDenied, codes.Unauthenticated:
+		return true
+	}
+	return false
+}
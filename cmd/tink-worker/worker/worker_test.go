@@ -0,0 +1,32 @@
+package worker
+
+import (
+	"testing"
+	"time"
+
+	"github.com/tinkerbell/tink/internal/proto"
+)
+
+func TestRetryBackoff(t *testing.T) {
+	cases := []struct {
+		Name    string
+		Backoff int64
+		Attempt int64
+		Expect  time.Duration
+	}{
+		{Name: "NoBackoff", Backoff: 0, Attempt: 3, Expect: 0},
+		{Name: "FirstAttempt", Backoff: 5, Attempt: 1, Expect: 5 * time.Second},
+		{Name: "Doubles", Backoff: 5, Attempt: 3, Expect: 20 * time.Second},
+		{Name: "CappedAfterManyAttempts", Backoff: 5, Attempt: 100, Expect: maxRetryBackoff},
+		{Name: "CappedLargeBackoff", Backoff: 1 << 62, Attempt: 1, Expect: maxRetryBackoff},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.Name, func(t *testing.T) {
+			delay := retryBackoff(&proto.WorkflowAction{Backoff: tc.Backoff}, tc.Attempt)
+			if delay != tc.Expect {
+				t.Fatalf("Expected: %v; Received: %v", tc.Expect, delay)
+			}
+		})
+	}
+}
@@ -52,6 +52,9 @@ func NewRootCommand(version string) *cobra.Command {
 				viper.GetString("tinkerbell-grpc-authority"),
 				viper.GetBool("tinkerbell-tls"),
 				viper.GetBool("tinkerbell-insecure-tls"),
+				client.WithRootCA(viper.GetString("tinkerbell-tls-ca-file")),
+				client.WithClientCertificate(viper.GetString("tinkerbell-tls-cert-file"), viper.GetString("tinkerbell-tls-key-file")),
+				client.WithBearerToken(viper.GetString("tinkerbell-token")),
 			)
 			if err != nil {
 				return err
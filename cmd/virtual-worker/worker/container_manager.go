@@ -74,6 +74,11 @@ func (m *fakeManager) WaitForFailedContainer(_ context.Context, id string, faile
 	failedActionStatus <- proto.State_STATE_SUCCESS
 }
 
+func (m *fakeManager) GetExitCode(_ context.Context, id string) (int, error) {
+	m.logger.Info("inspecting container", "containerID", id)
+	return 0, nil
+}
+
 func (m *fakeManager) RemoveContainer(_ context.Context, id string) error {
 	m.logger.Info("removing container", "containerID", id)
 	return nil
@@ -3,6 +3,7 @@ package agent
 import (
 	"context"
 	"errors"
+	"slices"
 	"sync"
 
 	"github.com/go-logr/logr"
@@ -13,8 +14,9 @@ import (
 // Agent is the core data structure for handling workflow execution on target nodes. It leverages
 // a Transport and a ContainerRuntime to retrieve workflows and execute actions.
 //
-// The agent runs a single workflow at a time. Concurrent requests to run workflows will have the
-// second workflow rejected with an event.WorkflowRejected event.
+// The agent runs up to MaxConcurrentWorkflows workflows at a time. Requests to run workflows
+// beyond the limit, or to run a workflow that is already running, are rejected with an
+// event.WorkflowRejected event.
 type Agent struct {
 	Log logr.Logger
 
@@ -28,12 +30,20 @@ type Agent struct {
 	// Runtime is the container runtime used to execute workflow actions.
 	Runtime ContainerRuntime
 
-	// sem ensure we handle a single workflow at a time.
+	// Heartbeat configures heartbeats sent to the server when Transport implements
+	// HeartbeatTransport.
+	Heartbeat HeartbeatConfig
+
+	// MaxConcurrentWorkflows is the maximum number of workflows executed concurrently. Defaults
+	// to 1.
+	MaxConcurrentWorkflows int
+
+	// sem limits the number of workflows executing concurrently.
 	sem chan struct{}
 
-	// executionContext tracks the currently executing workflow.
-	executionContext *executionContext
-	mtx              sync.RWMutex
+	// executionContexts tracks the currently executing workflows by workflow ID.
+	executionContexts map[string]*executionContext
+	mtx               sync.RWMutex
 }
 
 // Start finalizes the Agent configuration and starts the configured Transport so it is ready
@@ -59,9 +69,33 @@ func (agent *Agent) Start(ctx context.Context) error {
 
 	agent.Log = agent.Log.WithValues("agent_id", agent.ID)
 
-	// Initialize the semaphore and add a resource to it ensuring we can run 1 workflow at a time.
-	agent.sem = make(chan struct{}, 1)
-	agent.sem <- struct{}{}
+	if agent.MaxConcurrentWorkflows < 0 {
+		return errors.New("MaxConcurrentWorkflows field must not be negative")
+	}
+
+	maxWorkflows := max(agent.MaxConcurrentWorkflows, 1)
+
+	// Initialize the semaphore with a resource per workflow we can run concurrently.
+	agent.sem = make(chan struct{}, maxWorkflows)
+	for range maxWorkflows {
+		agent.sem <- struct{}{}
+	}
+	agent.executionContexts = map[string]*executionContext{}
+
+	if trnport, ok := agent.Transport.(HeartbeatTransport); ok && agent.Heartbeat.Interval >= 0 {
+		// Stop sending heartbeats once the transport stops.
+		ctx, cancel := context.WithCancel(ctx)
+		var wg sync.WaitGroup
+		wg.Add(1)
+		defer func() {
+			cancel()
+			wg.Wait()
+		}()
+		go func() {
+			defer wg.Done()
+			agent.sendHeartbeats(ctx, trnport)
+		}()
+	}
 
 	return agent.Transport.Start(ctx, agent.ID, agent)
 }
@@ -72,71 +106,108 @@ func (agent *Agent) HandleWorkflow(ctx context.Context, wflw workflow.Workflow,
 		agent.Log.Info("Agent must have Start() called before calling HandleWorkflow()")
 	}
 
+	if reason := agent.startWorkflow(ctx, wflw, events); reason != "" {
+		agent.reject(ctx, wflw, events, reason)
+	}
+}
+
+// startWorkflow launches a goroutine executing wflw. If wflw can't be started it returns the reason.
+func (agent *Agent) startWorkflow(ctx context.Context, wflw workflow.Workflow, events event.Recorder) string {
+	// Hold the lock while we acquire the semaphore and configure the execution context so we
+	// can't race with CancelWorkflow or a concurrent request for the same workflow.
+	agent.mtx.Lock()
+	defer agent.mtx.Unlock()
+
+	if _, running := agent.executionContexts[wflw.ID]; running {
+		return "workflow already in progress"
+	}
+
 	select {
 	case <-agent.sem:
-		// Ensure we configure the current workflow and cancellation func before we launch the
-		// goroutine to avoid a race with CancelWorkflow.
+	default:
+		return "maximum concurrent workflows in progress"
+	}
+
+	ctx, cancel := context.WithCancelCause(ctx)
+	agent.executionContexts[wflw.ID] = &executionContext{
+		Workflow: wflw,
+		Cancel:   cancel,
+	}
+
+	go func() {
+		agent.run(ctx, wflw, events)
+
+		// Remove the execution context after running so cancellation requests are no longer
+		// routed to it, and replenish the semaphore so we can pick up another workflow.
 		agent.mtx.Lock()
 		defer agent.mtx.Unlock()
+		delete(agent.executionContexts, wflw.ID)
+		cancel(nil)
+		agent.sem <- struct{}{}
+	}()
 
-		ctx, cancel := context.WithCancel(ctx)
-		agent.executionContext = &executionContext{
-			Workflow: wflw,
-			Cancel:   cancel,
-		}
-
-		go func() {
-			// Replenish the semaphore on exit so we can pick up another workflow.
-			defer func() { agent.sem <- struct{}{} }()
+	return ""
+}
 
-			agent.run(ctx, wflw, events)
+// reject records a WorkflowRejected event for wflw.
+func (agent *Agent) reject(ctx context.Context, wflw workflow.Workflow, events event.Recorder, message string) {
+	log := agent.Log.WithValues("workflow_id", wflw.ID)
 
-			// Nilify the execution context after running so cancellation requests are ignored.
-			agent.mtx.Lock()
-			defer agent.mtx.Unlock()
-			agent.executionContext = nil
-		}()
+	reject := event.WorkflowRejected{
+		ID:      wflw.ID,
+		Message: message,
+	}
 
-	default:
-		log := agent.Log.WithValues("workflow_id", wflw.ID)
+	if err := events.RecordEvent(ctx, reject); err != nil {
+		log.Error(err, "Failed to record workflow rejection event")
+		return
+	}
 
-		reject := event.WorkflowRejected{
-			ID:      wflw.ID,
-			Message: "workflow already in progress",
-		}
+	log.Info("Rejected workflow", "reason", message)
+}
 
-		if err := events.RecordEvent(ctx, reject); err != nil {
-			log.Error(err, "Failed to record workflow rejection event")
-			return
+// CancelWorkflow satisfies transport. Workflows the agent isn't running, typically because the
+// agent restarted, are reported canceled so the server needn't wait for them.
+func (agent *Agent) CancelWorkflow(ctx context.Context, workflowID string, events event.Recorder) {
+	agent.mtx.RLock()
+	execCtx, ok := agent.executionContexts[workflowID]
+	agent.mtx.RUnlock()
+
+	if !ok {
+		log := agent.Log.WithValues("workflow_id", workflowID)
+		log.Info("Workflow not running; recording cancellation")
+		if err := events.RecordEvent(ctx, event.WorkflowCanceled{ID: workflowID}); err != nil {
+			log.Error(err, "Record workflow canceled event")
 		}
-
-		log.Info("Workflow already executing; dropping request")
+		return
 	}
+
+	agent.Log.Info("Cancel workflow", "workflow_id", workflowID)
+	execCtx.Cancel(errWorkflowCanceled)
 }
 
-func (agent *Agent) CancelWorkflow(workflowID string) {
+// RunningWorkflows satisfies transport.WorkflowHandler.
+func (agent *Agent) RunningWorkflows() []string {
 	agent.mtx.RLock()
 	defer agent.mtx.RUnlock()
 
-	if agent.executionContext == nil {
-		agent.Log.Info("No workflow running; ignoring cancellation request", "workflow_id", workflowID)
-		return
+	if len(agent.executionContexts) == 0 {
+		return nil
 	}
 
-	if agent.executionContext.Workflow.ID != workflowID {
-		agent.Log.Info(
-			"Incorrect workflow ID in cancellation request; ignoring cancellation request",
-			"workflow_id", workflowID,
-			"running_workflow_id", agent.executionContext.Workflow.ID,
-		)
-		return
+	ids := make([]string, 0, len(agent.executionContexts))
+	for id := range agent.executionContexts {
+		ids = append(ids, id)
 	}
-
-	agent.Log.Info("Cancel workflow", "workflow_id", workflowID)
-	agent.executionContext.Cancel()
+	slices.Sort(ids)
+	return ids
 }
 
+// errWorkflowCanceled is the cause used when canceling a workflow's context in response to a
+// cancellation request. It lets us distinguish cancellation requests from the agent shutting down.
+var errWorkflowCanceled = errors.New("workflow canceled")
+
 type executionContext struct {
 	Workflow workflow.Workflow
-	Cancel   context.CancelFunc
+	Cancel   context.CancelCauseFunc
 }
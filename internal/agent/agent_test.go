@@ -2,7 +2,13 @@ package agent_test
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -12,6 +18,7 @@ import (
 	"github.com/tinkerbell/tink/internal/agent"
 	"github.com/tinkerbell/tink/internal/agent/event"
 	"github.com/tinkerbell/tink/internal/agent/failure"
+	"github.com/tinkerbell/tink/internal/agent/output"
 	"github.com/tinkerbell/tink/internal/agent/runtime"
 	"github.com/tinkerbell/tink/internal/agent/transport"
 	"github.com/tinkerbell/tink/internal/agent/workflow"
@@ -51,6 +58,17 @@ func TestAgent_InvalidStart(t *testing.T) {
 			},
 			Error: "Transport field must be set before calling Start()",
 		},
+		{
+			Name: "NegativeMaxConcurrentWorkflows",
+			Agent: &agent.Agent{
+				Log:                    logr.Discard(),
+				ID:                     "1234",
+				Transport:              transport.Noop(),
+				Runtime:                runtime.Noop(),
+				MaxConcurrentWorkflows: -1,
+			},
+			Error: "MaxConcurrentWorkflows field must not be negative",
+		},
 		{
 			Name: "InitializedCorrectly",
 			Agent: &agent.Agent{
@@ -163,6 +181,438 @@ func TestAgent_ConcurrentWorkflows(t *testing.T) {
 	}
 }
 
+// The goal of this test is to ensure the agent runs workflows concurrently up to its limit and
+// cancels them independently.
+func TestAgent_MaxConcurrentWorkflows(t *testing.T) {
+	logger := zapr.NewLogger(zap.Must(zap.NewDevelopment()))
+	trnport := transport.Noop()
+
+	// Started is used to indicate the runtime has received a workflow's action.
+	started := make(chan string)
+	rntime := agent.ContainerRuntimeMock{
+		RunFunc: func(ctx context.Context, a workflow.Action) error {
+			started <- a.ID
+			<-ctx.Done()
+			return ctx.Err()
+		},
+	}
+
+	canceled := make(chan string)
+	recorder := event.RecorderMock{
+		RecordEventFunc: func(_ context.Context, e event.Event) error {
+			if c, ok := e.(event.WorkflowCanceled); ok {
+				canceled <- c.ID
+			}
+			return nil
+		},
+	}
+
+	agnt := agent.Agent{
+		Log:                    logger,
+		Transport:              &trnport,
+		Runtime:                &rntime,
+		ID:                     "1234",
+		MaxConcurrentWorkflows: 2,
+	}
+	if err := agnt.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	newWorkflow := func(id string) workflow.Workflow {
+		return workflow.Workflow{
+			ID:      id,
+			Actions: []workflow.Action{{ID: id + "-action", Name: "name", Image: "image"}},
+		}
+	}
+
+	// Start 2 workflows and wait for both to run their action.
+	agnt.HandleWorkflow(ctx, newWorkflow("1"), &recorder)
+	agnt.HandleWorkflow(ctx, newWorkflow("2"), &recorder)
+	for range 2 {
+		select {
+		case <-started:
+		case <-ctx.Done():
+			t.Fatal(ctx.Err())
+		}
+	}
+
+	if diff := cmp.Diff([]string{"1", "2"}, agnt.RunningWorkflows()); diff != "" {
+		t.Fatal(diff)
+	}
+
+	// A third workflow exceeds the limit.
+	agnt.HandleWorkflow(ctx, newWorkflow("3"), &recorder)
+
+	calls := recorder.RecordEventCalls()
+	expectRejected := event.WorkflowRejected{ID: "3", Message: "maximum concurrent workflows in progress"}
+	if !cmp.Equal(expectRejected, calls[len(calls)-1].Event) {
+		t.Fatalf("Received unexpected event:\n%v", cmp.Diff(expectRejected, calls[len(calls)-1].Event))
+	}
+
+	// Canceling one workflow leaves the other running.
+	agnt.CancelWorkflow(ctx, "1", &recorder)
+	select {
+	case id := <-canceled:
+		if id != "1" {
+			t.Fatalf("Expected workflow 1 to be canceled; received %v", id)
+		}
+	case <-ctx.Done():
+		t.Fatal(ctx.Err())
+	}
+
+	// Wait for the canceled workflow to release its slot then start another.
+	for len(agnt.RunningWorkflows()) != 1 {
+		time.Sleep(time.Millisecond)
+	}
+	if diff := cmp.Diff([]string{"2"}, agnt.RunningWorkflows()); diff != "" {
+		t.Fatal(diff)
+	}
+
+	agnt.HandleWorkflow(ctx, newWorkflow("3"), &recorder)
+	select {
+	case id := <-started:
+		if id != "3-action" {
+			t.Fatalf("Expected workflow 3 to start; received action %v", id)
+		}
+	case <-ctx.Done():
+		t.Fatal(ctx.Err())
+	}
+}
+
+// The goal of this test is to ensure grouped actions run concurrently and the workflow proceeds
+// only once all actions in the group have finished.
+func TestAgent_ActionGroup(t *testing.T) {
+	cases := []struct {
+		Name       string
+		FailAction string
+		// ExpectRun is the sorted IDs of the actions expected to run.
+		ExpectRun []string
+	}{
+		{Name: "Succeeded", ExpectRun: []string{"reboot", "wipe-0", "wipe-1"}},
+		{Name: "Failed", FailAction: "wipe-0", ExpectRun: []string{"wipe-0", "wipe-1"}},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.Name, func(t *testing.T) {
+			wflw := workflow.Workflow{
+				ID: "1234",
+				Actions: []workflow.Action{
+					{ID: "wipe-0", Group: "wipe"},
+					{ID: "wipe-1", Group: "wipe"},
+					{ID: "reboot"},
+				},
+			}
+
+			// Grouped actions wait for each other so the test deadlocks unless they're concurrent.
+			var grouped sync.WaitGroup
+			grouped.Add(2)
+
+			var (
+				mtx sync.Mutex
+				ran []string
+			)
+			rntime := agent.ContainerRuntimeMock{
+				RunFunc: func(_ context.Context, a workflow.Action) error {
+					if a.Group != "" {
+						grouped.Done()
+						grouped.Wait()
+					}
+					// Ensure the failed action finishes first so the remaining action must be waited on.
+					if a.ID != tc.FailAction && tc.FailAction != "" {
+						time.Sleep(10 * time.Millisecond)
+					}
+
+					mtx.Lock()
+					defer mtx.Unlock()
+					ran = append(ran, a.ID)
+					if a.ID == tc.FailAction {
+						return errors.New("failed")
+					}
+					return nil
+				},
+			}
+
+			done := make(chan struct{})
+			recorder := event.RecorderMock{
+				RecordEventFunc: func(_ context.Context, e event.Event) error {
+					switch e := e.(type) {
+					case event.ActionSucceeded:
+						if e.ActionID == "reboot" || (tc.FailAction != "" && e.ActionID != tc.FailAction) {
+							close(done)
+						}
+					}
+					return nil
+				},
+			}
+
+			agnt := agent.Agent{
+				Log:       logr.Discard(),
+				Transport: transport.Noop(),
+				Runtime:   &rntime,
+				ID:        "1234",
+			}
+			if err := agnt.Start(context.Background()); err != nil {
+				t.Fatal(err)
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			agnt.HandleWorkflow(ctx, wflw, &recorder)
+
+			select {
+			case <-done:
+			case <-ctx.Done():
+				t.Fatal(ctx.Err())
+			}
+
+			// Wait for the workflow to finish so we know no further actions run.
+			for len(agnt.RunningWorkflows()) > 0 {
+				time.Sleep(time.Millisecond)
+			}
+
+			mtx.Lock()
+			defer mtx.Unlock()
+			slices.Sort(ran)
+			if diff := cmp.Diff(tc.ExpectRun, ran); diff != "" {
+				t.Fatal(diff)
+			}
+		})
+	}
+}
+
+// The goal of this test is to ensure canceling a running workflow records a WorkflowCanceled event.
+func TestAgent_CancelWorkflow(t *testing.T) {
+	logger := zapr.NewLogger(zap.Must(zap.NewDevelopment()))
+	trnport := transport.Noop()
+
+	wflw := workflow.Workflow{
+		ID: "1234",
+		Actions: []workflow.Action{
+			{
+				ID:    "1",
+				Name:  "name",
+				Image: "image",
+			},
+			{
+				ID:    "2",
+				Name:  "name",
+				Image: "image",
+			},
+		},
+	}
+
+	// Started is used to indicate the runtime has received the workflow.
+	started := make(chan struct{})
+	rntime := agent.ContainerRuntimeMock{
+		RunFunc: func(ctx context.Context, _ workflow.Action) error {
+			started <- struct{}{}
+			<-ctx.Done()
+			return ctx.Err()
+		},
+	}
+
+	canceled := make(chan struct{})
+	recorder := event.RecorderMock{
+		RecordEventFunc: func(_ context.Context, e event.Event) error {
+			if _, ok := e.(event.WorkflowCanceled); ok {
+				canceled <- struct{}{}
+			}
+			return nil
+		},
+	}
+
+	agnt := agent.Agent{
+		Log:       logger,
+		Transport: &trnport,
+		Runtime:   &rntime,
+		ID:        "1234",
+	}
+	if err := agnt.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	agnt.HandleWorkflow(ctx, wflw, &recorder)
+
+	select {
+	case <-started:
+	case <-ctx.Done():
+		t.Fatal(ctx.Err())
+	}
+
+	if diff := cmp.Diff([]string{wflw.ID}, agnt.RunningWorkflows()); diff != "" {
+		t.Fatal(diff)
+	}
+
+	agnt.CancelWorkflow(ctx, wflw.ID, &recorder)
+
+	select {
+	case <-canceled:
+	case <-ctx.Done():
+		t.Fatal(ctx.Err())
+	}
+
+	expect := []event.Event{
+		event.ActionStarted{WorkflowID: "1234", ActionID: "1", Attempt: 1},
+		event.WorkflowCanceled{ID: "1234"},
+	}
+	var received []event.Event
+	for _, call := range recorder.RecordEventCalls() {
+		received = append(received, call.Event)
+	}
+	if !cmp.Equal(expect, received) {
+		t.Fatalf("Did not received expected event set:\n%v", cmp.Diff(expect, received))
+	}
+}
+
+// The goal of this test is to ensure canceling a workflow the agent isn't running records a
+// WorkflowCanceled event so the server isn't left waiting.
+func TestAgent_CancelUnknownWorkflow(t *testing.T) {
+	logger := zapr.NewLogger(zap.Must(zap.NewDevelopment()))
+	trnport := transport.Noop()
+
+	recorder := event.RecorderMock{
+		RecordEventFunc: func(context.Context, event.Event) error {
+			return nil
+		},
+	}
+
+	agnt := agent.Agent{
+		Log:       logger,
+		Transport: &trnport,
+		Runtime:   &agent.ContainerRuntimeMock{},
+		ID:        "1234",
+	}
+	if err := agnt.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	agnt.CancelWorkflow(context.Background(), "unknown", &recorder)
+
+	expect := []event.Event{event.WorkflowCanceled{ID: "unknown"}}
+	var received []event.Event
+	for _, call := range recorder.RecordEventCalls() {
+		received = append(received, call.Event)
+	}
+	if !cmp.Equal(expect, received) {
+		t.Fatalf("Did not received expected event set:\n%v", cmp.Diff(expect, received))
+	}
+}
+
+// The goal of this test is to ensure failed actions are retried according to their retry policy.
+func TestAgent_RetryAction(t *testing.T) {
+	logger := zapr.NewLogger(zap.Must(zap.NewDevelopment()))
+
+	cases := []struct {
+		Name    string
+		RetryOn []string
+		Events  []event.Event
+	}{
+		{
+			Name: "RetryAll",
+			Events: []event.Event{
+				event.ActionStarted{WorkflowID: "1234", ActionID: "1", Attempt: 1},
+				event.ActionFailed{WorkflowID: "1234", ActionID: "1", Reason: "Flaky", Message: "flaky", Attempt: 1, Retrying: true},
+				event.ActionStarted{WorkflowID: "1234", ActionID: "1", Attempt: 2},
+				event.ActionSucceeded{WorkflowID: "1234", ActionID: "1"},
+			},
+		},
+		{
+			Name:    "RetryOnMatchingReason",
+			RetryOn: []string{"Flaky"},
+			Events: []event.Event{
+				event.ActionStarted{WorkflowID: "1234", ActionID: "1", Attempt: 1},
+				event.ActionFailed{WorkflowID: "1234", ActionID: "1", Reason: "Flaky", Message: "flaky", Attempt: 1, Retrying: true},
+				event.ActionStarted{WorkflowID: "1234", ActionID: "1", Attempt: 2},
+				event.ActionSucceeded{WorkflowID: "1234", ActionID: "1"},
+			},
+		},
+		{
+			Name:    "RetryOnMismatch",
+			RetryOn: []string{"1"},
+			Events: []event.Event{
+				event.ActionStarted{WorkflowID: "1234", ActionID: "1", Attempt: 1},
+				event.ActionFailed{WorkflowID: "1234", ActionID: "1", Reason: "Flaky", Message: "flaky", Attempt: 1},
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.Name, func(t *testing.T) {
+			wflw := workflow.Workflow{
+				ID: "1234",
+				Actions: []workflow.Action{
+					{
+						ID:      "1",
+						Name:    "name",
+						Image:   "image",
+						Retries: 2,
+						Backoff: time.Millisecond,
+						RetryOn: tc.RetryOn,
+					},
+				},
+			}
+
+			// Fail the first attempt only.
+			var attempts int
+			rntime := agent.ContainerRuntimeMock{
+				RunFunc: func(context.Context, workflow.Action) error {
+					attempts++
+					if attempts == 1 {
+						return failure.NewReason("flaky", "Flaky")
+					}
+					return nil
+				},
+			}
+
+			lastEventReceived := make(chan struct{})
+			recorder := event.RecorderMock{
+				RecordEventFunc: func(_ context.Context, e event.Event) error {
+					if cmp.Equal(e, tc.Events[len(tc.Events)-1]) {
+						lastEventReceived <- struct{}{}
+					}
+					return nil
+				},
+			}
+
+			agnt := agent.Agent{
+				Log:       logger,
+				Transport: transport.Noop(),
+				Runtime:   &rntime,
+				ID:        "1234",
+			}
+			if err := agnt.Start(context.Background()); err != nil {
+				t.Fatal(err)
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			agnt.HandleWorkflow(ctx, wflw, &recorder)
+
+			select {
+			case <-lastEventReceived:
+			case <-ctx.Done():
+				t.Fatal(ctx.Err())
+			}
+
+			var received []event.Event
+			for _, call := range recorder.RecordEventCalls() {
+				received = append(received, call.Event)
+			}
+			if !cmp.Equal(tc.Events, received) {
+				t.Fatalf("Did not received expected event set:\n%v", cmp.Diff(tc.Events, received))
+			}
+		})
+	}
+}
+
 func TestAgent_HandlingWorkflows(t *testing.T) {
 	logger := zapr.NewLogger(zap.Must(zap.NewDevelopment()))
 
@@ -198,6 +648,7 @@ func TestAgent_HandlingWorkflows(t *testing.T) {
 				event.ActionStarted{
 					WorkflowID: "1234",
 					ActionID:   "1",
+					Attempt:    1,
 				},
 				event.ActionSucceeded{
 					WorkflowID: "1234",
@@ -206,6 +657,43 @@ func TestAgent_HandlingWorkflows(t *testing.T) {
 				event.ActionStarted{
 					WorkflowID: "1234",
 					ActionID:   "2",
+					Attempt:    1,
+				},
+				event.ActionSucceeded{
+					WorkflowID: "1234",
+					ActionID:   "2",
+				},
+			},
+		},
+		{
+			Name: "ConditionalActionSkipped",
+			Workflow: workflow.Workflow{
+				ID: "1234",
+				Actions: []workflow.Action{
+					{
+						ID:    "1",
+						Name:  "action_1",
+						Image: "image_1",
+						When:  "gt 1 2",
+					},
+					{
+						ID:    "2",
+						Name:  "action_2",
+						Image: "image_2",
+						When:  "gt 2 1",
+					},
+				},
+			},
+			Errors: map[string]ReasonAndMessage{},
+			Events: []event.Event{
+				event.ActionSkipped{
+					WorkflowID: "1234",
+					ActionID:   "1",
+				},
+				event.ActionStarted{
+					WorkflowID: "1234",
+					ActionID:   "2",
+					Attempt:    1,
 				},
 				event.ActionSucceeded{
 					WorkflowID: "1234",
@@ -240,6 +728,7 @@ func TestAgent_HandlingWorkflows(t *testing.T) {
 				event.ActionStarted{
 					WorkflowID: "1234",
 					ActionID:   "1",
+					Attempt:    1,
 				},
 				event.ActionSucceeded{
 					WorkflowID: "1234",
@@ -248,12 +737,14 @@ func TestAgent_HandlingWorkflows(t *testing.T) {
 				event.ActionStarted{
 					WorkflowID: "1234",
 					ActionID:   "2",
+					Attempt:    1,
 				},
 				event.ActionFailed{
 					WorkflowID: "1234",
 					ActionID:   "2",
 					Reason:     "TestReason",
 					Message:    "test message",
+					Attempt:    1,
 				},
 			},
 		},
@@ -284,12 +775,14 @@ func TestAgent_HandlingWorkflows(t *testing.T) {
 				event.ActionStarted{
 					WorkflowID: "1234",
 					ActionID:   "1",
+					Attempt:    1,
 				},
 				event.ActionFailed{
 					WorkflowID: "1234",
 					ActionID:   "1",
 					Reason:     "TestReason",
 					Message:    "test message",
+					Attempt:    1,
 				},
 			},
 		},
@@ -325,6 +818,7 @@ func TestAgent_HandlingWorkflows(t *testing.T) {
 				event.ActionStarted{
 					WorkflowID: "1234",
 					ActionID:   "1",
+					Attempt:    1,
 				},
 				event.ActionSucceeded{
 					WorkflowID: "1234",
@@ -333,12 +827,14 @@ func TestAgent_HandlingWorkflows(t *testing.T) {
 				event.ActionStarted{
 					WorkflowID: "1234",
 					ActionID:   "2",
+					Attempt:    1,
 				},
 				event.ActionFailed{
 					WorkflowID: "1234",
 					ActionID:   "2",
 					Reason:     "TestReason",
 					Message:    "test message",
+					Attempt:    1,
 				},
 			},
 		},
@@ -364,12 +860,14 @@ func TestAgent_HandlingWorkflows(t *testing.T) {
 				event.ActionStarted{
 					WorkflowID: "1234",
 					ActionID:   "1",
+					Attempt:    1,
 				},
 				event.ActionFailed{
 					WorkflowID: "1234",
 					ActionID:   "1",
 					Reason:     "InvalidReason",
 					Message:    "test message",
+					Attempt:    1,
 				},
 			},
 		},
@@ -396,12 +894,14 @@ message`,
 				event.ActionStarted{
 					WorkflowID: "1234",
 					ActionID:   "1",
+					Attempt:    1,
 				},
 				event.ActionFailed{
 					WorkflowID: "1234",
 					ActionID:   "1",
 					Reason:     "TestReason",
 					Message:    `invalid \nmessage`,
+					Attempt:    1,
 				},
 			},
 		},
@@ -474,3 +974,230 @@ message`,
 		})
 	}
 }
+
+// loggingRuntime is a ContainerRuntime that writes output for each action.
+type loggingRuntime struct {
+	agent.ContainerRuntimeMock
+	Output string
+	Err    error
+}
+
+func (r *loggingRuntime) RunWithLogs(_ context.Context, _ workflow.Action, logs io.Writer) error {
+	if _, err := io.WriteString(logs, r.Output); err != nil {
+		return err
+	}
+	return r.Err
+}
+
+func TestAgent_ActionLogs(t *testing.T) {
+	logger := zapr.NewLogger(zap.Must(zap.NewDevelopment()))
+
+	wflw := workflow.Workflow{
+		ID:      "1234",
+		Actions: []workflow.Action{{ID: "1", Name: "name", Image: "image"}},
+	}
+
+	expect := []event.Event{
+		event.ActionStarted{WorkflowID: "1234", ActionID: "1", Attempt: 1},
+		event.ActionLog{WorkflowID: "1234", ActionID: "1", Attempt: 1, Data: []byte("boom\n")},
+		event.ActionFailed{
+			WorkflowID: "1234",
+			ActionID:   "1",
+			Attempt:    1,
+			Reason:     "Boom",
+			Message:    "boom",
+			LogTail:    "boom\n",
+		},
+	}
+
+	rntime := loggingRuntime{
+		Output: "boom\n",
+		Err:    failure.WithLogTail(failure.NewReason("boom", "Boom"), "boom\n"),
+	}
+
+	lastEventReceived := make(chan struct{})
+	recorder := event.RecorderMock{
+		RecordEventFunc: func(_ context.Context, e event.Event) error {
+			if cmp.Equal(e, expect[len(expect)-1]) {
+				lastEventReceived <- struct{}{}
+			}
+			return nil
+		},
+	}
+
+	agnt := agent.Agent{
+		Log:       logger,
+		Transport: transport.Noop(),
+		Runtime:   &rntime,
+		ID:        "1234",
+	}
+	if err := agnt.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	agnt.HandleWorkflow(ctx, wflw, &recorder)
+
+	select {
+	case <-lastEventReceived:
+	case <-ctx.Done():
+		t.Fatal(ctx.Err())
+	}
+
+	var received []event.Event
+	for _, call := range recorder.RecordEventCalls() {
+		received = append(received, call.Event)
+	}
+	if !cmp.Equal(expect, received) {
+		t.Fatalf("Did not received expected event set:\n%v", cmp.Diff(expect, received))
+	}
+}
+
+func TestAgent_ActionOutputs(t *testing.T) {
+	wflw := workflow.Workflow{
+		ID: "1234",
+		Actions: []workflow.Action{
+			{ID: "1", Name: "detect-disks"},
+			{ID: "2", Name: "raid", When: `gt (atoi (index .Outputs "detect-disks" "count")) 1`},
+			{ID: "3", Name: "single", When: `eq (index .Outputs "detect-disks" "count") "1"`},
+		},
+	}
+
+	var env map[string]string
+	rntime := agent.ContainerRuntimeMock{
+		RunFunc: func(_ context.Context, a workflow.Action) error {
+			if a.Env[output.EnvVar] != output.MountPath {
+				return fmt.Errorf("expected %v=%v; received %v", output.EnvVar, output.MountPath, a.Env)
+			}
+
+			// The output file is mounted by the last volume.
+			host, _, _ := strings.Cut(a.Volumes[len(a.Volumes)-1], ":")
+			switch a.ID {
+			case "1":
+				return os.WriteFile(host, []byte("count=2\ntype=nvme\n"), 0o666)
+			case "2":
+				env = a.Env
+			}
+			return nil
+		},
+	}
+
+	lastEventReceived := make(chan struct{})
+	recorder := event.RecorderMock{
+		RecordEventFunc: func(_ context.Context, e event.Event) error {
+			if skipped, ok := e.(event.ActionSkipped); ok && skipped.ActionID == "3" {
+				close(lastEventReceived)
+			}
+			return nil
+		},
+	}
+
+	agnt := agent.Agent{
+		Log:       logr.Discard(),
+		Transport: transport.Noop(),
+		Runtime:   &rntime,
+		ID:        "1234",
+	}
+	if err := agnt.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	agnt.HandleWorkflow(ctx, wflw, &recorder)
+
+	select {
+	case <-lastEventReceived:
+	case <-ctx.Done():
+		t.Fatal(ctx.Err())
+	}
+
+	expect := []event.Event{
+		event.ActionStarted{WorkflowID: "1234", ActionID: "1", Attempt: 1},
+		event.ActionSucceeded{WorkflowID: "1234", ActionID: "1", Outputs: map[string]string{"count": "2", "type": "nvme"}},
+		event.ActionStarted{WorkflowID: "1234", ActionID: "2", Attempt: 1},
+		event.ActionSucceeded{WorkflowID: "1234", ActionID: "2"},
+		event.ActionSkipped{WorkflowID: "1234", ActionID: "3"},
+	}
+	var received []event.Event
+	for _, call := range recorder.RecordEventCalls() {
+		received = append(received, call.Event)
+	}
+	if !cmp.Equal(expect, received) {
+		t.Fatalf("Did not received expected event set:\n%v", cmp.Diff(expect, received))
+	}
+
+	if env["TINKERBELL_OUTPUT_DETECT_DISKS_COUNT"] != "2" || env["TINKERBELL_OUTPUT_DETECT_DISKS_TYPE"] != "nvme" {
+		t.Fatalf("Expected outputs as environment variables; received %v", env)
+	}
+}
+
+// heartbeatTransport is a transport.Fake that blocks until cancelled and forwards heartbeats.
+type heartbeatTransport struct {
+	transport.Fake
+	heartbeats chan transport.Heartbeat
+}
+
+func (h heartbeatTransport) Start(ctx context.Context, _ string, _ transport.WorkflowHandler) error {
+	<-ctx.Done()
+	return nil
+}
+
+func (h heartbeatTransport) Heartbeat(_ context.Context, hb transport.Heartbeat) error {
+	h.heartbeats <- hb
+	return nil
+}
+
+func TestAgent_Heartbeat(t *testing.T) {
+	trnport := heartbeatTransport{
+		Fake:       transport.Noop(),
+		heartbeats: make(chan transport.Heartbeat),
+	}
+
+	agnt := agent.Agent{
+		Log:       logr.Discard(),
+		Transport: trnport,
+		Runtime:   runtime.Noop(),
+		ID:        "1234",
+		Heartbeat: agent.HeartbeatConfig{
+			Interval: time.Millisecond,
+			Version:  "v1.0.0",
+			Runtime:  "noop",
+		},
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	errs := make(chan error, 1)
+	go func() { errs <- agnt.Start(ctx) }()
+
+	// Receive more than one heartbeat to ensure they're sent periodically.
+	for range 2 {
+		select {
+		case hb := <-trnport.heartbeats:
+			if hb.AgentID != "1234" || hb.Version != "v1.0.0" || hb.Runtime != "noop" || len(hb.RunningWorkflows) != 0 {
+				t.Fatalf("Unexpected heartbeat: %+v", hb)
+			}
+		case <-ctx.Done():
+			t.Fatal(ctx.Err())
+		}
+	}
+
+	cancel()
+	// Drain heartbeats sent before the agent observed cancellation.
+	for {
+		select {
+		case <-trnport.heartbeats:
+			continue
+		case err := <-errs:
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+		break
+	}
+}
@@ -0,0 +1,85 @@
+// Package condition evaluates the conditions that determine if an action runs.
+//
+// A condition is a Go template pipeline, without delimiters, that must evaluate to a boolean.
+// Conditions are part of the template so Hardware data and template parameters are interpolated
+// when the template is rendered. Outputs published by earlier actions are only known at runtime
+// so they're referenced by the pipeline itself and resolved immediately before the action runs.
+// For example, the following condition runs an action only if more than one disk was detected.
+//
+//	gt {{ len .Hardware.StorageDevices }} 1
+//
+// Outputs of actions that haven't run, such as skipped actions, evaluate to empty strings. Actions
+// whose names aren't valid template identifiers, such as hyphenated names, are referenced using
+// index.
+//
+//	eq (index .Outputs "detect-disks" "type") "nvme"
+package condition
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+// funcs defines the custom functions available to conditions.
+var funcs = map[string]interface{}{
+	"contains":  strings.Contains,
+	"hasPrefix": strings.HasPrefix,
+	"hasSuffix": strings.HasSuffix,
+	"atoi":      strconv.Atoi,
+}
+
+// Data is the data conditions are evaluated against.
+type Data struct {
+	// Outputs are the outputs published by previously executed actions keyed by action name then
+	// output name. Outputs are exposed to conditions using a top level .Outputs key.
+	Outputs map[string]map[string]string
+}
+
+// Validate ensures expr is a syntactically valid condition. An empty expr is valid.
+func Validate(expr string) error {
+	if strings.TrimSpace(expr) == "" {
+		return nil
+	}
+	_, err := parse(expr)
+	return err
+}
+
+// Evaluate evaluates expr against data. An empty expr always evaluates to true. Outputs of an
+// action that hasn't run are treated as empty so conditions can test for skipped actions.
+// Referencing data other than .Outputs is an error so mistakes aren't mistaken for a false
+// condition.
+func Evaluate(expr string, data Data) (bool, error) {
+	if strings.TrimSpace(expr) == "" {
+		return true, nil
+	}
+
+	tpl, err := parse(expr)
+	if err != nil {
+		return false, err
+	}
+
+	var result bytes.Buffer
+	if err := tpl.Execute(&result, data); err != nil {
+		return false, fmt.Errorf("evaluate condition: %w", err)
+	}
+
+	ok, err := strconv.ParseBool(strings.TrimSpace(result.String()))
+	if err != nil {
+		return false, fmt.Errorf("condition must evaluate to a boolean: got %q", result.String())
+	}
+	return ok, nil
+}
+
+func parse(expr string) (*template.Template, error) {
+	tpl, err := template.New("when").
+		Option("missingkey=zero").
+		Funcs(funcs).
+		Parse("{{ " + expr + " }}")
+	if err != nil {
+		return nil, fmt.Errorf("parse condition: %w", err)
+	}
+	return tpl, nil
+}
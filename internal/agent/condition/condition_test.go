@@ -0,0 +1,110 @@
+package condition_test
+
+import (
+	"testing"
+
+	"github.com/tinkerbell/tink/internal/agent/condition"
+)
+
+func TestEvaluate(t *testing.T) {
+	outputs := map[string]map[string]string{
+		"detect-disks": {"count": "2", "type": "nvme"},
+	}
+
+	cases := []struct {
+		Name        string
+		Expr        string
+		Expect      bool
+		ExpectError bool
+	}{
+		{
+			Name:   "Empty",
+			Expr:   "",
+			Expect: true,
+		},
+		{
+			Name:   "RenderedTrue",
+			Expr:   "true",
+			Expect: true,
+		},
+		{
+			Name:   "RenderedFalse",
+			Expr:   "false",
+			Expect: false,
+		},
+		{
+			Name:   "RenderedComparison",
+			Expr:   "gt 2 1",
+			Expect: true,
+		},
+		{
+			Name:   "Output",
+			Expr:   `eq (index .Outputs "detect-disks" "type") "nvme"`,
+			Expect: true,
+		},
+		{
+			Name:   "NumericOutput",
+			Expr:   `gt (atoi (index .Outputs "detect-disks" "count")) 2`,
+			Expect: false,
+		},
+		{
+			Name:   "SkippedActionOutput",
+			Expr:   `eq .Outputs.partition.device "/dev/sda"`,
+			Expect: false,
+		},
+		{
+			Name:   "SkippedActionEmptyOutput",
+			Expr:   `eq .Outputs.partition.device ""`,
+			Expect: true,
+		},
+		{
+			Name:   "SkippedHyphenatedActionOutput",
+			Expr:   `eq (index .Outputs "create-partitions" "device") ""`,
+			Expect: true,
+		},
+		{
+			Name:        "UnknownData",
+			Expr:        `eq .Output.partition.device "/dev/sda"`,
+			ExpectError: true,
+		},
+		{
+			Name:        "NotBoolean",
+			Expr:        `index .Outputs "detect-disks" "type"`,
+			ExpectError: true,
+		},
+		{
+			Name:        "InvalidSyntax",
+			Expr:        "eq (",
+			ExpectError: true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.Name, func(t *testing.T) {
+			ok, err := condition.Evaluate(tc.Expr, condition.Data{Outputs: outputs})
+			if tc.ExpectError {
+				if err == nil {
+					t.Fatalf("Expected error; received result: %v", ok)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if ok != tc.Expect {
+				t.Fatalf("Expected: %v; Received: %v", tc.Expect, ok)
+			}
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	for _, expr := range []string{"", "true", `eq .Outputs.a.b "c"`} {
+		if err := condition.Validate(expr); err != nil {
+			t.Fatalf("Unexpected error for %q: %v", expr, err)
+		}
+	}
+	if err := condition.Validate("eq ("); err == nil {
+		t.Fatal("Expected error for invalid condition")
+	}
+}
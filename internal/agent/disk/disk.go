@@ -0,0 +1,2 @@
+// Package disk reports file system usage for heartbeats sent by agents and workers.
+package disk
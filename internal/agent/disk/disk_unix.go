@@ -0,0 +1,14 @@
+//go:build linux || darwin
+
+package disk
+
+import "syscall"
+
+// FreeBytes returns the space available to unprivileged users on the file system at path.
+func FreeBytes(path string) (uint64, error) {
+	var st syscall.Statfs_t
+	if err := syscall.Statfs(path, &st); err != nil {
+		return 0, err
+	}
+	return uint64(st.Bavail) * uint64(st.Bsize), nil //nolint:unconvert // Field types vary by platform.
+}
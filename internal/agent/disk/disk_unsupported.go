@@ -0,0 +1,10 @@
+//go:build !linux && !darwin
+
+package disk
+
+import "errors"
+
+// FreeBytes isn't supported on this platform.
+func FreeBytes(string) (uint64, error) {
+	return 0, errors.New("free disk space unsupported on this platform")
+}
@@ -6,12 +6,17 @@ const (
 	ActionStartedName   Name = "ActionStarted"
 	ActionSucceededName Name = "ActionSucceeded"
 	ActionFailedName    Name = "ActionFailed"
+	ActionSkippedName   Name = "ActionSkipped"
+	ActionLogName       Name = "ActionLog"
 )
 
 // ActionStarted occurs when an action begins running.
 type ActionStarted struct {
 	ActionID   string
 	WorkflowID string
+
+	// Attempt is the 1-based attempt number.
+	Attempt int
 }
 
 func (ActionStarted) GetName() Name {
@@ -19,13 +24,16 @@ func (ActionStarted) GetName() Name {
 }
 
 func (e ActionStarted) String() string {
-	return fmt.Sprintf("workflow=%v action=%v", e.WorkflowID, e.ActionID)
+	return fmt.Sprintf("workflow=%v action=%v attempt=%v", e.WorkflowID, e.ActionID, e.Attempt)
 }
 
 // ActionSucceeded occurs when an action successfully completes.
 type ActionSucceeded struct {
 	ActionID   string
 	WorkflowID string
+
+	// Outputs are the outputs published by the action.
+	Outputs map[string]string
 }
 
 func (ActionSucceeded) GetName() Name {
@@ -36,12 +44,35 @@ func (e ActionSucceeded) String() string {
 	return fmt.Sprintf("workflow=%v action=%v", e.WorkflowID, e.ActionID)
 }
 
+// ActionSkipped occurs when an action isn't run because its condition evaluated to false.
+type ActionSkipped struct {
+	ActionID   string
+	WorkflowID string
+}
+
+func (ActionSkipped) GetName() Name {
+	return ActionSkippedName
+}
+
+func (e ActionSkipped) String() string {
+	return fmt.Sprintf("workflow=%v action=%v", e.WorkflowID, e.ActionID)
+}
+
 // ActionFailed occurs when an action fails to complete.
 type ActionFailed struct {
 	ActionID   string
 	WorkflowID string
 	Reason     string
 	Message    string
+
+	// Attempt is the 1-based attempt number that failed.
+	Attempt int
+
+	// Retrying indicates the action will be retried.
+	Retrying bool
+
+	// LogTail is the tail of the action's output, if available.
+	LogTail string
 }
 
 func (ActionFailed) GetName() Name {
@@ -49,5 +80,26 @@ func (ActionFailed) GetName() Name {
 }
 
 func (e ActionFailed) String() string {
-	return fmt.Sprintf("workflow='%v' action='%v' reason='%v'", e.WorkflowID, e.ActionID, e.Reason)
+	return fmt.Sprintf("workflow='%v' action='%v' reason='%v' attempt='%v'", e.WorkflowID, e.ActionID, e.Reason, e.Attempt)
+}
+
+// ActionLog occurs when an action writes to stdout or stderr. The output of an action is the
+// concatenation of the Data of each ActionLog event for the action.
+type ActionLog struct {
+	ActionID   string
+	WorkflowID string
+
+	// Attempt is the 1-based attempt number that produced the output.
+	Attempt int
+
+	// Data is a chunk of the action's output.
+	Data []byte
+}
+
+func (ActionLog) GetName() Name {
+	return ActionLogName
+}
+
+func (e ActionLog) String() string {
+	return fmt.Sprintf("workflow=%v action=%v attempt=%v bytes=%v", e.WorkflowID, e.ActionID, e.Attempt, len(e.Data))
 }
@@ -15,3 +15,19 @@ func (WorkflowRejected) GetName() Name {
 func (e WorkflowRejected) String() string {
 	return e.Message
 }
+
+const WorkflowCanceledName Name = "WorkflowCanceled"
+
+// WorkflowCanceled is generated when a workflow has stopped executing in response to a
+// cancellation request.
+type WorkflowCanceled struct {
+	ID string
+}
+
+func (WorkflowCanceled) GetName() Name {
+	return WorkflowCanceledName
+}
+
+func (e WorkflowCanceled) String() string {
+	return "workflow canceled"
+}
@@ -12,5 +12,8 @@ package event
 func (ActionStarted) isEventFromThisPackage()   {}
 func (ActionSucceeded) isEventFromThisPackage() {}
 func (ActionFailed) isEventFromThisPackage()    {}
+func (ActionLog) isEventFromThisPackage()       {}
+func (ActionSkipped) isEventFromThisPackage()   {}
 
 func (WorkflowRejected) isEventFromThisPackage() {}
+func (WorkflowCanceled) isEventFromThisPackage() {}
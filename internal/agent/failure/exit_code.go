@@ -0,0 +1,41 @@
+package failure
+
+// ExitCode extracts an exit code from err. err has an exit code if it satisfies the exit code
+// interface:
+//
+//	interface {
+//		ExitCode() int
+//	}
+func ExitCode(err error) (int, bool) {
+	ec, ok := err.(interface {
+		ExitCode() int
+	})
+	if !ok {
+		return 0, false
+	}
+	return ec.ExitCode(), true
+}
+
+// WithExitCode decorates err with the exit code of the process that failed. The exit code can be
+// extracted using ExitCode(). Any reason associated with err is preserved.
+func WithExitCode(err error, code int) error {
+	return withExitCode{err, code}
+}
+
+type withExitCode struct {
+	error
+	code int
+}
+
+func (e withExitCode) ExitCode() int {
+	return e.code
+}
+
+func (e withExitCode) FailureReason() string {
+	reason, _ := Reason(e.error)
+	return reason
+}
+
+func (e withExitCode) Unwrap() error {
+	return e.error
+}
@@ -0,0 +1,43 @@
+package failure
+
+import "errors"
+
+// LogTail extracts the tail of an action's output from err. err, or an error it wraps, has a log
+// tail if it satisfies the log tail interface:
+//
+//	interface {
+//		LogTail() string
+//	}
+func LogTail(err error) (string, bool) {
+	var lt interface {
+		LogTail() string
+	}
+	if !errors.As(err, &lt) || lt.LogTail() == "" {
+		return "", false
+	}
+	return lt.LogTail(), true
+}
+
+// WithLogTail decorates err with the tail of the failed action's output. The tail can be
+// extracted using LogTail(). Any reason associated with err is preserved.
+func WithLogTail(err error, tail string) error {
+	return withLogTail{err, tail}
+}
+
+type withLogTail struct {
+	error
+	tail string
+}
+
+func (e withLogTail) LogTail() string {
+	return e.tail
+}
+
+func (e withLogTail) FailureReason() string {
+	reason, _ := Reason(e.error)
+	return reason
+}
+
+func (e withLogTail) Unwrap() error {
+	return e.error
+}
@@ -0,0 +1,25 @@
+package failure
+
+import "strconv"
+
+// Retryable returns true if err matches one of the retryOn conditions. Conditions are either exit
+// codes or failure reasons. An empty set of conditions matches all errors.
+func Retryable(err error, retryOn []string) bool {
+	if len(retryOn) == 0 {
+		return true
+	}
+
+	reason, hasReason := Reason(err)
+	code, hasCode := ExitCode(err)
+
+	for _, cond := range retryOn {
+		if hasReason && cond == reason {
+			return true
+		}
+		if hasCode && cond == strconv.Itoa(code) {
+			return true
+		}
+	}
+
+	return false
+}
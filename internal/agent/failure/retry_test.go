@@ -0,0 +1,61 @@
+package failure_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/tinkerbell/tink/internal/agent/failure"
+)
+
+func TestRetryable(t *testing.T) {
+	cases := []struct {
+		Name    string
+		Error   error
+		RetryOn []string
+		Expect  bool
+	}{
+		{
+			Name:   "NoConditions",
+			Error:  errors.New("failed"),
+			Expect: true,
+		},
+		{
+			Name:    "MatchingReason",
+			Error:   failure.NewReason("failed", "ImagePull"),
+			RetryOn: []string{"ImagePull"},
+			Expect:  true,
+		},
+		{
+			Name:    "MatchingExitCode",
+			Error:   failure.WithExitCode(errors.New("failed"), 3),
+			RetryOn: []string{"1", "3"},
+			Expect:  true,
+		},
+		{
+			Name:    "ReasonPreservedWithExitCode",
+			Error:   failure.WithExitCode(failure.NewReason("failed", "DiskBusy"), 1),
+			RetryOn: []string{"DiskBusy"},
+			Expect:  true,
+		},
+		{
+			Name:    "NoMatch",
+			Error:   failure.WithExitCode(failure.NewReason("failed", "DiskBusy"), 1),
+			RetryOn: []string{"2", "ImagePull"},
+			Expect:  false,
+		},
+		{
+			Name:    "NoReasonOrExitCode",
+			Error:   errors.New("failed"),
+			RetryOn: []string{"1"},
+			Expect:  false,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.Name, func(t *testing.T) {
+			if got := failure.Retryable(tc.Error, tc.RetryOn); got != tc.Expect {
+				t.Fatalf("Expected: %v; Received: %v", tc.Expect, got)
+			}
+		})
+	}
+}
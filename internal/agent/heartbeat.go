@@ -0,0 +1,69 @@
+package agent
+
+import (
+	"context"
+	"time"
+
+	"github.com/tinkerbell/tink/internal/agent/disk"
+	"github.com/tinkerbell/tink/internal/agent/transport"
+)
+
+// DefaultHeartbeatInterval is the interval at which heartbeats are sent when unspecified.
+const DefaultHeartbeatInterval = 30 * time.Second
+
+// HeartbeatConfig configures the heartbeats an agent sends when its Transport implements
+// HeartbeatTransport.
+type HeartbeatConfig struct {
+	// Interval is the interval at which heartbeats are sent. Defaults to DefaultHeartbeatInterval.
+	// Negative values disable heartbeats.
+	Interval time.Duration
+
+	// Version is the agent version reported in heartbeats.
+	Version string
+
+	// Runtime is the name of the Runtime reported in heartbeats.
+	Runtime string
+
+	// DiskPath is a path on the file system whose free space is reported in heartbeats. Defaults
+	// to the root directory.
+	DiskPath string
+}
+
+// sendHeartbeats sends a heartbeat every interval until ctx is cancelled. Failed heartbeats are
+// logged; the next heartbeat is sent regardless.
+func (agent *Agent) sendHeartbeats(ctx context.Context, trnport HeartbeatTransport) {
+	cfg := agent.Heartbeat
+	if cfg.Interval == 0 {
+		cfg.Interval = DefaultHeartbeatInterval
+	}
+	if cfg.DiskPath == "" {
+		cfg.DiskPath = "/"
+	}
+
+	ticker := time.NewTicker(cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		free, err := disk.FreeBytes(cfg.DiskPath)
+		if err != nil {
+			agent.Log.Info("Could not determine free disk space", "path", cfg.DiskPath, "error", err)
+		}
+
+		heartbeat := transport.Heartbeat{
+			AgentID:          agent.ID,
+			Version:          cfg.Version,
+			Runtime:          cfg.Runtime,
+			FreeDiskBytes:    free,
+			RunningWorkflows: agent.RunningWorkflows(),
+		}
+		if err := trnport.Heartbeat(ctx, heartbeat); err != nil && ctx.Err() == nil {
+			agent.Log.Info("Failed to send heartbeat", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
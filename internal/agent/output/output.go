@@ -0,0 +1,140 @@
+// Package output implements the mechanism actions use to publish outputs to later actions.
+//
+// Each action is provided a file, mounted at MountPath and identified by the EnvVar environment
+// variable, that it writes outputs to as name=value lines. Outputs published by an action are
+// reported with its status and exposed to later actions as environment variables and to action
+// conditions as .Outputs.<action name>.<output name>.
+package output
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+const (
+	// MountPath is the path actions write outputs to.
+	MountPath = "/tinkerbell/output"
+
+	// EnvVar is the environment variable containing MountPath.
+	EnvVar = "TINKERBELL_OUTPUT"
+
+	// MaxSize is the maximum number of bytes an action may write to its output file.
+	MaxSize = 64 << 10
+)
+
+// validNameRegex defines the regex for a valid output name. Names are restricted so they can be
+// referenced in conditions and environment variable names.
+var validNameRegex = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+// invalidEnvRegex matches characters that aren't valid in environment variable names.
+var invalidEnvRegex = regexp.MustCompile(`[^A-Z0-9_]`)
+
+// Create creates an empty output file at path, truncating it if it exists.
+func Create(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o666)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	// Actions may run as any user so the file must be world writable regardless of umask.
+	return f.Chmod(0o666)
+}
+
+// CreateTemp creates an empty output file in the default temporary directory and returns its
+// path. Consumers are responsible for removing the file.
+func CreateTemp() (string, error) {
+	f, err := os.CreateTemp("", "action-output-*")
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	if err := f.Chmod(0o666); err != nil {
+		os.Remove(f.Name())
+		return "", err
+	}
+	return f.Name(), nil
+}
+
+// ReadFile parses the outputs written to the output file at path.
+func ReadFile(path string) (map[string]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return Parse(f)
+}
+
+// Parse parses outputs from r. Outputs are name=value lines; blank lines are ignored and later
+// lines take precedence over earlier lines with the same name.
+func Parse(r io.Reader) (map[string]string, error) {
+	data, err := io.ReadAll(io.LimitReader(r, MaxSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > MaxSize {
+		return nil, fmt.Errorf("outputs exceed %d bytes", MaxSize)
+	}
+
+	var outputs map[string]string
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 4096), MaxSize)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimRight(scanner.Text(), "\r")
+		if strings.TrimSpace(text) == "" {
+			continue
+		}
+
+		name, value, ok := strings.Cut(text, "=")
+		if !ok || !validNameRegex.MatchString(name) {
+			return nil, fmt.Errorf("invalid output on line %d: expected name=value with name matching %v", line, validNameRegex)
+		}
+
+		if outputs == nil {
+			outputs = map[string]string{}
+		}
+		outputs[name] = value
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return outputs, nil
+}
+
+// Env returns environment variables exposing outputs, keyed by action name then output name, to
+// an action. Each output is exposed as TINKERBELL_OUTPUT_<ACTION>_<NAME> where ACTION and NAME
+// are upper cased with characters invalid in environment variable names replaced with
+// underscores.
+func Env(outputs map[string]map[string]string) map[string]string {
+	env := map[string]string{}
+	for action, published := range outputs {
+		for name, value := range published {
+			env[EnvName(action, name)] = value
+		}
+	}
+	return env
+}
+
+// EnvName returns the name of the environment variable exposing the output name published by
+// action.
+func EnvName(action, name string) string {
+	return EnvVar + "_" + toEnv(action) + "_" + toEnv(name)
+}
+
+func toEnv(s string) string {
+	return invalidEnvRegex.ReplaceAllString(strings.ToUpper(s), "_")
+}
@@ -0,0 +1,118 @@
+package output_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/tinkerbell/tink/internal/agent/output"
+)
+
+func TestParse(t *testing.T) {
+	cases := []struct {
+		Name        string
+		Data        string
+		Expect      map[string]string
+		ExpectError bool
+	}{
+		{
+			Name: "Empty",
+			Data: "",
+		},
+		{
+			Name:   "Outputs",
+			Data:   "disks=2\n\nraid_level=1\r\ndevice=/dev/md0=ok\n",
+			Expect: map[string]string{"disks": "2", "raid_level": "1", "device": "/dev/md0=ok"},
+		},
+		{
+			Name:   "LaterLinesTakePrecedence",
+			Data:   "disks=1\ndisks=2",
+			Expect: map[string]string{"disks": "2"},
+		},
+		{
+			Name:   "EmptyValue",
+			Data:   "disks=",
+			Expect: map[string]string{"disks": ""},
+		},
+		{
+			Name:        "MissingSeparator",
+			Data:        "disks",
+			ExpectError: true,
+		},
+		{
+			Name:        "InvalidName",
+			Data:        "disk-count=2",
+			ExpectError: true,
+		},
+		{
+			Name:        "TooLarge",
+			Data:        "disks=" + strings.Repeat("a", output.MaxSize),
+			ExpectError: true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.Name, func(t *testing.T) {
+			outputs, err := output.Parse(strings.NewReader(tc.Data))
+			if tc.ExpectError {
+				if err == nil {
+					t.Fatalf("Expected error; received outputs: %v", outputs)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if !cmp.Equal(tc.Expect, outputs) {
+				t.Fatal(cmp.Diff(tc.Expect, outputs))
+			}
+		})
+	}
+}
+
+func TestCreate(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "workflow", "action")
+	if err := output.Create(path); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte("disks=2\n"), 0o666); err != nil {
+		t.Fatal(err)
+	}
+
+	outputs, err := output.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !cmp.Equal(map[string]string{"disks": "2"}, outputs) {
+		t.Fatal(cmp.Diff(map[string]string{"disks": "2"}, outputs))
+	}
+
+	// Creating the file again discards outputs from a previous attempt.
+	if err := output.Create(path); err != nil {
+		t.Fatal(err)
+	}
+	outputs, err = output.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(outputs) != 0 {
+		t.Fatalf("Expected no outputs; received %v", outputs)
+	}
+}
+
+func TestEnv(t *testing.T) {
+	env := output.Env(map[string]map[string]string{
+		"detect-disks": {"count": "2"},
+		"raid":         {"device": "/dev/md0"},
+	})
+
+	expect := map[string]string{
+		"TINKERBELL_OUTPUT_DETECT_DISKS_COUNT": "2",
+		"TINKERBELL_OUTPUT_RAID_DEVICE":        "/dev/md0",
+	}
+	if !cmp.Equal(expect, env) {
+		t.Fatal(cmp.Diff(expect, env))
+	}
+}
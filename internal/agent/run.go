@@ -2,13 +2,20 @@ package agent
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"os"
 	"regexp"
+	"slices"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/go-logr/logr"
+	"github.com/tinkerbell/tink/internal/agent/condition"
 	"github.com/tinkerbell/tink/internal/agent/event"
 	"github.com/tinkerbell/tink/internal/agent/failure"
+	"github.com/tinkerbell/tink/internal/agent/output"
 	"github.com/tinkerbell/tink/internal/agent/workflow"
 )
 
@@ -18,71 +25,324 @@ const ReasonRuntimeError = "RuntimeError"
 // ReasonInvalid indicates a reason provided by the runtime was invalid.
 const ReasonInvalid = "InvalidReason"
 
+// ReasonInvalidCondition indicates an action's condition couldn't be evaluated.
+const ReasonInvalidCondition = "InvalidCondition"
+
+// ReasonInvalidOutput indicates the outputs written by an action couldn't be parsed.
+const ReasonInvalidOutput = "InvalidOutput"
+
 // validReasonRegex defines the regex for a valid action failure reason.
 var validReasonRegex = regexp.MustCompile(`^[a-zA-Z]+$`)
 
-// run executes the workflow using the runtime configured on agent.
+// run executes the workflow using the runtime configured on agent. Actions in the same group are
+// executed concurrently; the workflow proceeds once all actions in the group have finished.
 func (agent *Agent) run(ctx context.Context, wflw workflow.Workflow, events event.Recorder) {
 	log := agent.Log.WithValues("workflow_id", wflw.ID)
 
 	workflowStart := time.Now()
 	log.Info("Starting workflow")
 
-	for _, action := range wflw.Actions {
-		log := log.WithValues("action_id", action.ID, "action_name", action.Name)
+	// outputs are the outputs published by finished actions keyed by action name. Actions in a
+	// group only receive outputs published before the group started.
+	outputs := map[string]map[string]string{}
+
+	for _, group := range workflow.Groups(wflw.Actions) {
+		if canceled(ctx) {
+			agent.recordCanceled(ctx, log, wflw, events)
+			return
+		}
+
+		results := make([]actionResult, len(group))
+		published := make([]map[string]string, len(group))
+		if len(group) == 1 {
+			results[0], published[0] = agent.execute(ctx, log, wflw, group[0], outputs, events)
+		} else {
+			log.Info("Starting action group", "group", group[0].Group, "actions", len(group))
+			var wg sync.WaitGroup
+			for i, action := range group {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					results[i], published[i] = agent.execute(ctx, log, wflw, action, outputs, events)
+				}()
+			}
+			wg.Wait()
+		}
+
+		switch {
+		case slices.Contains(results, actionCanceled):
+			agent.recordCanceled(ctx, log, wflw, events)
+			return
+		case slices.ContainsFunc(results, func(r actionResult) bool { return r != actionSucceeded && r != actionSkipped }):
+			return
+		}
+
+		for i, action := range group {
+			if published[i] != nil {
+				outputs[action.Name] = published[i]
+			}
+		}
+	}
+
+	log.Info("Finished workflow", "duration", time.Since(workflowStart).String())
+}
+
+// maxRetryBackoff is the maximum delay between attempts of an action.
+const maxRetryBackoff = 10 * time.Minute
+
+// actionResult is the outcome of executing an action.
+type actionResult int
+
+const (
+	// actionSucceeded indicates the action succeeded.
+	actionSucceeded actionResult = iota
+
+	// actionFailed indicates the action failed, or its events couldn't be recorded, and the
+	// workflow must terminate.
+	actionFailed
 
-		actionStart := time.Now()
-		log.Info("Starting action")
+	// actionCanceled indicates the action was interrupted by workflow cancellation. Cancellation
+	// is yet to be recorded.
+	actionCanceled
+
+	// actionSkipped indicates the action's condition evaluated to false so it wasn't run.
+	actionSkipped
+)
+
+// execute runs action, retrying according to its retry policy, and records its events. Actions
+// whose condition evaluates to false are skipped. outputs are the outputs published by earlier
+// actions keyed by action name. It returns the outputs published by action if it succeeded.
+func (agent *Agent) execute(ctx context.Context, log logr.Logger, wflw workflow.Workflow, action workflow.Action, outputs map[string]map[string]string, events event.Recorder) (actionResult, map[string]string) {
+	log = log.WithValues("action_id", action.ID, "action_name", action.Name)
+
+	run, err := condition.Evaluate(action.When, condition.Data{Outputs: outputs})
+	if err != nil {
+		log.Info("Action condition invalid; terminating workflow", "error", err)
+		failed := event.ActionFailed{
+			ActionID:   action.ID,
+			WorkflowID: wflw.ID,
+			Reason:     ReasonInvalidCondition,
+			Message:    strings.ReplaceAll(err.Error(), "\n", `\n`),
+		}
+		if err := events.RecordEvent(ctx, failed); err != nil {
+			log.Error(err, "Record failed action event", "event", failed)
+		}
+		return actionFailed, nil
+	}
+	if !run {
+		log.Info("Skipping action; condition evaluated to false", "condition", action.When)
+		skipped := event.ActionSkipped{
+			ActionID:   action.ID,
+			WorkflowID: wflw.ID,
+		}
+		if err := events.RecordEvent(ctx, skipped); err != nil {
+			log.Error(err, "Record skipped action event")
+			return actionFailed, nil
+		}
+		return actionSkipped, nil
+	}
+
+	actionStart := time.Now()
+	log.Info("Starting action")
+
+	var published map[string]string
+	for attempt := 1; ; attempt++ {
+		log := log.WithValues("attempt", attempt)
 
 		started := event.ActionStarted{
 			ActionID:   action.ID,
 			WorkflowID: wflw.ID,
+			Attempt:    attempt,
 		}
 		if err := events.RecordEvent(ctx, started); err != nil {
 			log.Error(err, "Record action start event")
-			return
+			return actionFailed, nil
 		}
 
-		if err := agent.Runtime.Run(ctx, action); err != nil {
-			reason := extractReason(log, err)
+		var err error
+		published, err = agent.runAction(ctx, log, wflw, action, attempt, outputs, events)
+		if err == nil {
+			break
+		}
+
+		if canceled(ctx) {
+			log.Info("Action interrupted by workflow cancellation", "error", err)
+			return actionCanceled, nil
+		}
+
+		reason := extractReason(log, err)
+
+		// We consider newlines in the failure message invalid because it upsets formatting.
+		// The failure message is vital to easy debugability so we force the string into
+		// something we're happy with and communicate that.
+		message := strings.ReplaceAll(err.Error(), "\n", `\n`)
+
+		retrying := attempt <= action.Retries && failure.Retryable(err, action.RetryOn)
 
-			// We consider newlines in the failure message invalid because it upsets formatting.
-			// The failure message is vital to easy debugability so we force the string into
-			// something we're happy with and communicate that.
-			message := strings.ReplaceAll(err.Error(), "\n", `\n`)
+		logTail, _ := failure.LogTail(err)
 
+		failed := event.ActionFailed{
+			ActionID:   action.ID,
+			WorkflowID: wflw.ID,
+			Reason:     reason,
+			Message:    message,
+			Attempt:    attempt,
+			Retrying:   retrying,
+			LogTail:    logTail,
+		}
+
+		if !retrying {
 			log.Info("Action failed; terminating workflow",
 				"error", err,
 				"reason", reason,
 				"duration", time.Since(actionStart).String(),
 			)
-
-			failed := event.ActionFailed{
-				ActionID:   action.ID,
-				WorkflowID: wflw.ID,
-				Reason:     reason,
-				Message:    message,
-			}
 			if err := events.RecordEvent(ctx, failed); err != nil {
 				log.Error(err, "Record failed action event", "event", failed)
 			}
+			return actionFailed, nil
+		}
 
-			return
+		backoff := retryBackoff(action, attempt)
+		log.Info("Action failed; retrying",
+			"error", err,
+			"reason", reason,
+			"backoff", backoff.String(),
+		)
+		if err := events.RecordEvent(ctx, failed); err != nil {
+			log.Error(err, "Record failed action event", "event", failed)
+			return actionFailed, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			if canceled(ctx) {
+				return actionCanceled, nil
+			}
+			return actionFailed, nil
+		case <-time.After(backoff):
 		}
+	}
+
+	succeed := event.ActionSucceeded{
+		ActionID:   action.ID,
+		WorkflowID: wflw.ID,
+		Outputs:    published,
+	}
+	if err := events.RecordEvent(ctx, succeed); err != nil {
+		log.Error(err, "Record succeeded action event")
+		return actionFailed, nil
+	}
+
+	log.Info("Finished action", "duration", time.Since(actionStart).String())
+	return actionSucceeded, published
+}
+
+// runAction runs action using the agent's runtime and returns the outputs it published. The
+// action is provided a file to publish outputs to and receives outputs published by earlier
+// actions as environment variables.
+func (agent *Agent) runAction(ctx context.Context, log logr.Logger, wflw workflow.Workflow, action workflow.Action, attempt int, outputs map[string]map[string]string, events event.Recorder) (map[string]string, error) {
+	path, err := output.CreateTemp()
+	if err != nil {
+		return nil, fmt.Errorf("create action output file: %w", err)
+	}
+	defer os.Remove(path)
+
+	if err := agent.runWithLogs(ctx, log, wflw, withOutputs(action, path, outputs), attempt, events); err != nil {
+		return nil, err
+	}
+
+	published, err := output.ReadFile(path)
+	if err != nil {
+		return nil, failure.NewReason(fmt.Sprintf("read action outputs: %v", err), ReasonInvalidOutput)
+	}
+	return published, nil
+}
+
+// withOutputs returns a copy of action that publishes outputs to the file at path and receives
+// outputs as environment variables. Environment variables defined by the action take precedence
+// over outputs.
+func withOutputs(action workflow.Action, path string, outputs map[string]map[string]string) workflow.Action {
+	env := output.Env(outputs)
+	for k, v := range action.Env {
+		env[k] = v
+	}
+	env[output.EnvVar] = output.MountPath
+	action.Env = env
+
+	// Clip the volumes so actions running concurrently don't share the appended volume.
+	action.Volumes = append(slices.Clip(action.Volumes), path+":"+output.MountPath)
+	return action
+}
+
+// runWithLogs runs action using the agent's runtime. If the runtime can stream action output, the
+// output is recorded as ActionLog events.
+func (agent *Agent) runWithLogs(ctx context.Context, log logr.Logger, wflw workflow.Workflow, action workflow.Action, attempt int, events event.Recorder) error {
+	rntime, ok := agent.Runtime.(LoggingContainerRuntime)
+	if !ok {
+		return agent.Runtime.Run(ctx, action)
+	}
 
-		succeed := event.ActionSucceeded{
+	return rntime.RunWithLogs(ctx, action, &actionLogRecorder{
+		ctx:    ctx,
+		log:    log,
+		events: events,
+		template: event.ActionLog{
 			ActionID:   action.ID,
 			WorkflowID: wflw.ID,
-		}
-		if err := events.RecordEvent(ctx, succeed); err != nil {
-			log.Error(err, "Record succeeded action event")
-			return
-		}
+			Attempt:    attempt,
+		},
+	})
+}
 
-		log.Info("Finished action", "duration", time.Since(actionStart).String())
+// actionLogRecorder is an io.Writer that records each write as an ActionLog event. Failing to
+// record output must not fail the action so recording errors are logged and further output is
+// dropped.
+type actionLogRecorder struct {
+	ctx      context.Context
+	log      logr.Logger
+	events   event.Recorder
+	template event.ActionLog
+	failed   bool
+}
+
+func (r *actionLogRecorder) Write(p []byte) (int, error) {
+	if r.failed {
+		return len(p), nil
 	}
 
-	log.Info("Finished workflow", "duration", time.Since(workflowStart).String())
+	evnt := r.template
+	evnt.Data = append([]byte(nil), p...)
+	if err := r.events.RecordEvent(r.ctx, evnt); err != nil {
+		r.log.Error(err, "Record action log event; dropping further output")
+		r.failed = true
+	}
+	return len(p), nil
+}
+
+// retryBackoff returns the delay before the attempt following attempt. The delay doubles for each
+// subsequent attempt up to maxRetryBackoff.
+func retryBackoff(action workflow.Action, attempt int) time.Duration {
+	delay := action.Backoff
+	for i := 1; i < attempt && delay > 0 && delay < maxRetryBackoff; i++ {
+		delay *= 2
+	}
+	return min(delay, maxRetryBackoff)
+}
+
+// canceled returns true if ctx was canceled because of a workflow cancellation request.
+func canceled(ctx context.Context) bool {
+	return errors.Is(context.Cause(ctx), errWorkflowCanceled)
+}
+
+// recordCanceled records a WorkflowCanceled event. The workflow context is canceled so the event
+// is recorded with a context that isn't.
+func (agent *Agent) recordCanceled(ctx context.Context, log logr.Logger, wflw workflow.Workflow, events event.Recorder) {
+	log.Info("Workflow canceled")
+	canceled := event.WorkflowCanceled{ID: wflw.ID}
+	if err := events.RecordEvent(context.WithoutCancel(ctx), canceled); err != nil {
+		log.Error(err, "Record workflow canceled event")
+	}
 }
 
 func extractReason(log logr.Logger, err error) string {
@@ -2,6 +2,7 @@ package agent
 
 import (
 	"context"
+	"io"
 
 	"github.com/tinkerbell/tink/internal/agent/workflow"
 )
@@ -18,3 +19,13 @@ type ContainerRuntime interface {
 	// be the error message and the reason should be provided as defined in failure.Reason().
 	Run(context.Context, workflow.Action) error
 }
+
+// LoggingContainerRuntime is a ContainerRuntime capable of streaming action output. The agent
+// records output written to logs as event.ActionLog events.
+type LoggingContainerRuntime interface {
+	ContainerRuntime
+
+	// RunWithLogs executes the action as Run does and writes the action's stdout and stderr to
+	// logs.
+	RunWithLogs(_ context.Context, _ workflow.Action, logs io.Writer) error
+}
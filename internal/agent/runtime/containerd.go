@@ -0,0 +1,353 @@
+package runtime
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+
+	retry "github.com/avast/retry-go"
+	"github.com/containerd/containerd"
+	"github.com/containerd/containerd/cio"
+	"github.com/containerd/containerd/namespaces"
+	"github.com/containerd/containerd/oci"
+	"github.com/distribution/reference"
+	"github.com/go-logr/logr"
+	"github.com/opencontainers/runtime-spec/specs-go"
+	"github.com/tinkerbell/tink/internal/agent"
+	"github.com/tinkerbell/tink/internal/agent/failure"
+	"github.com/tinkerbell/tink/internal/agent/runtime/internal"
+	"github.com/tinkerbell/tink/internal/agent/workflow"
+)
+
+var _ agent.LoggingContainerRuntime = &Containerd{}
+
+// DefaultContainerdAddress is the default address of the containerd socket.
+const DefaultContainerdAddress = "/run/containerd/containerd.sock"
+
+// DefaultContainerdNamespace is the default containerd namespace actions are launched in.
+const DefaultContainerdNamespace = "tinkerbell"
+
+// stopTimeout is the time a container is given to exit after being signaled before it's killed.
+const stopTimeout = 5 * time.Second
+
+// Containerd is a containerd runtime that satisfies agent.ContainerRuntime.
+//
+// containerd doesn't provide container networking so actions are always launched in the host
+// network namespace.
+type Containerd struct {
+	log    logr.Logger
+	client *containerd.Client
+
+	// namespace is the containerd namespace images, containers and snapshots are created in.
+	namespace string
+
+	// logTailSize is the number of bytes of action output attached to failures.
+	logTailSize int
+
+	// privileged launches action containers in privileged mode granting access to host devices.
+	privileged bool
+}
+
+// Run satisfies agent.ContainerRuntime.
+func (c *Containerd) Run(ctx context.Context, a workflow.Action) error {
+	return c.RunWithLogs(ctx, a, io.Discard)
+}
+
+// RunWithLogs satisfies agent.LoggingContainerRuntime. The tail of the action's output is
+// retained and attached to the returned error when the action fails; see failure.LogTail().
+func (c *Containerd) RunWithLogs(ctx context.Context, a workflow.Action, logs io.Writer) error {
+	ctx = namespaces.WithNamespace(ctx, c.namespace)
+
+	// containerd, unlike Docker, requires fully qualified image references.
+	ref, err := reference.ParseDockerRef(a.Image)
+	if err != nil {
+		return fmt.Errorf("containerd: parse image: %w", err)
+	}
+
+	var img containerd.Image
+	pullImage := func() error {
+		img, err = c.client.Pull(ctx, ref.String(), containerd.WithPullUnpack)
+		if err != nil {
+			return fmt.Errorf("containerd: %w", err)
+		}
+		return nil
+	}
+
+	err = retry.Do(pullImage, retry.Attempts(5), retry.DelayType(retry.BackOffDelay))
+	if err != nil {
+		return err
+	}
+
+	failureFiles, err := internal.NewFailureFiles()
+	if err != nil {
+		return fmt.Errorf("create action failure files: %w", err)
+	}
+	defer failureFiles.Close()
+
+	specOpts, err := c.toSpecOpts(img, a, failureFiles)
+	if err != nil {
+		return fmt.Errorf("containerd: %w", err)
+	}
+
+	containerName := toContainerName(a.ID)
+
+	cntr, err := c.client.NewContainer(
+		ctx,
+		containerName,
+		containerd.WithNewSnapshot(containerName, img),
+		containerd.WithNewSpec(specOpts...),
+	)
+	if err != nil {
+		return fmt.Errorf("containerd: %w", err)
+	}
+
+	// Always try to remove the container and its snapshot on exit. We can't use the context
+	// passed to Run() as it may have been cancelled so we use Background() instead.
+	cleanupCtx := namespaces.WithNamespace(context.Background(), c.namespace)
+	defer func() {
+		if err := cntr.Delete(cleanupCtx, containerd.WithSnapshotCleanup); err != nil {
+			c.log.Info("Couldn't remove container", "container_name", containerName, "error", err)
+		}
+	}()
+
+	tail := internal.NewRingBuffer(c.logTailSize)
+	output := io.MultiWriter(tail, logs)
+
+	task, err := cntr.NewTask(ctx, cio.NewCreator(cio.WithStreams(nil, output, output)))
+	if err != nil {
+		return fmt.Errorf("containerd: %w", err)
+	}
+	defer func() {
+		if _, err := task.Delete(cleanupCtx, containerd.WithProcessKill); err != nil {
+			c.log.Info("Couldn't remove task", "container_name", containerName, "error", err)
+		}
+	}()
+
+	// Wait before starting the task so we can't miss its exit.
+	exit, err := task.Wait(cleanupCtx)
+	if err != nil {
+		return fmt.Errorf("containerd: %w", err)
+	}
+
+	if err := task.Start(ctx); err != nil {
+		return fmt.Errorf("containerd: %w", err)
+	}
+
+	select {
+	case status := <-exit:
+		code, _, err := status.Result()
+		if err != nil {
+			return fmt.Errorf("containerd: %w", err)
+		}
+
+		outputDone := make(chan struct{})
+		go func() {
+			defer close(outputDone)
+			task.IO().Wait()
+		}()
+		select {
+		case <-outputDone:
+		case <-time.After(outputDrainTimeout):
+			c.log.Info("Timed out reading container output", "container_name", containerName)
+		}
+
+		if code == 0 {
+			return nil
+		}
+		return failure.WithExitCode(
+			failure.WithLogTail(failureFiles.ToError(), tail.String()),
+			int(code),
+		)
+
+	case <-ctx.Done():
+		if err := task.Kill(cleanupCtx, syscall.SIGTERM); err != nil {
+			c.log.Info("Failed to gracefully stop container", "error", err)
+		}
+		select {
+		case <-exit:
+		case <-time.After(stopTimeout):
+			// The deferred task deletion kills the container.
+			c.log.Info("Timed out stopping container", "container_name", containerName)
+		}
+		return fmt.Errorf("containerd: %w", ctx.Err())
+	}
+}
+
+// toSpecOpts builds the OCI spec options for running a as a container from img.
+func (c *Containerd) toSpecOpts(img containerd.Image, a workflow.Action, ff *internal.FailureFiles) ([]oci.SpecOpts, error) {
+	mounts := []specs.Mount{
+		toBindMount(ff.ReasonPath(), ReasonMountPath, false),
+		toBindMount(ff.MessagePath(), MessageMountPath, false),
+	}
+	for _, v := range a.Volumes {
+		m, err := parseVolume(v)
+		if err != nil {
+			return nil, err
+		}
+		mounts = append(mounts, m)
+	}
+
+	// The Tink Action Cmd property is modeled as being the command launched in the container
+	// and overrides the image's entrypoint and command. When only args are specified they're
+	// passed to the image's entrypoint, consistent with the Docker runtime.
+	imageConfig := oci.WithImageConfig(img)
+	if a.Cmd == "" && len(a.Args) > 0 {
+		imageConfig = oci.WithImageConfigArgs(img, a.Args)
+	}
+
+	opts := []oci.SpecOpts{
+		imageConfig,
+		oci.WithEnv(toDockerEnv(a.Env)),
+		oci.WithMounts(mounts),
+		oci.WithHostNamespace(specs.NetworkNamespace),
+		oci.WithHostHostsFile,
+		oci.WithHostResolvconf,
+	}
+
+	if a.Cmd != "" {
+		opts = append(opts, oci.WithProcessArgs(append([]string{a.Cmd}, a.Args...)...))
+	}
+
+	switch a.NetworkNamespace {
+	case "", "host":
+	default:
+		return nil, fmt.Errorf("unsupported network namespace: %v", a.NetworkNamespace)
+	}
+
+	switch a.PIDNamespace {
+	case "":
+	case "host":
+		opts = append(opts, oci.WithHostNamespace(specs.PIDNamespace))
+	default:
+		return nil, fmt.Errorf("unsupported pid namespace: %v", a.PIDNamespace)
+	}
+
+	if c.privileged {
+		opts = append(opts, oci.WithPrivileged, oci.WithAllDevicesAllowed, oci.WithHostDevices)
+	}
+
+	return opts, nil
+}
+
+var errNamedVolume = errors.New("named volumes are unsupported; source must be an absolute host path")
+
+// parseVolume parses a volume of the form HOST-DIR:CONTAINER-DIR[:OPTIONS] into a bind mount.
+// Named volumes are a Docker concept and aren't supported.
+func parseVolume(v string) (specs.Mount, error) {
+	parts := strings.Split(v, ":")
+	if len(parts) < 2 || len(parts) > 3 {
+		return specs.Mount{}, fmt.Errorf("invalid volume: %v", v)
+	}
+
+	if !filepath.IsAbs(parts[0]) {
+		return specs.Mount{}, fmt.Errorf("invalid volume: %v: %w", v, errNamedVolume)
+	}
+
+	var readOnly bool
+	if len(parts) == 3 {
+		switch parts[2] {
+		case "ro":
+			readOnly = true
+		case "rw":
+		default:
+			return specs.Mount{}, fmt.Errorf("invalid volume: %v: unsupported options %q", v, parts[2])
+		}
+	}
+
+	return toBindMount(parts[0], parts[1], readOnly), nil
+}
+
+func toBindMount(src, dst string, readOnly bool) specs.Mount {
+	mode := "rw"
+	if readOnly {
+		mode = "ro"
+	}
+	return specs.Mount{
+		Type:        "bind",
+		Source:      src,
+		Destination: dst,
+		Options:     []string{"rbind", mode},
+	}
+}
+
+// NewContainerd creates a new Containerd instance connected to the containerd socket at address.
+func NewContainerd(address string, opts ...ContainerdOption) (*Containerd, error) {
+	o := &Containerd{
+		log:         logr.Discard(),
+		namespace:   DefaultContainerdNamespace,
+		logTailSize: defaultLogTailSize,
+	}
+
+	for _, fn := range opts {
+		fn(o)
+	}
+
+	if o.client == nil {
+		var err error
+		o.client, err = containerd.New(address)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return o, nil
+}
+
+// ContainerdOption defines optional configuration for a Containerd instance.
+type ContainerdOption func(*Containerd)
+
+// WithContainerdLogger returns an option to configure the logger on a Containerd instance.
+func WithContainerdLogger(log logr.Logger) ContainerdOption {
+	return func(o *Containerd) {
+		if log.GetSink() == nil {
+			return
+		}
+		o.log = log
+	}
+}
+
+// WithContainerdClient returns an option to configure a containerd client on a Containerd
+// instance.
+func WithContainerdClient(clnt *containerd.Client) ContainerdOption {
+	return func(o *Containerd) {
+		if clnt == nil {
+			return
+		}
+		o.client = clnt
+	}
+}
+
+// WithContainerdNamespace returns an option to configure the containerd namespace actions are
+// launched in.
+func WithContainerdNamespace(namespace string) ContainerdOption {
+	return func(o *Containerd) {
+		if namespace == "" {
+			return
+		}
+		o.namespace = namespace
+	}
+}
+
+// WithContainerdLogTailSize returns an option to configure the number of bytes of action output
+// attached to action failures.
+func WithContainerdLogTailSize(size int) ContainerdOption {
+	return func(o *Containerd) {
+		if size < 0 {
+			return
+		}
+		o.logTailSize = size
+	}
+}
+
+// WithContainerdPrivileged returns an option to launch action containers in privileged mode.
+// Privileged containers can access host devices such as disks under /dev.
+func WithContainerdPrivileged(privileged bool) ContainerdOption {
+	return func(o *Containerd) {
+		o.privileged = privileged
+	}
+}
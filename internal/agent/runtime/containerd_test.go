@@ -0,0 +1,125 @@
+package runtime_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/go-logr/zerologr"
+	"github.com/rs/zerolog"
+	"github.com/tinkerbell/tink/internal/agent/failure"
+	"github.com/tinkerbell/tink/internal/agent/runtime"
+	"github.com/tinkerbell/tink/internal/agent/workflow"
+)
+
+// newContainerd creates a Containerd runtime skipping the test when containerd isn't available.
+// containerd typically requires root so, unlike Docker, it's rarely available in development
+// environments.
+func newContainerd(t *testing.T) *runtime.Containerd {
+	t.Helper()
+
+	if _, err := os.Stat(runtime.DefaultContainerdAddress); err != nil {
+		t.Skipf("containerd unavailable: %v", err)
+	}
+
+	logger := zerolog.New(zerolog.ConsoleWriter{Out: os.Stderr})
+	rt, err := runtime.NewContainerd(
+		runtime.DefaultContainerdAddress,
+		runtime.WithContainerdLogger(zerologr.New(&logger)),
+	)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	return rt
+}
+
+func TestContainerdFailureMessages(t *testing.T) {
+	for _, tc := range []struct {
+		Name          string
+		Action        workflow.Action
+		ExpectReason  string
+		ExpectMessage string
+		ExpectCode    int
+	}{
+		{
+			Name: "FailureReasonAndMessage",
+			Action: workflow.Action{
+				ID:    "foobar",
+				Image: "alpine",
+				Args:  command{Message: "failure message", Reason: "FailureReason", Code: 3}.Build(),
+			},
+			ExpectReason:  "FailureReason",
+			ExpectMessage: "failure message",
+			ExpectCode:    3,
+		},
+		{
+			Name: "NoError",
+			Action: workflow.Action{
+				ID:    "foobar",
+				Image: "alpine",
+				Cmd:   "true",
+			},
+		},
+	} {
+		t.Run(tc.Name, func(t *testing.T) {
+			rt := newContainerd(t)
+
+			err := rt.Run(context.Background(), tc.Action)
+
+			switch {
+			case tc.ExpectMessage == "" && err != nil:
+				t.Fatalf("Received unexpected error: %v", err)
+			case tc.ExpectMessage != "" && err == nil:
+				t.Fatal("Expected error but received none")
+			case tc.ExpectMessage != "" && tc.ExpectMessage != err.Error():
+				t.Fatalf("Expected: %v; Received: %v", tc.ExpectMessage, err)
+			}
+
+			if reason, _ := failure.Reason(err); reason != tc.ExpectReason {
+				t.Fatalf("Expected reason: %v; Received: %v", tc.ExpectReason, reason)
+			}
+
+			if code, _ := failure.ExitCode(err); code != tc.ExpectCode {
+				t.Fatalf("Expected exit code: %v; Received: %v", tc.ExpectCode, code)
+			}
+		})
+	}
+}
+
+func TestContainerdContextTimeout(t *testing.T) {
+	rt := newContainerd(t)
+
+	action := workflow.Action{
+		ID:    "foobar",
+		Image: "alpine",
+		Args:  command{Sleep: 30}.Build(),
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	err := rt.Run(ctx, action)
+
+	if err == nil {
+		t.Fatal("Expected error but received none")
+	}
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Expect: %v; Received: %v", context.DeadlineExceeded, err)
+	}
+}
+
+func TestContainerdNamedVolume(t *testing.T) {
+	rt := newContainerd(t)
+
+	action := workflow.Action{
+		ID:      "foobar",
+		Image:   "alpine",
+		Volumes: []string{"shared_volume:/data"},
+	}
+
+	if err := rt.Run(context.Background(), action); err == nil {
+		t.Fatal("Expected error but received none")
+	}
+}
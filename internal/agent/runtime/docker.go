@@ -5,30 +5,52 @@ import (
 	"fmt"
 	"io"
 	"regexp"
+	"time"
 
 	retry "github.com/avast/retry-go"
 	"github.com/docker/docker/api/types/container"
 	"github.com/docker/docker/api/types/image"
 	"github.com/docker/docker/api/types/mount"
 	"github.com/docker/docker/client"
+	"github.com/docker/docker/pkg/stdcopy"
 	"github.com/go-logr/logr"
 	"github.com/tinkerbell/tink/internal/agent"
+	"github.com/tinkerbell/tink/internal/agent/failure"
 	"github.com/tinkerbell/tink/internal/agent/runtime/internal"
 	"github.com/tinkerbell/tink/internal/agent/workflow"
 	"github.com/tinkerbell/tink/internal/ptr"
 	"k8s.io/apimachinery/pkg/util/rand"
 )
 
-var _ agent.ContainerRuntime = &Docker{}
+var _ agent.LoggingContainerRuntime = &Docker{}
+
+// defaultLogTailSize is the default number of bytes of action output retained for failures.
+const defaultLogTailSize = 4 << 10
+
+// outputDrainTimeout is the maximum time to wait for container output to be read after the
+// container exits.
+const outputDrainTimeout = 5 * time.Second
 
 // Docker is a docker runtime that satisfies agent.ContainerRuntime.
 type Docker struct {
 	log    logr.Logger
 	client *client.Client
+
+	// logTailSize is the number of bytes of action output attached to failures.
+	logTailSize int
+
+	// privileged launches action containers in privileged mode granting access to host devices.
+	privileged bool
 }
 
 // Run satisfies agent.ContainerRuntime.
 func (d *Docker) Run(ctx context.Context, a workflow.Action) error {
+	return d.RunWithLogs(ctx, a, io.Discard)
+}
+
+// RunWithLogs satisfies agent.LoggingContainerRuntime. The tail of the action's output is
+// retained and attached to the returned error when the action fails; see failure.LogTail().
+func (d *Docker) RunWithLogs(ctx context.Context, a workflow.Action, logs io.Writer) error {
 	pullImage := func() error {
 		// We need the image to be available before we can create a container.
 		img, err := d.client.ImagePull(ctx, a.Image, image.PullOptions{})
@@ -51,7 +73,6 @@ func (d *Docker) Run(ctx context.Context, a workflow.Action) error {
 		return err
 	}
 
-	// TODO: Support all the other things on the action such as volumes.
 	cfg := container.Config{
 		Image: a.Image,
 		Env:   toDockerEnv(a.Env),
@@ -64,6 +85,10 @@ func (d *Docker) Run(ctx context.Context, a workflow.Action) error {
 	defer failureFiles.Close()
 
 	hostCfg := container.HostConfig{
+		Privileged:  d.privileged,
+		Binds:       a.Volumes,
+		NetworkMode: container.NetworkMode(a.NetworkNamespace),
+		PidMode:     container.PidMode(a.PIDNamespace),
 		Mounts: []mount.Mount{
 			{
 				Type:   mount.TypeBind,
@@ -90,8 +115,6 @@ func (d *Docker) Run(ctx context.Context, a workflow.Action) error {
 		cfg.Cmd = append(cfg.Cmd, a.Args...)
 	}
 
-	// TODO: Figure out container logging. We probably want to save it somewhere for debugability.
-
 	create, err := d.client.ContainerCreate(ctx, &cfg, &hostCfg, nil, nil, containerName)
 	if err != nil {
 		return fmt.Errorf("docker: %w", err)
@@ -111,6 +134,28 @@ func (d *Docker) Run(ctx context.Context, a workflow.Action) error {
 		}
 	}()
 
+	// Attach before starting the container so no output is missed.
+	attach, err := d.client.ContainerAttach(ctx, create.ID, container.AttachOptions{
+		Stream: true,
+		Stdout: true,
+		Stderr: true,
+	})
+	if err != nil {
+		return fmt.Errorf("docker: %w", err)
+	}
+	defer attach.Close()
+
+	tail := internal.NewRingBuffer(d.logTailSize)
+	output := io.MultiWriter(tail, logs)
+	outputDone := make(chan struct{})
+	go func() {
+		defer close(outputDone)
+		// Containers are created without a TTY so stdout and stderr are multiplexed.
+		if _, err := stdcopy.StdCopy(output, output, attach.Reader); err != nil {
+			d.log.Info("Failed reading container output", "container_name", containerName, "error", err)
+		}
+	}()
+
 	// Issue the wait with a 'next-exit' condition so we can await a response originating from
 	// ContainerStart().
 	waitBody, waitErr := d.client.ContainerWait(ctx, create.ID, container.WaitConditionNextExit)
@@ -121,10 +166,19 @@ func (d *Docker) Run(ctx context.Context, a workflow.Action) error {
 
 	select {
 	case result := <-waitBody:
+		select {
+		case <-outputDone:
+		case <-time.After(outputDrainTimeout):
+			d.log.Info("Timed out reading container output", "container_name", containerName)
+		}
+
 		if result.StatusCode == 0 {
 			return nil
 		}
-		return failureFiles.ToError()
+		return failure.WithExitCode(
+			failure.WithLogTail(failureFiles.ToError(), tail.String()),
+			int(result.StatusCode),
+		)
 
 	case err := <-waitErr:
 		return fmt.Errorf("docker: %w", err)
@@ -164,7 +218,8 @@ func toDockerEnv(env map[string]string) []string {
 // NewDocker creates a new Docker instance.
 func NewDocker(opts ...DockerOption) (*Docker, error) {
 	o := &Docker{
-		log: logr.Discard(),
+		log:         logr.Discard(),
+		logTailSize: defaultLogTailSize,
 	}
 
 	var err error
@@ -202,3 +257,22 @@ func WithClient(clnt *client.Client) DockerOption {
 		o.client = clnt
 	}
 }
+
+// WithLogTailSize returns an option to configure the number of bytes of action output attached
+// to action failures.
+func WithLogTailSize(size int) DockerOption {
+	return func(o *Docker) {
+		if size < 0 {
+			return
+		}
+		o.logTailSize = size
+	}
+}
+
+// WithPrivileged returns an option to launch action containers in privileged mode. Privileged
+// containers can access host devices such as disks under /dev.
+func WithPrivileged(privileged bool) DockerOption {
+	return func(o *Docker) {
+		o.privileged = privileged
+	}
+}
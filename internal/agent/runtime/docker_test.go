@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
@@ -161,6 +162,38 @@ func TestDockerContextTimeout(t *testing.T) {
 	}
 }
 
+func TestDockerHostConfig(t *testing.T) {
+	logger := zerolog.New(zerolog.ConsoleWriter{Out: os.Stderr})
+	rt, err := runtime.NewDocker(runtime.WithLogger(zerologr.New(&logger)), runtime.WithPrivileged(true))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	dir := t.TempDir()
+
+	// The shell is only process 1 when launched in its own PID namespace and mounting requires
+	// privileges.
+	action := workflow.Action{
+		ID:           "foobar",
+		Image:        "alpine",
+		Volumes:      []string{dir + ":/data"},
+		PIDNamespace: "host",
+		Args: []string{"sh", "-c", strings.Join([]string{
+			"test $$ -ne 1",
+			"mount -t tmpfs none /mnt",
+			"touch /data/written",
+		}, " && ")},
+	}
+
+	if err := rt.Run(context.Background(), action); err != nil {
+		t.Fatalf("Received unexpected error: %v", err)
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, "written")); err != nil {
+		t.Fatalf("Expected action to write to volume: %v", err)
+	}
+}
+
 type command struct {
 	// Reason is the reason to write to /tinkerbell/failure-reason
 	Reason string
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"fmt"
 	"os"
+	"path/filepath"
 	"strings"
 
 	"github.com/tinkerbell/tink/internal/agent/failure"
@@ -28,6 +29,34 @@ func NewFailureFiles() (*FailureFiles, error) {
 	}, nil
 }
 
+// NewFailureFilesAt creates a new FailureFiles instance using files at the specified paths,
+// truncating them if they exist. It's intended for runtimes that can't mount files. Consumers
+// are responsible for calling FailureFiles.Close().
+func NewFailureFilesAt(reasonPath, messagePath string) (*FailureFiles, error) {
+	var files []*os.File
+	for _, path := range []string{reasonPath, messagePath} {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			return nil, err
+		}
+
+		f, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR|os.O_TRUNC, 0o666)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, f)
+
+		// Actions may run as any user so the files must be world writable regardless of umask.
+		if err := f.Chmod(0o666); err != nil {
+			return nil, err
+		}
+	}
+
+	return &FailureFiles{
+		reason:  files[0],
+		message: files[1],
+	}, nil
+}
+
 // FailureFiles provides mountable files for runtimes that can be used to extract
 // a reason and message from actions.
 type FailureFiles struct {
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/tinkerbell/tink/internal/agent/failure"
@@ -82,3 +83,42 @@ func TestFailureFiles(t *testing.T) {
 		t.Fatalf("Expected not exists path error, received '%v'", err)
 	}
 }
+
+func TestFailureFilesAt(t *testing.T) {
+	dir := t.TempDir()
+	reasonPath := filepath.Join(dir, "tinkerbell", "failure-reason")
+	messagePath := filepath.Join(dir, "tinkerbell", "failure-message")
+
+	// Stale content from a previous action must be discarded.
+	if err := os.MkdirAll(filepath.Dir(reasonPath), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(reasonPath, []byte("Stale"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	ff, err := internal.NewFailureFilesAt(reasonPath, messagePath)
+	if err != nil {
+		t.Fatalf("Could not create failure files: %v", err)
+	}
+
+	if ff.ReasonPath() != reasonPath || ff.MessagePath() != messagePath {
+		t.Fatalf("Unexpected paths: %v, %v", ff.ReasonPath(), ff.MessagePath())
+	}
+
+	if reason, err := ff.Reason(); err != nil || reason != "" {
+		t.Fatalf("Expected empty reason; Received: %q (%v)", reason, err)
+	}
+
+	if err := os.WriteFile(messagePath, []byte("my special message\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if message, err := ff.Message(); err != nil || message != "my special message" {
+		t.Fatalf("Expected: my special message; Received: %q (%v)", message, err)
+	}
+
+	ff.Close()
+	if _, err := os.Stat(reasonPath); !os.IsNotExist(err) {
+		t.Fatalf("Expected reason file to be removed: %v", err)
+	}
+}
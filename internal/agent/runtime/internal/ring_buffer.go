@@ -0,0 +1,59 @@
+package internal
+
+import "sync"
+
+// RingBuffer is an io.Writer that retains the last Size bytes written to it. It is safe for
+// concurrent use.
+type RingBuffer struct {
+	mu   sync.Mutex
+	buf  []byte
+	pos  int
+	full bool
+}
+
+// NewRingBuffer creates a RingBuffer that retains up to size bytes.
+func NewRingBuffer(size int) *RingBuffer {
+	return &RingBuffer{buf: make([]byte, size)}
+}
+
+// Write satisfies io.Writer. It never fails.
+func (r *RingBuffer) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	n := len(p)
+	if len(r.buf) == 0 {
+		return n, nil
+	}
+
+	// Only the trailing bytes of large writes can be retained.
+	if len(p) >= len(r.buf) {
+		copy(r.buf, p[len(p)-len(r.buf):])
+		r.pos = 0
+		r.full = true
+		return n, nil
+	}
+
+	copied := copy(r.buf[r.pos:], p)
+	if copied < len(p) {
+		copy(r.buf, p[copied:])
+		r.full = true
+	}
+	r.pos = (r.pos + len(p)) % len(r.buf)
+	if r.pos == 0 {
+		r.full = true
+	}
+
+	return n, nil
+}
+
+// String returns the retained bytes in the order they were written.
+func (r *RingBuffer) String() string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !r.full {
+		return string(r.buf[:r.pos])
+	}
+	return string(r.buf[r.pos:]) + string(r.buf[:r.pos])
+}
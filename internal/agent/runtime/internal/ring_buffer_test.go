@@ -0,0 +1,39 @@
+package internal_test
+
+import (
+	"testing"
+
+	"github.com/tinkerbell/tink/internal/agent/runtime/internal"
+)
+
+func TestRingBuffer(t *testing.T) {
+	cases := []struct {
+		name   string
+		size   int
+		writes []string
+		want   string
+	}{
+		{name: "Empty", size: 4},
+		{name: "Partial", size: 8, writes: []string{"abc", "de"}, want: "abcde"},
+		{name: "Exact", size: 4, writes: []string{"ab", "cd"}, want: "abcd"},
+		{name: "Wrapped", size: 4, writes: []string{"abc", "def"}, want: "cdef"},
+		{name: "WrappedTwice", size: 4, writes: []string{"abc", "def", "gh", "i"}, want: "fghi"},
+		{name: "LargeWrite", size: 4, writes: []string{"ab", "cdefghij"}, want: "ghij"},
+		{name: "ZeroSize", size: 0, writes: []string{"abc"}},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			rb := internal.NewRingBuffer(tc.size)
+			for _, w := range tc.writes {
+				n, err := rb.Write([]byte(w))
+				if err != nil || n != len(w) {
+					t.Fatalf("Write(%q) = %v, %v", w, n, err)
+				}
+			}
+			if got := rb.String(); got != tc.want {
+				t.Fatalf("expected %q, got %q", tc.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,459 @@
+package runtime
+
+import (
+	"archive/tar"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	goruntime "runtime"
+	"strings"
+	"sync"
+	"time"
+
+	retry "github.com/avast/retry-go"
+	"github.com/go-logr/logr"
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/opencontainers/runtime-spec/specs-go"
+	"github.com/tinkerbell/tink/internal/agent"
+	"github.com/tinkerbell/tink/internal/agent/failure"
+	"github.com/tinkerbell/tink/internal/agent/runtime/internal"
+	"github.com/tinkerbell/tink/internal/agent/workflow"
+)
+
+var _ agent.LoggingContainerRuntime = &Process{}
+
+// DefaultProcessImageDir is the default directory where the Process runtime stores image root
+// filesystems.
+const DefaultProcessImageDir = "/var/lib/tink-agent/images"
+
+// defaultPath is used to find commands when neither the image nor the action define PATH.
+const defaultPath = "/usr/local/sbin:/usr/local/bin:/usr/sbin:/usr/bin:/sbin:/bin"
+
+// hostMounts are host file systems made available to actions. Provisioning actions typically
+// need access to host devices.
+var hostMounts = []specs.Mount{
+	toBindMount("/dev", "/dev", false),
+	toBindMount("/proc", "/proc", false),
+	toBindMount("/sys", "/sys", false),
+}
+
+// Process is a runtime that satisfies agent.ContainerRuntime by running actions as host processes
+// chrooted into their image's root filesystem. It's intended for hosts that can't run a container
+// engine and must run as root.
+//
+// Actions aren't isolated beyond the chroot: they share the host's namespaces and devices, and
+// writes to the root filesystem persist for subsequent actions using the same image. Actions using
+// the same image are therefore run sequentially.
+//
+// Image root filesystems are extracted to <dir>/<image>/rootfs alongside the image config at
+// <dir>/<image>/config.json. Root filesystems may be pre-staged at the same location, in which
+// case the image isn't pulled and the config is optional.
+type Process struct {
+	log logr.Logger
+
+	// dir is the directory image root filesystems are extracted to.
+	dir string
+
+	// logTailSize is the number of bytes of action output attached to failures.
+	logTailSize int
+
+	mtx    sync.Mutex
+	images map[string]*sync.Mutex
+}
+
+// Run satisfies agent.ContainerRuntime.
+func (p *Process) Run(ctx context.Context, a workflow.Action) error {
+	return p.RunWithLogs(ctx, a, io.Discard)
+}
+
+// RunWithLogs satisfies agent.LoggingContainerRuntime. The tail of the action's output is
+// retained and attached to the returned error when the action fails; see failure.LogTail().
+func (p *Process) RunWithLogs(ctx context.Context, a workflow.Action, logs io.Writer) error {
+	imageDir := filepath.Join(p.dir, validContainerName.ReplaceAllString(a.Image, "_"))
+
+	unlock := p.lockImage(imageDir)
+	defer unlock()
+
+	rootfs := filepath.Join(imageDir, "rootfs")
+	if _, err := os.Stat(rootfs); errors.Is(err, os.ErrNotExist) {
+		extract := func() error {
+			if err := p.extractImage(ctx, a.Image, imageDir); err != nil {
+				return fmt.Errorf("process: %w", err)
+			}
+			return nil
+		}
+		if err := retry.Do(extract, retry.Attempts(5), retry.DelayType(retry.BackOffDelay)); err != nil {
+			return err
+		}
+	}
+
+	cfg, err := readImageConfig(imageDir)
+	if err != nil {
+		return fmt.Errorf("process: %w", err)
+	}
+
+	failureFiles, err := internal.NewFailureFilesAt(
+		filepath.Join(rootfs, ReasonMountPath),
+		filepath.Join(rootfs, MessageMountPath),
+	)
+	if err != nil {
+		return fmt.Errorf("create action failure files: %w", err)
+	}
+	defer failureFiles.Close()
+
+	mounts := append([]specs.Mount(nil), hostMounts...)
+	for _, v := range a.Volumes {
+		m, err := parseVolume(v)
+		if err != nil {
+			return fmt.Errorf("process: %w", err)
+		}
+		mounts = append(mounts, m)
+	}
+
+	args := toProcessArgs(a, cfg)
+	if len(args) == 0 {
+		return errors.New("process: action and image define no command")
+	}
+
+	env := append(append([]string(nil), cfg.Env...), toDockerEnv(a.Env)...)
+	cmdPath, err := lookPath(rootfs, args[0], env)
+	if err != nil {
+		return fmt.Errorf("process: %w", err)
+	}
+
+	workDir := cfg.WorkingDir
+	if workDir == "" {
+		workDir = "/"
+	}
+
+	tail := internal.NewRingBuffer(p.logTailSize)
+	output := io.MultiWriter(tail, logs)
+
+	// The command is resolved in the chroot so must be constructed directly; exec.Command()
+	// would resolve it on the host.
+	cmd := &exec.Cmd{
+		Path:      cmdPath,
+		Args:      args,
+		Env:       env,
+		Dir:       workDir,
+		Stdout:    output,
+		Stderr:    output,
+		WaitDelay: outputDrainTimeout,
+	}
+
+	unmount, err := mountAll(rootfs, mounts)
+	if err != nil {
+		return fmt.Errorf("process: %w", err)
+	}
+	defer func() {
+		if err := unmount(); err != nil {
+			p.log.Info("Couldn't unmount action file systems", "action", a.ID, "error", err)
+		}
+	}()
+
+	if err := startChrooted(cmd, rootfs); err != nil {
+		return fmt.Errorf("process: %w", err)
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- cmd.Wait()
+	}()
+
+	select {
+	case err := <-done:
+		var exitErr *exec.ExitError
+		switch {
+		case err == nil:
+			return nil
+		case errors.As(err, &exitErr):
+			return failure.WithExitCode(
+				failure.WithLogTail(failureFiles.ToError(), tail.String()),
+				exitErr.ExitCode(),
+			)
+		default:
+			return fmt.Errorf("process: %w", err)
+		}
+
+	case <-ctx.Done():
+		if err := terminate(cmd.Process); err != nil {
+			p.log.Info("Failed to gracefully stop process", "error", err)
+		}
+		select {
+		case <-done:
+		case <-time.After(stopTimeout):
+			if err := kill(cmd.Process); err != nil {
+				p.log.Info("Failed to kill process", "error", err)
+			}
+			<-done
+		}
+		return fmt.Errorf("process: %w", ctx.Err())
+	}
+}
+
+// lockImage serializes use of the root filesystem in imageDir. The returned func releases the
+// lock.
+func (p *Process) lockImage(imageDir string) func() {
+	p.mtx.Lock()
+	if p.images == nil {
+		p.images = map[string]*sync.Mutex{}
+	}
+	mtx, ok := p.images[imageDir]
+	if !ok {
+		mtx = &sync.Mutex{}
+		p.images[imageDir] = mtx
+	}
+	p.mtx.Unlock()
+
+	mtx.Lock()
+	return mtx.Unlock
+}
+
+// extractImage pulls ref and extracts its root filesystem and config to imageDir. The root
+// filesystem is extracted to a temporary directory and renamed so partial extractions are never
+// used.
+func (p *Process) extractImage(ctx context.Context, ref, imageDir string) error {
+	r, err := name.ParseReference(ref)
+	if err != nil {
+		return err
+	}
+
+	img, err := remote.Image(r,
+		remote.WithContext(ctx),
+		remote.WithAuthFromKeychain(authn.DefaultKeychain),
+		remote.WithPlatform(v1.Platform{OS: "linux", Architecture: goruntime.GOARCH}),
+	)
+	if err != nil {
+		return err
+	}
+
+	cfg, err := img.ConfigFile()
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(imageDir, 0o755); err != nil {
+		return err
+	}
+
+	tmp, err := os.MkdirTemp(imageDir, "rootfs-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmp)
+
+	// Image filesystems are regular directories so the temporary directory must be too.
+	if err := os.Chmod(tmp, 0o755); err != nil {
+		return err
+	}
+
+	fs := mutate.Extract(img)
+	defer fs.Close()
+
+	p.log.Info("Extracting image", "image", ref, "dir", imageDir)
+	if err := untar(tar.NewReader(fs), tmp); err != nil {
+		return err
+	}
+
+	config, err := json.Marshal(cfg.Config)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(imageDir, "config.json"), config, 0o644); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp, filepath.Join(imageDir, "rootfs"))
+}
+
+// untar extracts r to dir. Images are trusted to the same degree as actions that run privileged
+// so links aren't sanitized beyond ensuring entries are written beneath dir.
+func untar(r *tar.Reader, dir string) error {
+	for {
+		hdr, err := r.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		target := filepath.Join(dir, path.Clean("/"+hdr.Name))
+		mode := hdr.FileInfo().Mode()
+
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, 0o755); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+				return err
+			}
+			fh, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, mode.Perm())
+			if err != nil {
+				return err
+			}
+			_, err = io.Copy(fh, r) //nolint:gosec // Image sizes are bounded by the registry.
+			fh.Close()
+			if err != nil {
+				return err
+			}
+		case tar.TypeSymlink:
+			if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+				return err
+			}
+			if err := os.Symlink(hdr.Linkname, target); err != nil {
+				return err
+			}
+			continue
+		case tar.TypeLink:
+			if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+				return err
+			}
+			if err := os.Link(filepath.Join(dir, path.Clean("/"+hdr.Linkname)), target); err != nil {
+				return err
+			}
+			continue
+		default:
+			// Device nodes and FIFOs are skipped; /dev is mounted from the host.
+			continue
+		}
+
+		// Apply the mode explicitly so it isn't subject to the umask and includes setuid bits.
+		if err := os.Chmod(target, mode); err != nil {
+			return err
+		}
+		if os.Geteuid() == 0 {
+			if err := os.Lchown(target, hdr.Uid, hdr.Gid); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// readImageConfig reads the image config from imageDir. Pre-staged root filesystems needn't have
+// a config so a missing config results in an empty config.
+func readImageConfig(imageDir string) (v1.Config, error) {
+	var cfg v1.Config
+
+	data, err := os.ReadFile(filepath.Join(imageDir, "config.json"))
+	if errors.Is(err, os.ErrNotExist) {
+		return cfg, nil
+	}
+	if err != nil {
+		return cfg, err
+	}
+
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return cfg, fmt.Errorf("parse image config: %w", err)
+	}
+	return cfg, nil
+}
+
+// toProcessArgs resolves the command line for a. The Tink Action Cmd property is modeled as being
+// the command launched in the container and overrides the image's entrypoint and command. When
+// only args are specified they're passed to the image's entrypoint, consistent with the Docker
+// runtime.
+func toProcessArgs(a workflow.Action, cfg v1.Config) []string {
+	if a.Cmd != "" {
+		return append([]string{a.Cmd}, a.Args...)
+	}
+
+	args := append([]string(nil), cfg.Entrypoint...)
+	if len(a.Args) > 0 {
+		return append(args, a.Args...)
+	}
+	return append(args, cfg.Cmd...)
+}
+
+// lookPath finds the executable file in the root filesystem rootfs using the PATH in env. The
+// returned path is relative to rootfs.
+func lookPath(rootfs, file string, env []string) (string, error) {
+	if strings.Contains(file, "/") {
+		return file, nil
+	}
+
+	searchPath := defaultPath
+	for _, e := range env {
+		if v, ok := strings.CutPrefix(e, "PATH="); ok {
+			searchPath = v
+		}
+	}
+
+	for _, dir := range filepath.SplitList(searchPath) {
+		candidate := path.Join("/", dir, file)
+
+		// Symlinks are commonly absolute so can't be followed outside the chroot. Assume they
+		// resolve to an executable.
+		fi, err := os.Lstat(filepath.Join(rootfs, candidate))
+		if err != nil {
+			continue
+		}
+		if fi.Mode()&os.ModeSymlink != 0 || (fi.Mode().IsRegular() && fi.Mode().Perm()&0o111 != 0) {
+			return candidate, nil
+		}
+	}
+
+	return "", fmt.Errorf("%v: executable file not found in PATH", file)
+}
+
+// NewProcess creates a new Process instance.
+func NewProcess(opts ...ProcessOption) *Process {
+	o := &Process{
+		log:         logr.Discard(),
+		dir:         DefaultProcessImageDir,
+		logTailSize: defaultLogTailSize,
+	}
+
+	for _, fn := range opts {
+		fn(o)
+	}
+
+	return o
+}
+
+// ProcessOption defines optional configuration for a Process instance.
+type ProcessOption func(*Process)
+
+// WithProcessLogger returns an option to configure the logger on a Process instance.
+func WithProcessLogger(log logr.Logger) ProcessOption {
+	return func(o *Process) {
+		if log.GetSink() == nil {
+			return
+		}
+		o.log = log
+	}
+}
+
+// WithProcessImageDir returns an option to configure the directory image root filesystems are
+// stored in.
+func WithProcessImageDir(dir string) ProcessOption {
+	return func(o *Process) {
+		if dir == "" {
+			return
+		}
+		o.dir = dir
+	}
+}
+
+// WithProcessLogTailSize returns an option to configure the number of bytes of action output
+// attached to action failures.
+func WithProcessLogTailSize(size int) ProcessOption {
+	return func(o *Process) {
+		if size < 0 {
+			return
+		}
+		o.logTailSize = size
+	}
+}
@@ -0,0 +1,91 @@
+package runtime
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"syscall"
+
+	"github.com/opencontainers/runtime-spec/specs-go"
+)
+
+// mountAll bind mounts mounts beneath rootfs. The returned func unmounts them.
+func mountAll(rootfs string, mounts []specs.Mount) (func() error, error) {
+	var mounted []string
+	unmount := func() error {
+		var errs []error
+		for i := len(mounted) - 1; i >= 0; i-- {
+			target := mounted[i]
+			if err := syscall.Unmount(target, syscall.MNT_DETACH); err != nil {
+				errs = append(errs, fmt.Errorf("unmount %v: %w", target, err))
+			}
+		}
+		return errors.Join(errs...)
+	}
+
+	for _, m := range mounts {
+		target := filepath.Join(rootfs, m.Destination)
+		if err := createMountPoint(m.Source, target); err != nil {
+			return nil, errors.Join(err, unmount())
+		}
+
+		if err := syscall.Mount(m.Source, target, "", syscall.MS_BIND|syscall.MS_REC, ""); err != nil {
+			return nil, errors.Join(fmt.Errorf("mount %v: %w", m.Source, err), unmount())
+		}
+		mounted = append(mounted, target)
+
+		// Bind mounts can only be made read-only by remounting.
+		if slices.Contains(m.Options, "ro") {
+			flags := uintptr(syscall.MS_BIND | syscall.MS_REMOUNT | syscall.MS_RDONLY)
+			if err := syscall.Mount("", target, "", flags, ""); err != nil {
+				return nil, errors.Join(fmt.Errorf("remount %v read-only: %w", m.Source, err), unmount())
+			}
+		}
+	}
+
+	return unmount, nil
+}
+
+// createMountPoint creates a file or directory at target matching the type of src.
+func createMountPoint(src, target string) error {
+	fi, err := os.Stat(src)
+	if err != nil {
+		return err
+	}
+
+	if fi.IsDir() {
+		return os.MkdirAll(target, 0o755)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+		return err
+	}
+	fh, err := os.OpenFile(target, os.O_CREATE, 0o644)
+	if err != nil {
+		return err
+	}
+	return fh.Close()
+}
+
+// startChrooted starts cmd chrooted into rootfs. The process is started in its own process group
+// so it, and any children, can be signaled together.
+func startChrooted(cmd *exec.Cmd, rootfs string) error {
+	cmd.SysProcAttr = &syscall.SysProcAttr{
+		Chroot:  rootfs,
+		Setpgid: true,
+	}
+	return cmd.Start()
+}
+
+// terminate sends SIGTERM to the process group of p.
+func terminate(p *os.Process) error {
+	return syscall.Kill(-p.Pid, syscall.SIGTERM)
+}
+
+// kill sends SIGKILL to the process group of p.
+func kill(p *os.Process) error {
+	return syscall.Kill(-p.Pid, syscall.SIGKILL)
+}
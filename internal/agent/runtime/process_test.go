@@ -0,0 +1,153 @@
+package runtime_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-logr/zerologr"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/rs/zerolog"
+	"github.com/tinkerbell/tink/internal/agent/failure"
+	"github.com/tinkerbell/tink/internal/agent/runtime"
+	"github.com/tinkerbell/tink/internal/agent/workflow"
+)
+
+// newProcess creates a Process runtime skipping the test when not running as root, as chroot
+// and mounts require root, or when the registry serving test images is unreachable.
+func newProcess(t *testing.T, dir string) *runtime.Process {
+	t.Helper()
+
+	if os.Geteuid() != 0 {
+		t.Skip("process runtime requires root")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	ref, err := name.ParseReference("alpine")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := remote.Head(ref, remote.WithContext(ctx)); err != nil {
+		t.Skipf("registry unavailable: %v", err)
+	}
+
+	logger := zerolog.New(zerolog.ConsoleWriter{Out: os.Stderr})
+	return runtime.NewProcess(
+		runtime.WithProcessLogger(zerologr.New(&logger)),
+		runtime.WithProcessImageDir(dir),
+	)
+}
+
+func TestProcessFailureMessages(t *testing.T) {
+	rt := newProcess(t, t.TempDir())
+
+	for _, tc := range []struct {
+		Name          string
+		Action        workflow.Action
+		ExpectReason  string
+		ExpectMessage string
+		ExpectCode    int
+	}{
+		{
+			Name: "FailureReasonAndMessage",
+			Action: workflow.Action{
+				ID:    "foobar",
+				Image: "alpine",
+				Args:  command{Message: "failure message", Reason: "FailureReason", Code: 3}.Build(),
+			},
+			ExpectReason:  "FailureReason",
+			ExpectMessage: "failure message",
+			ExpectCode:    3,
+		},
+		{
+			// Failure files must be reset between actions sharing an image.
+			Name: "NoError",
+			Action: workflow.Action{
+				ID:    "foobar",
+				Image: "alpine",
+				Cmd:   "true",
+			},
+		},
+	} {
+		t.Run(tc.Name, func(t *testing.T) {
+			err := rt.Run(context.Background(), tc.Action)
+
+			switch {
+			case tc.ExpectMessage == "" && err != nil:
+				t.Fatalf("Received unexpected error: %v", err)
+			case tc.ExpectMessage != "" && err == nil:
+				t.Fatal("Expected error but received none")
+			case tc.ExpectMessage != "" && tc.ExpectMessage != err.Error():
+				t.Fatalf("Expected: %v; Received: %v", tc.ExpectMessage, err)
+			}
+
+			if reason, _ := failure.Reason(err); reason != tc.ExpectReason {
+				t.Fatalf("Expected reason: %v; Received: %v", tc.ExpectReason, reason)
+			}
+
+			if code, _ := failure.ExitCode(err); code != tc.ExpectCode {
+				t.Fatalf("Expected exit code: %v; Received: %v", tc.ExpectCode, code)
+			}
+		})
+	}
+}
+
+func TestProcessContextTimeout(t *testing.T) {
+	rt := newProcess(t, t.TempDir())
+
+	action := workflow.Action{
+		ID:    "foobar",
+		Image: "alpine",
+		Args:  command{Sleep: 30}.Build(),
+	}
+
+	// Pull the image first so the timeout only applies to the action.
+	if err := rt.Run(context.Background(), workflow.Action{ID: "pull", Image: "alpine", Cmd: "true"}); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
+
+	err := rt.Run(ctx, action)
+
+	if err == nil {
+		t.Fatal("Expected error but received none")
+	}
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Expect: %v; Received: %v", context.DeadlineExceeded, err)
+	}
+}
+
+func TestProcessPreStagedRootfs(t *testing.T) {
+	dir := t.TempDir()
+	rt := newProcess(t, dir)
+
+	// Stage a root filesystem without an image config by extracting an image and moving it.
+	if err := rt.Run(context.Background(), workflow.Action{ID: "pull", Image: "alpine", Cmd: "true"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Join(dir, "staged"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Rename(filepath.Join(dir, "alpine", "rootfs"), filepath.Join(dir, "staged", "rootfs")); err != nil {
+		t.Fatal(err)
+	}
+
+	action := workflow.Action{
+		ID:    "foobar",
+		Image: "staged",
+		Cmd:   "sh",
+		Args:  []string{"-c", "test -c /dev/null && exit 4"},
+	}
+
+	err := rt.Run(context.Background(), action)
+	if code, _ := failure.ExitCode(err); code != 4 {
+		t.Fatalf("Expected exit code 4; Received: %v", err)
+	}
+}
@@ -0,0 +1,29 @@
+//go:build !linux
+
+package runtime
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+
+	"github.com/opencontainers/runtime-spec/specs-go"
+)
+
+var errProcessUnsupported = errors.New("the process runtime is only supported on Linux")
+
+func mountAll(string, []specs.Mount) (func() error, error) {
+	return nil, errProcessUnsupported
+}
+
+func startChrooted(*exec.Cmd, string) error {
+	return errProcessUnsupported
+}
+
+func terminate(p *os.Process) error {
+	return p.Kill()
+}
+
+func kill(p *os.Process) error {
+	return p.Kill()
+}
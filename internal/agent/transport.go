@@ -13,3 +13,12 @@ type Transport interface {
 	// should block until its told to cancel via the context.
 	Start(_ context.Context, agentID string, _ transport.WorkflowHandler) error
 }
+
+// HeartbeatTransport is a Transport capable of informing the server the agent is alive. The agent
+// sends heartbeats periodically when its Transport implements HeartbeatTransport.
+type HeartbeatTransport interface {
+	Transport
+
+	// Heartbeat sends a single heartbeat to the server.
+	Heartbeat(context.Context, transport.Heartbeat) error
+}
@@ -2,51 +2,320 @@ package transport
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
+	"sync"
+	"time"
 
+	"github.com/fsnotify/fsnotify"
 	"github.com/go-logr/logr"
 	"github.com/tinkerbell/tink/internal/agent/event"
 	"github.com/tinkerbell/tink/internal/agent/workflow"
 	"gopkg.in/yaml.v3"
 )
 
-// File is a transport implementation that executes a single workflow stored as a file.
+// fileSettleDelay is the time a workflow file must go unmodified before it's handled. It ensures
+// partially written files aren't parsed.
+const fileSettleDelay = 500 * time.Millisecond
+
+// fileRetryInterval is the interval at which workflow files the handler rejected, typically
+// because it's running its maximum number of workflows, are offered to it again.
+const fileRetryInterval = time.Second
+
+// eventsFileSuffix is the suffix of files events are written to.
+const eventsFileSuffix = ".events.jsonl"
+
+// summaryFileSuffix is the suffix of files workflow summaries are written to.
+const summaryFileSuffix = ".summary.json"
+
+// File is a transport implementation that runs workflows stored as files in a directory. It's
+// intended for air-gapped environments where workflows are delivered on removable media.
 type File struct {
 	// Log is a logger for debugging.
 	Log logr.Logger
 
-	// Path to the workflow to run.
-	Path string
+	// Dir is the directory watched for workflow files. Workflow files must have a .yml or .yaml
+	// extension.
+	Dir string
+
+	// LogDir, when set, is a directory where action output is persisted. Output for each action
+	// is appended to <LogDir>/<workflow id>/<action id>.log.
+	LogDir string
+
+	// EventsFile, when set, is a file events from all workflows are appended to in addition to
+	// each workflow's events file. It's useful for aggregating the results of bench runs.
+	EventsFile string
+
+	mtx     sync.Mutex
+	handled map[string]struct{}
+	pending []string
+
+	eventsMtx sync.Mutex
 }
 
 // Start begins watching f.Dir for files. When it finds a file it hasn't handled before, it
 // attempts to parse it and offload to the handler. It will run workflows once where a workflow
-// is determined by its file name.
+// is determined by its file name. Events for each workflow are written as FileEvent JSON lines to
+// a sibling <name>.events.jsonl file whose existence also prevents the workflow running again if
+// the agent restarts. When the workflow reaches a final outcome a Summary is written to a sibling
+// <name>.summary.json file. Workflows the handler rejects are queued and offered to it again
+// until it accepts them. Start blocks until ctx is cancelled.
 func (f *File) Start(ctx context.Context, _ string, handler WorkflowHandler) error {
-	path, err := filepath.Abs(f.Path)
+	dir, err := filepath.Abs(f.Dir)
 	if err != nil {
 		return err
 	}
 
-	fh, err := os.Open(path)
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+	defer watcher.Close()
+
+	// Watch before listing existing files so files created in between aren't missed.
+	if err := watcher.Add(dir); err != nil {
+		return err
+	}
+
+	entries, err := os.ReadDir(dir)
 	if err != nil {
 		return err
 	}
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			f.handleFile(ctx, filepath.Join(dir, entry.Name()), handler)
+		}
+	}
+
+	retry := time.NewTicker(fileRetryInterval)
+	defer retry.Stop()
+
+	// Debounce events per file so files are handled once writing completes.
+	settled := make(chan string)
+	timers := map[string]*time.Timer{}
+	defer func() {
+		for _, t := range timers {
+			t.Stop()
+		}
+	}()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+
+		case evnt, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+			if !evnt.Has(fsnotify.Create) && !evnt.Has(fsnotify.Write) {
+				continue
+			}
+			if !isWorkflowFile(evnt.Name) {
+				continue
+			}
+
+			path := evnt.Name
+			if t, ok := timers[path]; ok {
+				t.Reset(fileSettleDelay)
+				continue
+			}
+			timers[path] = time.AfterFunc(fileSettleDelay, func() {
+				select {
+				case settled <- path:
+				case <-ctx.Done():
+				}
+			})
+
+		case path := <-settled:
+			delete(timers, path)
+			f.handleFile(ctx, path, handler)
+
+		case <-retry.C:
+			f.retryPending(ctx, handler)
+
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+			f.Log.Error(err, "Watching workflow directory", "dir", dir)
+		}
+	}
+}
+
+// handleFile parses the workflow file at path and offloads it to handler if it hasn't been
+// handled before.
+func (f *File) handleFile(ctx context.Context, path string, handler WorkflowHandler) {
+	if !isWorkflowFile(path) {
+		return
+	}
+
+	log := f.Log.WithValues("file", path)
+
+	name := filepath.Base(path)
+	f.mtx.Lock()
+	if f.handled == nil {
+		f.handled = map[string]struct{}{}
+	}
+	_, handled := f.handled[name]
+	f.handled[name] = struct{}{}
+	f.mtx.Unlock()
+	if handled {
+		return
+	}
+
+	// Creating the events file exclusively ensures the workflow is only run once, even across
+	// agent restarts.
+	eventsPath := strings.TrimSuffix(path, filepath.Ext(path)) + eventsFileSuffix
+	fh, err := os.OpenFile(eventsPath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o644)
+	switch {
+	case errors.Is(err, os.ErrExist):
+		log.Info("Workflow file already handled; ignoring")
+		return
+	case err != nil:
+		log.Error(err, "Create workflow events file")
+		return
+	}
+	fh.Close()
+
+	wrkflow, err := readWorkflowFile(path)
+	if err != nil {
+		log.Error(err, "Parse workflow file")
+		wrkflow = workflow.Workflow{ID: workflowIDFromPath(path)}
+	}
+
+	recorder := &fileRecorder{
+		log:         log,
+		workflow:    wrkflow,
+		nowFunc:     time.Now,
+		path:        eventsPath,
+		summaryPath: strings.TrimSuffix(path, filepath.Ext(path)) + summaryFileSuffix,
+		sharedPath:  f.EventsFile,
+		sharedMtx:   &f.eventsMtx,
+		logDir:      f.LogDir,
+	}
+
+	if err != nil {
+		// Record the failure so it's visible to whoever delivered the file.
+		reject := event.WorkflowRejected{
+			ID:      wrkflow.ID,
+			Message: fmt.Sprintf("parse workflow file: %v", err),
+		}
+		if err := recorder.RecordEvent(ctx, reject); err != nil {
+			log.Error(err, "Record workflow rejection event")
+		}
+		return
+	}
+
+	// The handler rejects workflows it can't run yet so the file is released rather than
+	// recording the rejection.
+	recorder.onRejected = func(e event.WorkflowRejected) {
+		f.requeue(log, path, eventsPath, e.Message)
+	}
+
+	log.Info("Running workflow file", "workflow_id", wrkflow.ID)
+	handler.HandleWorkflow(ctx, wrkflow, recorder)
+}
+
+// requeue releases the workflow file at path, rejected by the handler, so it's offered to the
+// handler again.
+func (f *File) requeue(log logr.Logger, path, eventsPath, reason string) {
+	log.Info("Workflow rejected; queuing for retry", "reason", reason)
+
+	if err := os.Remove(eventsPath); err != nil && !errors.Is(err, os.ErrNotExist) {
+		log.Error(err, "Remove workflow events file")
+	}
+
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	delete(f.handled, filepath.Base(path))
+	f.pending = append(f.pending, path)
+}
+
+// retryPending offers queued workflow files to handler in the order they were rejected.
+func (f *File) retryPending(ctx context.Context, handler WorkflowHandler) {
+	f.mtx.Lock()
+	pending := f.pending
+	f.pending = nil
+	f.mtx.Unlock()
+
+	for _, path := range pending {
+		// Files removed while queued are dropped.
+		if _, err := os.Stat(path); err != nil {
+			continue
+		}
+		f.handleFile(ctx, path, handler)
+	}
+}
+
+func readWorkflowFile(path string) (workflow.Workflow, error) {
+	fh, err := os.Open(path)
+	if err != nil {
+		return workflow.Workflow{}, err
+	}
+	defer fh.Close()
 
 	var wrkflow workflow.Workflow
 	if err := yaml.NewDecoder(fh).Decode(&wrkflow); err != nil {
+		return workflow.Workflow{}, err
+	}
+
+	// Workflows are identified by their file name when they don't specify an ID.
+	if wrkflow.ID == "" {
+		wrkflow.ID = workflowIDFromPath(path)
+	}
+
+	return wrkflow, nil
+}
+
+// workflowIDFromPath returns the file name of path without its extension.
+func workflowIDFromPath(path string) string {
+	return strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+}
+
+func isWorkflowFile(path string) bool {
+	switch filepath.Ext(path) {
+	case ".yml", ".yaml":
+		return !strings.HasPrefix(filepath.Base(path), ".")
+	default:
+		return false
+	}
+}
+
+// appendActionLog appends action output to <logDir>/<workflow id>/<action id>.log. Output is
+// discarded when logDir is empty.
+func appendActionLog(logDir string, l event.ActionLog) error {
+	if logDir == "" {
+		return nil
+	}
+
+	dir := filepath.Join(logDir, sanitizePathElement(l.WorkflowID))
+	if err := os.MkdirAll(dir, 0o755); err != nil {
 		return err
 	}
 
-	handler.HandleWorkflow(ctx, wrkflow, f)
+	fh, err := os.OpenFile(
+		filepath.Join(dir, sanitizePathElement(l.ActionID)+".log"),
+		os.O_CREATE|os.O_APPEND|os.O_WRONLY,
+		0o644,
+	)
+	if err != nil {
+		return err
+	}
+	defer fh.Close()
 
-	return nil
+	_, err = fh.Write(l.Data)
+	return err
 }
 
-func (f *File) RecordEvent(_ context.Context, e event.Event) error {
-	// Noop because we don't particularly care about events for File based transports. Maybe
-	// we'll record this in a dedicated file one day.
-	f.Log.Info("Recording event", "event", e.GetName())
-	return nil
+// sanitizePathElement replaces characters that would let s escape its parent directory.
+func sanitizePathElement(s string) string {
+	s = strings.NewReplacer("/", "_", "\\", "_").Replace(s)
+	if s == "" || s == "." || s == ".." {
+		return "_"
+	}
+	return s
 }
@@ -0,0 +1,206 @@
+package transport
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/tinkerbell/tink/internal/agent/event"
+	"github.com/tinkerbell/tink/internal/agent/workflow"
+)
+
+// Outcome is the final outcome of a workflow run by the File transport.
+type Outcome string
+
+const (
+	OutcomeSucceeded Outcome = "Succeeded"
+	OutcomeFailed    Outcome = "Failed"
+	OutcomeRejected  Outcome = "Rejected"
+	OutcomeCanceled  Outcome = "Canceled"
+)
+
+// FileEvent is the structure of each line in an events file.
+type FileEvent struct {
+	// Time is the time the event was recorded.
+	Time time.Time `json:"time"`
+
+	// Name identifies the type of Event.
+	Name event.Name `json:"name"`
+
+	// Event is the event data.
+	Event event.Event `json:"event"`
+}
+
+// Summary is the structure of a workflow summary file. Summaries are written once a workflow
+// reaches a final outcome.
+type Summary struct {
+	WorkflowID string  `json:"workflowId"`
+	Outcome    Outcome `json:"outcome"`
+
+	// StartedAt is the time the first action started. It's nil if no action started.
+	StartedAt *time.Time `json:"startedAt,omitempty"`
+
+	// FinishedAt is the time the outcome was determined.
+	FinishedAt time.Time `json:"finishedAt"`
+
+	// ActionID, Reason and Message describe the failed action for failed workflows.
+	ActionID string `json:"actionId,omitempty"`
+	Reason   string `json:"reason,omitempty"`
+	Message  string `json:"message,omitempty"`
+}
+
+// fileRecorder is an event.Recorder that appends events for a single workflow as JSON lines to
+// a file. It writes a summary file once the workflow reaches a final outcome.
+type fileRecorder struct {
+	log      logr.Logger
+	workflow workflow.Workflow
+	nowFunc  func() time.Time
+
+	// path is the workflow's events file.
+	path string
+
+	// summaryPath is the workflow's summary file.
+	summaryPath string
+
+	// sharedPath, when set, is a file events from all workflows are additionally appended to.
+	// It's guarded by sharedMtx.
+	sharedPath string
+	sharedMtx  *sync.Mutex
+
+	// logDir is where action output is persisted; see File.LogDir.
+	logDir string
+
+	// onRejected, when set, is called instead of recording WorkflowRejected events.
+	onRejected func(event.WorkflowRejected)
+
+	mtx       sync.Mutex
+	startedAt *time.Time
+	completed map[string]struct{}
+}
+
+func (r *fileRecorder) RecordEvent(_ context.Context, e event.Event) error {
+	// Action output is voluminous so it's persisted separately to other events.
+	if l, ok := e.(event.ActionLog); ok {
+		return appendActionLog(r.logDir, l)
+	}
+
+	if rejected, ok := e.(event.WorkflowRejected); ok && r.onRejected != nil {
+		r.onRejected(rejected)
+		return nil
+	}
+
+	now := r.nowFunc()
+	line, err := json.Marshal(FileEvent{Time: now, Name: e.GetName(), Event: e})
+	if err != nil {
+		return err
+	}
+	line = append(line, '\n')
+
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	r.log.Info("Recording event", "event", e.GetName())
+	if err := appendFile(r.path, line); err != nil {
+		return err
+	}
+
+	if r.sharedPath != "" {
+		r.sharedMtx.Lock()
+		err := appendFile(r.sharedPath, line)
+		r.sharedMtx.Unlock()
+		if err != nil {
+			return err
+		}
+	}
+
+	if summary, ok := r.summarize(e, now); ok {
+		data, err := json.MarshalIndent(summary, "", "  ")
+		if err != nil {
+			return err
+		}
+		return os.WriteFile(r.summaryPath, append(data, '\n'), 0o644)
+	}
+
+	return nil
+}
+
+// summarize tracks workflow progress using e. When e completes the workflow it returns the
+// workflow summary.
+func (r *fileRecorder) summarize(e event.Event, now time.Time) (Summary, bool) {
+	summary := Summary{
+		WorkflowID: r.workflow.ID,
+		StartedAt:  r.startedAt,
+		FinishedAt: now,
+	}
+
+	switch v := e.(type) {
+	case event.ActionStarted:
+		if r.startedAt == nil {
+			r.startedAt = &now
+		}
+		return Summary{}, false
+
+	case event.ActionSucceeded:
+		if !r.complete(v.ActionID) {
+			return Summary{}, false
+		}
+		summary.Outcome = OutcomeSucceeded
+
+	case event.ActionSkipped:
+		if !r.complete(v.ActionID) {
+			return Summary{}, false
+		}
+		summary.Outcome = OutcomeSucceeded
+
+	case event.ActionFailed:
+		if v.Retrying {
+			return Summary{}, false
+		}
+		summary.Outcome = OutcomeFailed
+		summary.ActionID = v.ActionID
+		summary.Reason = v.Reason
+		summary.Message = v.Message
+
+	case event.WorkflowRejected:
+		summary.WorkflowID = v.ID
+		summary.Outcome = OutcomeRejected
+		summary.Message = v.Message
+
+	case event.WorkflowCanceled:
+		summary.Outcome = OutcomeCanceled
+
+	default:
+		return Summary{}, false
+	}
+
+	return summary, true
+}
+
+// complete records actionID as having succeeded or been skipped. It returns true once all actions
+// in the workflow have completed.
+func (r *fileRecorder) complete(actionID string) bool {
+	if r.completed == nil {
+		r.completed = map[string]struct{}{}
+	}
+	r.completed[actionID] = struct{}{}
+	for _, action := range r.workflow.Actions {
+		if _, ok := r.completed[action.ID]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+func appendFile(path string, data []byte) error {
+	fh, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	defer fh.Close()
+
+	_, err = fh.Write(data)
+	return err
+}
@@ -2,6 +2,11 @@ package transport_test
 
 import (
 	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -28,6 +33,7 @@ func TestFile(t *testing.T) {
 				Env:              map[string]string{"foo": "bar"},
 				Volumes:          []string{"mount:/foo/bar:ro"},
 				NetworkNamespace: "custom-namespace",
+				PIDNamespace:     "host",
 			},
 			{
 				ID:               "test-action-2",
@@ -38,28 +44,358 @@ func TestFile(t *testing.T) {
 				Env:              map[string]string{"foo": "bar"},
 				Volumes:          []string{"mount:/foo/bar:ro"},
 				NetworkNamespace: "custom-namespace",
+				PIDNamespace:     "host",
 			},
 		},
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	dir := t.TempDir()
+	copyFile(t, "./testdata/workflow.yml", filepath.Join(dir, "existing.yml"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
+	received := make(chan event.Recorder, 2)
 	handler := &transport.WorkflowHandlerMock{
-		HandleWorkflowFunc: func(_ context.Context, workflow workflow.Workflow, _ event.Recorder) {
+		HandleWorkflowFunc: func(_ context.Context, workflow workflow.Workflow, recorder event.Recorder) {
 			if !cmp.Equal(expect, workflow) {
-				t.Fatalf("Workflow diff:\n%v", cmp.Diff(expect, workflow))
+				t.Errorf("Workflow diff:\n%v", cmp.Diff(expect, workflow))
+			}
+			received <- recorder
+		},
+	}
+
+	f := transport.File{
+		Log: zerologr.New(&logger),
+		Dir: dir,
+	}
+
+	errs := make(chan error, 1)
+	go func() { errs <- f.Start(ctx, "agent_id", handler) }()
+
+	// Files present at start up are run.
+	recorder := receive(ctx, t, received)
+
+	// Events are appended to a sibling file.
+	err := recorder.RecordEvent(ctx, event.ActionStarted{WorkflowID: expect.ID, ActionID: "test-action-1", Attempt: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	events, err := os.ReadFile(filepath.Join(dir, "existing.events.jsonl"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(events), `"name":"ActionStarted"`) {
+		t.Fatalf("Unexpected events file content: %s", events)
+	}
+
+	// Files created after start up are run. Non-workflow files are ignored.
+	copyFile(t, "./testdata/workflow.yml", filepath.Join(dir, "new.yaml"))
+	copyFile(t, "./testdata/workflow.yml", filepath.Join(dir, "ignored.txt"))
+	receive(ctx, t, received)
+	if _, err := os.Stat(filepath.Join(dir, "new.events.jsonl")); err != nil {
+		t.Fatal(err)
+	}
+
+	// Modifying a file that has been run doesn't run it again.
+	copyFile(t, "./testdata/workflow.yml", filepath.Join(dir, "new.yaml"))
+	select {
+	case <-received:
+		t.Fatal("Workflow ran more than once")
+	case <-time.After(time.Second):
+	}
+
+	cancel()
+	if err := <-errs; err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFile_AlreadyHandled(t *testing.T) {
+	logger := zerolog.New(zerolog.NewConsoleWriter())
+
+	// An events file indicates the workflow ran before the agent restarted.
+	dir := t.TempDir()
+	copyFile(t, "./testdata/workflow.yml", filepath.Join(dir, "workflow.yml"))
+	if err := os.WriteFile(filepath.Join(dir, "workflow.events.jsonl"), nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	handler := &transport.WorkflowHandlerMock{
+		HandleWorkflowFunc: func(context.Context, workflow.Workflow, event.Recorder) {
+			t.Error("Unexpected call to HandleWorkflow")
+		},
+	}
+
+	f := transport.File{
+		Log: zerologr.New(&logger),
+		Dir: dir,
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := f.Start(ctx, "agent_id", handler); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFile_Rejected(t *testing.T) {
+	logger := zerolog.New(zerolog.NewConsoleWriter())
+
+	dir := t.TempDir()
+	copyFile(t, "./testdata/workflow.yml", filepath.Join(dir, "workflow.yml"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// The handler is at capacity for the first attempt.
+	var calls atomic.Int32
+	attempts := make(chan event.Recorder, 2)
+	handler := &transport.WorkflowHandlerMock{
+		HandleWorkflowFunc: func(ctx context.Context, w workflow.Workflow, recorder event.Recorder) {
+			if calls.Add(1) == 1 {
+				reject := event.WorkflowRejected{ID: w.ID, Message: "maximum concurrent workflows in progress"}
+				if err := recorder.RecordEvent(ctx, reject); err != nil {
+					t.Error(err)
+				}
 			}
+			attempts <- recorder
 		},
 	}
 
 	f := transport.File{
-		Log:  zerologr.New(&logger),
-		Path: "./testdata/workflow.yml",
+		Log: zerologr.New(&logger),
+		Dir: dir,
 	}
 
-	err := f.Start(ctx, "agent_id", handler)
+	errs := make(chan error, 1)
+	go func() { errs <- f.Start(ctx, "agent_id", handler) }()
+
+	// The rejected workflow is offered to the handler again.
+	receive(ctx, t, attempts)
+	receive(ctx, t, attempts)
+
+	// The rejection isn't recorded so the workflow runs if the agent restarts.
+	events, err := os.ReadFile(filepath.Join(dir, "workflow.events.jsonl"))
 	if err != nil {
 		t.Fatal(err)
 	}
+	if len(events) != 0 {
+		t.Fatalf("Unexpected events file content: %s", events)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "workflow.summary.json")); !os.IsNotExist(err) {
+		t.Fatalf("Expected no summary file; Received: %v", err)
+	}
+
+	cancel()
+	if err := <-errs; err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFile_InvalidWorkflow(t *testing.T) {
+	logger := zerolog.New(zerolog.NewConsoleWriter())
+
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "invalid.yml"), []byte("actions: {"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	f := transport.File{
+		Log: zerologr.New(&logger),
+		Dir: dir,
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := f.Start(ctx, "agent_id", &transport.WorkflowHandlerMock{}); err != nil {
+		t.Fatal(err)
+	}
+
+	events, err := os.ReadFile(filepath.Join(dir, "invalid.events.jsonl"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(events), `"name":"WorkflowRejected"`) {
+		t.Fatalf("Expected rejection event; Received: %s", events)
+	}
+}
+
+func TestFile_RecordActionLog(t *testing.T) {
+	logger := zerolog.New(zerolog.NewConsoleWriter())
+
+	dir := t.TempDir()
+	copyFile(t, "./testdata/workflow.yml", filepath.Join(dir, "workflow.yml"))
+	logDir := t.TempDir()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	received := make(chan event.Recorder, 1)
+	handler := &transport.WorkflowHandlerMock{
+		HandleWorkflowFunc: func(_ context.Context, _ workflow.Workflow, recorder event.Recorder) {
+			received <- recorder
+		},
+	}
+
+	f := transport.File{
+		Log:    zerologr.New(&logger),
+		Dir:    dir,
+		LogDir: logDir,
+	}
+	go f.Start(ctx, "agent_id", handler) //nolint:errcheck // Errors surface as test timeouts.
+
+	recorder := receive(ctx, t, received)
+	for _, data := range []string{"hello ", "world\n"} {
+		err := recorder.RecordEvent(ctx, event.ActionLog{
+			WorkflowID: "default/test-workflow-id",
+			ActionID:   "test-action-1",
+			Data:       []byte(data),
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	got, err := os.ReadFile(filepath.Join(logDir, "default_test-workflow-id", "test-action-1.log"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != "hello world\n" {
+		t.Fatalf("Expected: %q; Received: %q", "hello world\n", got)
+	}
+}
+
+func receive(ctx context.Context, t *testing.T, received <-chan event.Recorder) event.Recorder {
+	t.Helper()
+	select {
+	case r := <-received:
+		return r
+	case <-ctx.Done():
+		t.Fatal("Timed out waiting for workflow")
+		return nil
+	}
+}
+
+func copyFile(t *testing.T, src, dst string) {
+	t.Helper()
+	data, err := os.ReadFile(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(dst, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFile_Summary(t *testing.T) {
+	logger := zerolog.New(zerolog.NewConsoleWriter())
+
+	cases := []struct {
+		Name   string
+		Events []event.Event
+		Expect transport.Summary
+	}{
+		{
+			Name: "Succeeded",
+			Events: []event.Event{
+				event.ActionStarted{WorkflowID: "test-workflow-id", ActionID: "test-action-1", Attempt: 1},
+				event.ActionSucceeded{WorkflowID: "test-workflow-id", ActionID: "test-action-1"},
+				event.ActionStarted{WorkflowID: "test-workflow-id", ActionID: "test-action-2", Attempt: 1},
+				event.ActionSucceeded{WorkflowID: "test-workflow-id", ActionID: "test-action-2"},
+			},
+			Expect: transport.Summary{WorkflowID: "test-workflow-id", Outcome: transport.OutcomeSucceeded},
+		},
+		{
+			Name: "Failed",
+			Events: []event.Event{
+				event.ActionStarted{WorkflowID: "test-workflow-id", ActionID: "test-action-1", Attempt: 1},
+				event.ActionFailed{WorkflowID: "test-workflow-id", ActionID: "test-action-1", Attempt: 1, Reason: "Flaky", Retrying: true},
+				event.ActionStarted{WorkflowID: "test-workflow-id", ActionID: "test-action-1", Attempt: 2},
+				event.ActionFailed{WorkflowID: "test-workflow-id", ActionID: "test-action-1", Attempt: 2, Reason: "Broken", Message: "disk not found"},
+			},
+			Expect: transport.Summary{
+				WorkflowID: "test-workflow-id",
+				Outcome:    transport.OutcomeFailed,
+				ActionID:   "test-action-1",
+				Reason:     "Broken",
+				Message:    "disk not found",
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.Name, func(t *testing.T) {
+			dir := t.TempDir()
+			copyFile(t, "./testdata/workflow.yml", filepath.Join(dir, "workflow.yml"))
+			eventsFile := filepath.Join(t.TempDir(), "all.jsonl")
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			received := make(chan event.Recorder, 1)
+			handler := &transport.WorkflowHandlerMock{
+				HandleWorkflowFunc: func(_ context.Context, _ workflow.Workflow, recorder event.Recorder) {
+					received <- recorder
+				},
+			}
+
+			f := transport.File{
+				Log:        zerologr.New(&logger),
+				Dir:        dir,
+				EventsFile: eventsFile,
+			}
+			go f.Start(ctx, "agent_id", handler) //nolint:errcheck // Errors surface as test timeouts.
+
+			recorder := receive(ctx, t, received)
+			for i, e := range tc.Events {
+				if _, err := os.Stat(filepath.Join(dir, "workflow.summary.json")); err == nil {
+					t.Fatalf("Summary written before final event %v", i)
+				}
+				if err := recorder.RecordEvent(ctx, e); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			data, err := os.ReadFile(filepath.Join(dir, "workflow.summary.json"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			var summary transport.Summary
+			if err := json.Unmarshal(data, &summary); err != nil {
+				t.Fatal(err)
+			}
+			if summary.StartedAt == nil || summary.FinishedAt.Before(*summary.StartedAt) {
+				t.Fatalf("Unexpected summary times: %s", data)
+			}
+			summary.StartedAt, summary.FinishedAt = nil, time.Time{}
+			if !cmp.Equal(tc.Expect, summary) {
+				t.Fatalf("Summary diff:\n%v", cmp.Diff(tc.Expect, summary))
+			}
+
+			// Every event is recorded, with a time, in both the workflow and shared events files.
+			for _, path := range []string{filepath.Join(dir, "workflow.events.jsonl"), eventsFile} {
+				data, err := os.ReadFile(path)
+				if err != nil {
+					t.Fatal(err)
+				}
+				lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+				if len(lines) != len(tc.Events) {
+					t.Fatalf("Expected %v events in %v; Received: %v", len(tc.Events), path, len(lines))
+				}
+				for _, line := range lines {
+					var e struct {
+						Time time.Time
+						Name event.Name
+					}
+					if err := json.Unmarshal([]byte(line), &e); err != nil {
+						t.Fatal(err)
+					}
+					if e.Time.IsZero() || e.Name == "" {
+						t.Fatalf("Expected time and name: %v", line)
+					}
+				}
+			}
+		})
+	}
 }
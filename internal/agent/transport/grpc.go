@@ -4,68 +4,131 @@ import (
 	"context"
 	"errors"
 	"io"
+	"math/rand/v2"
+	"time"
 
 	"github.com/avast/retry-go"
 	"github.com/go-logr/logr"
 	"github.com/tinkerbell/tink/internal/agent/event"
 	"github.com/tinkerbell/tink/internal/agent/workflow"
 	workflowproto "github.com/tinkerbell/tink/internal/proto/workflow/v2"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 var _ event.Recorder = &GRPC{}
 
-func NewGRPC(log logr.Logger, client workflowproto.WorkflowServiceClient) *GRPC {
-	return &GRPC{
+// Default reconnection backoff used by the GRPC transport.
+const (
+	DefaultReconnectInitialDelay = 500 * time.Millisecond
+	DefaultReconnectMaxDelay     = 30 * time.Second
+)
+
+// GRPCOption configures a GRPC transport.
+type GRPCOption func(*GRPC)
+
+// WithReconnectBackoff configures the delays between attempts to reconnect to the server. Delays
+// grow exponentially from initial to maxDelay and are jittered so agents don't reconnect in
+// lockstep.
+func WithReconnectBackoff(initial, maxDelay time.Duration) GRPCOption {
+	return func(g *GRPC) {
+		g.backoff = backoff{initial: initial, max: maxDelay}
+	}
+}
+
+func NewGRPC(log logr.Logger, client workflowproto.WorkflowServiceClient, opts ...GRPCOption) *GRPC {
+	g := &GRPC{
 		log:    log,
 		client: client,
+		backoff: backoff{
+			initial: DefaultReconnectInitialDelay,
+			max:     DefaultReconnectMaxDelay,
+		},
+	}
+	for _, opt := range opts {
+		opt(g)
 	}
+	return g
 }
 
 type GRPC struct {
-	log    logr.Logger
-	client workflowproto.WorkflowServiceClient
+	log     logr.Logger
+	client  workflowproto.WorkflowServiceClient
+	backoff backoff
 }
 
+// Start retrieves workflows for agentID until ctx is cancelled. When the stream breaks it
+// reconnects with backoff, reporting workflows handler is running so the server doesn't dispatch
+// them again. It returns an error if the server rejects the agent.
 func (g *GRPC) Start(ctx context.Context, agentID string, handler WorkflowHandler) error {
-	stream, err := g.client.GetWorkflows(ctx, &workflowproto.GetWorkflowsRequest{
-		AgentId: agentID,
+	return reconnect(ctx, g.log, g.backoff, func() (bool, error) {
+		return g.stream(ctx, agentID, handler)
 	})
-	if err != nil {
-		return err
-	}
+}
 
+// reconnect calls stream until ctx is cancelled or stream returns a permanent error, backing off
+// between calls. stream reports whether it received any commands and returns a nil error when the
+// server closed it.
+func reconnect(ctx context.Context, log logr.Logger, b backoff, stream func() (bool, error)) error {
 	for {
-		request, err := stream.Recv()
+		connected := time.Now()
+		received, err := stream()
 		switch {
-		case errors.Is(err, io.EOF):
+		case ctx.Err() != nil:
 			return nil
-		case err != nil:
+		case isPermanent(err):
 			return err
 		}
 
-		switch request.GetCmd().(type) {
-		case *workflowproto.GetWorkflowsResponse_StartWorkflow_:
-			grpcWorkflow := request.GetStartWorkflow().GetWorkflow()
+		// Streams that were healthy for a while, or that delivered commands, indicate the
+		// server is reachable so we start backing off afresh.
+		if received || time.Since(connected) > b.max {
+			b.reset()
+		}
 
-			if err := validateGRPCWorkflow(grpcWorkflow); err != nil {
-				g.log.Info(
-					"Dropping request to start workflow; invalid payload",
-					"error", err,
-					"payload", grpcWorkflow,
-				)
-				continue
-			}
+		delay := b.next()
+		if err != nil {
+			log.Info("Workflow stream broken; reconnecting", "error", err, "delay", delay)
+		} else {
+			log.Info("Workflow stream closed by server; reconnecting", "delay", delay)
+		}
 
-			handler.HandleWorkflow(ctx, toWorkflow(grpcWorkflow), g)
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(delay):
+		}
+	}
+}
 
-		case *workflowproto.GetWorkflowsResponse_StopWorkflow_:
-			if request.GetStopWorkflow().WorkflowId == "" {
-				g.log.Info("Dropping request to cancel workflow; missing workflow ID")
-				continue
-			}
+// stream opens a workflow stream and handles commands until it breaks. It reports whether any
+// commands were received. A nil error indicates the server closed the stream.
+func (g *GRPC) stream(ctx context.Context, agentID string, handler WorkflowHandler) (bool, error) {
+	// Cancel the stream on return so its resources are released.
+	streamCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
 
-			handler.CancelWorkflow(request.GetStopWorkflow().WorkflowId)
+	stream, err := g.client.GetWorkflows(streamCtx, &workflowproto.GetWorkflowsRequest{
+		AgentId:            agentID,
+		RunningWorkflowIds: handler.RunningWorkflows(),
+	})
+	if err != nil {
+		return false, err
+	}
+
+	var received bool
+	for {
+		request, err := stream.Recv()
+		switch {
+		case errors.Is(err, io.EOF):
+			return received, nil
+		case err != nil:
+			return received, err
 		}
+		received = true
+
+		// Workflows outlive the stream so they're handled with the transport's context.
+		handleGetWorkflowsResponse(ctx, g.log, request, handler, g)
 	}
 }
 
@@ -86,6 +149,82 @@ func (g *GRPC) RecordEvent(ctx context.Context, e event.Event) error {
 	return retry.Do(publish, retry.Attempts(5), retry.DelayType(retry.BackOffDelay))
 }
 
+// Heartbeat informs the server the agent is alive.
+func (g *GRPC) Heartbeat(ctx context.Context, h Heartbeat) error {
+	_, err := g.client.Heartbeat(ctx, toHeartbeatRequest(h))
+	return err
+}
+
+// backoff computes exponentially growing delays with jitter.
+type backoff struct {
+	initial, max time.Duration
+	attempt      int
+}
+
+// next returns the delay before the next attempt. Delays are between half and all of the
+// exponential delay so they're jittered but never negligible.
+func (b *backoff) next() time.Duration {
+	delay := b.max
+	if shift := b.attempt; shift < 32 {
+		if d := b.initial << shift; d > 0 && d < b.max {
+			delay = d
+		}
+	}
+	b.attempt++
+
+	half := delay / 2
+	return half + rand.N(delay-half+1)
+}
+
+// reset restarts the backoff from the initial delay.
+func (b *backoff) reset() {
+	b.attempt = 0
+}
+
+// isPermanent determines if err indicates reconnecting won't succeed.
+func isPermanent(err error) bool {
+	switch status.Code(err) {
+	case codes.InvalidArgument, codes.Unauthenticated, codes.PermissionDenied, codes.Unimplemented:
+		return true
+	default:
+		return false
+	}
+}
+
+// handleGetWorkflowsResponse offloads a command received from the server to handler. Events for
+// started workflows are recorded with recorder.
+func handleGetWorkflowsResponse(
+	ctx context.Context,
+	log logr.Logger,
+	request *workflowproto.GetWorkflowsResponse,
+	handler WorkflowHandler,
+	recorder event.Recorder,
+) {
+	switch request.GetCmd().(type) {
+	case *workflowproto.GetWorkflowsResponse_StartWorkflow_:
+		grpcWorkflow := request.GetStartWorkflow().GetWorkflow()
+
+		if err := validateGRPCWorkflow(grpcWorkflow); err != nil {
+			log.Info(
+				"Dropping request to start workflow; invalid payload",
+				"error", err,
+				"payload", grpcWorkflow,
+			)
+			return
+		}
+
+		handler.HandleWorkflow(ctx, toWorkflow(grpcWorkflow), recorder)
+
+	case *workflowproto.GetWorkflowsResponse_StopWorkflow_:
+		if request.GetStopWorkflow().WorkflowId == "" {
+			log.Info("Dropping request to cancel workflow; missing workflow ID")
+			return
+		}
+
+		handler.CancelWorkflow(ctx, request.GetStopWorkflow().WorkflowId, recorder)
+	}
+}
+
 func validateGRPCWorkflow(wflw *workflowproto.Workflow) error {
 	if wflw == nil {
 		return errors.New("workflow must not be nil")
@@ -119,6 +258,12 @@ func toActions(a []*workflowproto.Workflow_Action) []workflow.Action {
 			Env:              action.GetEnv(),
 			Volumes:          action.GetVolumes(),
 			NetworkNamespace: action.GetNetworkNamespace(),
+			PIDNamespace:     action.GetPidNamespace(),
+			Retries:          int(action.GetRetries()),
+			Backoff:          time.Duration(action.GetBackoffSeconds()) * time.Second,
+			RetryOn:          action.GetRetryOn(),
+			Group:            action.GetGroup(),
+			When:             action.GetWhen(),
 		})
 	}
 	return actions
@@ -132,6 +277,7 @@ func toGRPC(e event.Event) (*workflowproto.Event, error) {
 			Event: &workflowproto.Event_ActionStarted_{
 				ActionStarted: &workflowproto.Event_ActionStarted{
 					ActionId: v.ActionID,
+					Attempt:  int32(v.Attempt),
 				},
 			},
 		}, nil
@@ -141,6 +287,16 @@ func toGRPC(e event.Event) (*workflowproto.Event, error) {
 			Event: &workflowproto.Event_ActionSucceeded_{
 				ActionSucceeded: &workflowproto.Event_ActionSucceeded{
 					ActionId: v.ActionID,
+					Outputs:  v.Outputs,
+				},
+			},
+		}, nil
+	case event.ActionSkipped:
+		return &workflowproto.Event{
+			WorkflowId: v.WorkflowID,
+			Event: &workflowproto.Event_ActionSkipped_{
+				ActionSkipped: &workflowproto.Event_ActionSkipped{
+					ActionId: v.ActionID,
 				},
 			},
 		}, nil
@@ -152,6 +308,19 @@ func toGRPC(e event.Event) (*workflowproto.Event, error) {
 					ActionId:       v.ActionID,
 					FailureReason:  &v.Reason,
 					FailureMessage: &v.Message,
+					Attempt:        int32(v.Attempt),
+					Retrying:       v.Retrying,
+				},
+			},
+		}, nil
+	case event.ActionLog:
+		return &workflowproto.Event{
+			WorkflowId: v.WorkflowID,
+			Event: &workflowproto.Event_ActionLog_{
+				ActionLog: &workflowproto.Event_ActionLog{
+					ActionId: v.ActionID,
+					Attempt:  int32(v.Attempt),
+					Data:     v.Data,
 				},
 			},
 		}, nil
@@ -164,6 +333,13 @@ func toGRPC(e event.Event) (*workflowproto.Event, error) {
 				},
 			},
 		}, nil
+	case event.WorkflowCanceled:
+		return &workflowproto.Event{
+			WorkflowId: v.ID,
+			Event: &workflowproto.Event_WorkflowCanceled_{
+				WorkflowCanceled: &workflowproto.Event_WorkflowCanceled{},
+			},
+		}, nil
 	}
 
 	return nil, event.IncompatibleError{Event: e}
@@ -5,14 +5,19 @@ import (
 	"io"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/go-logr/zerologr"
+	"github.com/google/go-cmp/cmp"
 	"github.com/rs/zerolog"
 	"github.com/tinkerbell/tink/internal/agent/event"
 	"github.com/tinkerbell/tink/internal/agent/transport"
 	"github.com/tinkerbell/tink/internal/agent/workflow"
 	workflowproto "github.com/tinkerbell/tink/internal/proto/workflow/v2"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/testing/protocmp"
 )
 
 func TestGRPC(t *testing.T) {
@@ -51,21 +56,116 @@ func TestGRPC(t *testing.T) {
 		},
 	}
 
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
 	var wg sync.WaitGroup
 	wg.Add(1)
 	handler := &transport.WorkflowHandlerMock{
 		HandleWorkflowFunc: func(_ context.Context, _ workflow.Workflow, _ event.Recorder) {
 			defer wg.Done()
 			close(responses)
+
+			// The transport reconnects when the stream closes so stop it.
+			cancel()
 		},
+		RunningWorkflowsFunc: func() []string { return nil },
 	}
 
 	g := transport.NewGRPC(zerologr.New(&logger), client)
 
-	err := g.Start(context.Background(), "id", handler)
+	err := g.Start(ctx, "id", handler)
 	if err != nil {
 		t.Fatal(err)
 	}
 
 	wg.Wait()
 }
+
+func TestGRPC_Reconnect(t *testing.T) {
+	logger := zerolog.New(zerolog.NewConsoleWriter())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var requests []*workflowproto.GetWorkflowsRequest
+	client := &workflowproto.WorkflowServiceClientMock{
+		GetWorkflowsFunc: func(_ context.Context, req *workflowproto.GetWorkflowsRequest, _ ...grpc.CallOption) (workflowproto.WorkflowService_GetWorkflowsClient, error) {
+			requests = append(requests, req)
+			attempt := len(requests)
+
+			return &workflowproto.WorkflowService_GetWorkflowsClientMock{
+				RecvFunc: func() (*workflowproto.GetWorkflowsResponse, error) {
+					switch attempt {
+					case 1:
+						return nil, status.Error(codes.Unavailable, "connection reset")
+					case 2:
+						return nil, io.EOF
+					default:
+						cancel()
+						<-ctx.Done()
+						return nil, ctx.Err()
+					}
+				},
+			}, nil
+		},
+	}
+
+	handler := &transport.WorkflowHandlerMock{
+		RunningWorkflowsFunc: func() []string { return []string{"ns/running"} },
+	}
+
+	g := transport.NewGRPC(
+		zerologr.New(&logger),
+		client,
+		transport.WithReconnectBackoff(time.Millisecond, 10*time.Millisecond),
+	)
+
+	if err := g.Start(ctx, "id", handler); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(requests) != 3 {
+		t.Fatalf("Expected 3 connection attempts; Received %v", len(requests))
+	}
+	for _, req := range requests {
+		expect := &workflowproto.GetWorkflowsRequest{AgentId: "id", RunningWorkflowIds: []string{"ns/running"}}
+		if diff := cmp.Diff(expect, req, protocmp.Transform()); diff != "" {
+			t.Fatal(diff)
+		}
+	}
+}
+
+func TestGRPC_PermanentError(t *testing.T) {
+	logger := zerolog.New(zerolog.NewConsoleWriter())
+
+	var attempts int
+	client := &workflowproto.WorkflowServiceClientMock{
+		GetWorkflowsFunc: func(_ context.Context, _ *workflowproto.GetWorkflowsRequest, _ ...grpc.CallOption) (workflowproto.WorkflowService_GetWorkflowsClient, error) {
+			attempts++
+			return &workflowproto.WorkflowService_GetWorkflowsClientMock{
+				RecvFunc: func() (*workflowproto.GetWorkflowsResponse, error) {
+					return nil, status.Error(codes.PermissionDenied, "denied")
+				},
+			}, nil
+		},
+	}
+
+	handler := &transport.WorkflowHandlerMock{
+		RunningWorkflowsFunc: func() []string { return nil },
+	}
+
+	g := transport.NewGRPC(
+		zerologr.New(&logger),
+		client,
+		transport.WithReconnectBackoff(time.Millisecond, 10*time.Millisecond),
+	)
+
+	err := g.Start(context.Background(), "id", handler)
+	if status.Code(err) != codes.PermissionDenied {
+		t.Fatalf("Expected %v; Received: %v", codes.PermissionDenied, err)
+	}
+	if attempts != 1 {
+		t.Fatalf("Expected 1 connection attempt; Received %v", attempts)
+	}
+}
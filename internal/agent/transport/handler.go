@@ -14,7 +14,12 @@ type WorkflowHandler interface {
 	// in handing off workflow processing.
 	HandleWorkflow(context.Context, workflow.Workflow, event.Recorder)
 
-	// CancelWorkflow cancels a workflow identified by workflowID. It should not block and should
-	// be efficient in handing off the cancellation request.
-	CancelWorkflow(workflowID string)
+	// CancelWorkflow cancels a workflow identified by workflowID. The event.Recorder can be used
+	// to publish the cancellation. It should not block and should be efficient in handing off the
+	// cancellation request.
+	CancelWorkflow(ctx context.Context, workflowID string, events event.Recorder)
+
+	// RunningWorkflows returns the IDs of workflows currently executing. Transports report them
+	// to the server when reconnecting.
+	RunningWorkflows() []string
 }
@@ -0,0 +1,30 @@
+package transport
+
+import workflowproto "github.com/tinkerbell/tink/internal/proto/workflow/v2"
+
+// Heartbeat describes an agent in heartbeats sent to the server.
+type Heartbeat struct {
+	AgentID string
+
+	// Version is the version of the agent.
+	Version string
+
+	// Runtime is the name of the container runtime the agent executes actions with.
+	Runtime string
+
+	// FreeDiskBytes is the disk space available to the agent.
+	FreeDiskBytes uint64
+
+	// RunningWorkflows identifies the workflows the agent is executing.
+	RunningWorkflows []string
+}
+
+func toHeartbeatRequest(h Heartbeat) *workflowproto.HeartbeatRequest {
+	return &workflowproto.HeartbeatRequest{
+		AgentId:            h.AgentID,
+		Version:            h.Version,
+		Runtime:            h.Runtime,
+		FreeDiskBytes:      h.FreeDiskBytes,
+		RunningWorkflowIds: h.RunningWorkflows,
+	}
+}
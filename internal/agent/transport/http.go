@@ -0,0 +1,292 @@
+package transport
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/avast/retry-go"
+	"github.com/go-logr/logr"
+	"github.com/tinkerbell/tink/internal/agent/event"
+	workflowproto "github.com/tinkerbell/tink/internal/proto/workflow/v2"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	googleproto "google.golang.org/protobuf/proto"
+)
+
+// HTTP workflow API paths. They must match those served by the Tink server; they're duplicated
+// so the agent needn't depend on the server package.
+const (
+	httpGetWorkflowsPath = "/v2/agents/%v/workflows"
+	httpPublishEventPath = "/v2/events"
+	httpHeartbeatPath    = "/v2/heartbeats"
+)
+
+// maxSSEMessageSize is the largest Server-Sent Events message the HTTP transport will parse.
+const maxSSEMessageSize = 4 << 20
+
+var _ event.Recorder = &HTTP{}
+
+// HTTPOption configures an HTTP transport.
+type HTTPOption func(*HTTP)
+
+// WithHTTPReconnectBackoff configures the delays between attempts to reconnect to the server. See
+// WithReconnectBackoff.
+func WithHTTPReconnectBackoff(initial, maxDelay time.Duration) HTTPOption {
+	return func(h *HTTP) {
+		h.backoff = backoff{initial: initial, max: maxDelay}
+	}
+}
+
+// NewHTTP creates an HTTP transport that talks to the Tink server at baseURL, for example
+// https://tink-server:42115, using client.
+func NewHTTP(log logr.Logger, baseURL string, client *http.Client, opts ...HTTPOption) *HTTP {
+	h := &HTTP{
+		log:     log,
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		client:  client,
+		backoff: backoff{
+			initial: DefaultReconnectInitialDelay,
+			max:     DefaultReconnectMaxDelay,
+		},
+	}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
+}
+
+// HTTP is a transport implementation for networks that block HTTP/2 or gRPC. It receives
+// workflow commands from the Tink server as Server-Sent Events and publishes events as JSON.
+type HTTP struct {
+	log     logr.Logger
+	baseURL string
+	client  *http.Client
+	backoff backoff
+}
+
+// Start retrieves workflows for agentID until ctx is cancelled. When the event stream breaks it
+// reconnects with backoff, reporting workflows handler is running so the server doesn't dispatch
+// them again. It returns an error if the server rejects the agent.
+func (h *HTTP) Start(ctx context.Context, agentID string, handler WorkflowHandler) error {
+	return reconnect(ctx, h.log, h.backoff, func() (bool, error) {
+		return h.stream(ctx, agentID, handler)
+	})
+}
+
+// stream opens an event stream and handles commands until it breaks. It reports whether any
+// commands were received. A nil error indicates the server closed the stream.
+func (h *HTTP) stream(ctx context.Context, agentID string, handler WorkflowHandler) (bool, error) {
+	endpoint := h.baseURL + fmt.Sprintf(httpGetWorkflowsPath, url.PathEscape(agentID))
+	if running := handler.RunningWorkflows(); len(running) > 0 {
+		endpoint += "?" + url.Values{"running": running}.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Accept", "text/event-stream")
+
+	resp, err := h.client.Do(req)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+
+	if err := checkHTTPResponse(resp); err != nil {
+		return false, err
+	}
+
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(nil, maxSSEMessageSize)
+
+	var (
+		name     string
+		data     bytes.Buffer
+		received bool
+	)
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		switch {
+		// A blank line dispatches the message.
+		case line == "":
+			if data.Len() > 0 {
+				if err := h.dispatch(ctx, name, data.Bytes(), handler); err != nil {
+					return received, err
+				}
+				received = true
+			}
+			name = ""
+			data.Reset()
+
+		// Lines beginning with a colon are comments used to keep the connection alive.
+		case strings.HasPrefix(line, ":"):
+
+		default:
+			field, value, _ := strings.Cut(line, ":")
+			value = strings.TrimPrefix(value, " ")
+			switch field {
+			case "event":
+				name = value
+			case "data":
+				if data.Len() > 0 {
+					data.WriteByte('\n')
+				}
+				data.WriteString(value)
+			}
+		}
+	}
+
+	return received, scanner.Err()
+}
+
+// dispatch handles a single Server-Sent Events message.
+func (h *HTTP) dispatch(ctx context.Context, name string, data []byte, handler WorkflowHandler) error {
+	switch name {
+	case "", "message":
+		var request workflowproto.GetWorkflowsResponse
+		if err := protojson.Unmarshal(data, &request); err != nil {
+			h.log.Info("Dropping workflow command; invalid payload", "error", err)
+			return nil
+		}
+		handleGetWorkflowsResponse(ctx, h.log, &request, handler, h)
+
+	case "error":
+		return newHTTPError(data)
+	}
+
+	return nil
+}
+
+func (h *HTTP) RecordEvent(ctx context.Context, e event.Event) error {
+	evnt, err := toGRPC(e)
+	if err != nil {
+		return err
+	}
+
+	publish := func() error {
+		return h.post(ctx, httpPublishEventPath, &workflowproto.PublishEventRequest{Event: evnt})
+	}
+
+	return retry.Do(publish, retry.Attempts(5), retry.DelayType(retry.BackOffDelay))
+}
+
+// Heartbeat informs the server the agent is alive.
+func (h *HTTP) Heartbeat(ctx context.Context, hb Heartbeat) error {
+	return h.post(ctx, httpHeartbeatPath, toHeartbeatRequest(hb))
+}
+
+// post sends msg to the server at path. Errors that won't succeed on retry are wrapped with
+// retry.Unrecoverable.
+func (h *HTTP) post(ctx context.Context, path string, msg googleproto.Message) error {
+	body, err := protojson.Marshal(msg)
+	if err != nil {
+		return retry.Unrecoverable(err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, h.baseURL+path, bytes.NewReader(body))
+	if err != nil {
+		return retry.Unrecoverable(err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := h.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if err := checkHTTPResponse(resp); err != nil {
+		// Client errors won't succeed on retry.
+		if resp.StatusCode >= 400 && resp.StatusCode < 500 {
+			return retry.Unrecoverable(err)
+		}
+		return err
+	}
+
+	// Drain the body so the connection can be reused.
+	_, _ = io.Copy(io.Discard, resp.Body)
+	return nil
+}
+
+// checkHTTPResponse returns an error describing resp if it isn't successful.
+func checkHTTPResponse(resp *http.Response) error {
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+
+	data, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+	if json.Valid(data) {
+		return fmt.Errorf("%v: %w", resp.Status, newHTTPError(data))
+	}
+	return fmt.Errorf("%v: %w", resp.Status, &httpError{
+		code:    codeFromHTTPStatus(resp.StatusCode),
+		message: string(bytes.TrimSpace(data)),
+	})
+}
+
+// newHTTPError creates an error from a Tink server HTTP error payload.
+func newHTTPError(data []byte) error {
+	var payload struct {
+		Code    string `json:"code"`
+		Message string `json:"message"`
+	}
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return fmt.Errorf("unknown server error: %s", data)
+	}
+	return &httpError{code: parseCode(payload.Code), message: payload.Message}
+}
+
+// httpError is an error returned by the Tink server HTTP workflow API. It carries the gRPC code
+// of the underlying error so callers can use status.Code.
+type httpError struct {
+	code    codes.Code
+	message string
+}
+
+func (e *httpError) Error() string {
+	return e.code.String() + ": " + e.message
+}
+
+func (e *httpError) GRPCStatus() *status.Status {
+	return status.New(e.code, e.message)
+}
+
+// parseCode parses the name of a gRPC code as written by the Tink server. Unrecognized names are
+// codes.Unknown.
+func parseCode(name string) codes.Code {
+	for c := codes.OK; c <= codes.Unauthenticated; c++ {
+		if c.String() == name {
+			return c
+		}
+	}
+	return codes.Unknown
+}
+
+// codeFromHTTPStatus maps HTTP status codes of responses without an error payload, typically from
+// intermediaries, to gRPC codes.
+func codeFromHTTPStatus(code int) codes.Code {
+	switch code {
+	case http.StatusBadRequest:
+		return codes.InvalidArgument
+	case http.StatusUnauthorized:
+		return codes.Unauthenticated
+	case http.StatusForbidden:
+		return codes.PermissionDenied
+	case http.StatusNotImplemented:
+		return codes.Unimplemented
+	case http.StatusServiceUnavailable:
+		return codes.Unavailable
+	default:
+		return codes.Unknown
+	}
+}
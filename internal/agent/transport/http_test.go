@@ -0,0 +1,274 @@
+package transport_test
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-logr/zerologr"
+	"github.com/google/go-cmp/cmp"
+	"github.com/rs/zerolog"
+	"github.com/tinkerbell/tink/internal/agent/event"
+	"github.com/tinkerbell/tink/internal/agent/transport"
+	"github.com/tinkerbell/tink/internal/agent/workflow"
+	workflowproto "github.com/tinkerbell/tink/internal/proto/workflow/v2"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/testing/protocmp"
+)
+
+func TestHTTP(t *testing.T) {
+	logger := zerolog.New(zerolog.NewConsoleWriter())
+
+	start, err := protojson.Marshal(&workflowproto.GetWorkflowsResponse{
+		Cmd: &workflowproto.GetWorkflowsResponse_StartWorkflow_{
+			StartWorkflow: &workflowproto.GetWorkflowsResponse_StartWorkflow{
+				Workflow: &workflowproto.Workflow{
+					WorkflowId: "ns/wf",
+					Actions:    []*workflowproto.Workflow_Action{{Id: "action", Image: "alpine"}},
+				},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	stop, err := protojson.Marshal(&workflowproto.GetWorkflowsResponse{
+		Cmd: &workflowproto.GetWorkflowsResponse_StopWorkflow_{
+			StopWorkflow: &workflowproto.GetWorkflowsResponse_StopWorkflow{WorkflowId: "ns/wf"},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var (
+		mtx         sync.Mutex
+		published   []*workflowproto.PublishEventRequest
+		connections int
+	)
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /v2/agents/{agentID}/workflows", func(w http.ResponseWriter, r *http.Request) {
+		// The agent reconnects when the stream closes.
+		if connections++; connections > 1 {
+			cancel()
+			return
+		}
+		if id := r.PathValue("agentID"); id != "agent" {
+			t.Errorf("Unexpected agent ID: %v", id)
+		}
+		if diff := cmp.Diff([]string{"ns/running"}, r.URL.Query()["running"]); diff != "" {
+			t.Errorf("Unexpected running workflows: %v", diff)
+		}
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprintf(w, ": connected\n\ndata: %s\n\n: keepalive\n\ndata: %s\n\n", start, stop)
+	})
+	mux.HandleFunc("POST /v2/events", func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		var req workflowproto.PublishEventRequest
+		if err := protojson.Unmarshal(body, &req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		mtx.Lock()
+		published = append(published, &req)
+		mtx.Unlock()
+		_, _ = w.Write([]byte("{}"))
+	})
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	h := transport.NewHTTP(
+		zerologr.New(&logger),
+		ts.URL,
+		ts.Client(),
+		transport.WithHTTPReconnectBackoff(time.Millisecond, 10*time.Millisecond),
+	)
+
+	var (
+		workflows []workflow.Workflow
+		canceled  []string
+	)
+	handler := &transport.WorkflowHandlerMock{
+		HandleWorkflowFunc: func(ctx context.Context, w workflow.Workflow, r event.Recorder) {
+			workflows = append(workflows, w)
+			if err := r.RecordEvent(ctx, event.ActionStarted{WorkflowID: w.ID, ActionID: "action"}); err != nil {
+				t.Error(err)
+			}
+		},
+		CancelWorkflowFunc: func(_ context.Context, id string, _ event.Recorder) {
+			canceled = append(canceled, id)
+		},
+		RunningWorkflowsFunc: func() []string { return []string{"ns/running"} },
+	}
+
+	if err := h.Start(ctx, "agent", handler); err != nil {
+		t.Fatal(err)
+	}
+	if connections != 2 {
+		t.Fatalf("Expected 2 connections; Received %v", connections)
+	}
+
+	expectWorkflows := []workflow.Workflow{{
+		ID:      "ns/wf",
+		Actions: []workflow.Action{{ID: "action", Image: "alpine"}},
+	}}
+	if diff := cmp.Diff(expectWorkflows, workflows); diff != "" {
+		t.Fatal(diff)
+	}
+	if diff := cmp.Diff([]string{"ns/wf"}, canceled); diff != "" {
+		t.Fatal(diff)
+	}
+
+	expectPublished := []*workflowproto.PublishEventRequest{{
+		Event: &workflowproto.Event{
+			WorkflowId: "ns/wf",
+			Event: &workflowproto.Event_ActionStarted_{
+				ActionStarted: &workflowproto.Event_ActionStarted{ActionId: "action"},
+			},
+		},
+	}}
+	if diff := cmp.Diff(expectPublished, published, protocmp.Transform()); diff != "" {
+		t.Fatal(diff)
+	}
+}
+
+func TestHTTP_Errors(t *testing.T) {
+	logger := zerolog.New(zerolog.NewConsoleWriter())
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /v2/agents/{agentID}/workflows", func(w http.ResponseWriter, r *http.Request) {
+		if r.PathValue("agentID") == "forbidden" {
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte(`{"code": "PermissionDenied", "message": "denied"}`))
+			return
+		}
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, "event: error\ndata: {\"code\": \"Unauthenticated\", \"message\": \"missing credentials\"}\n\n")
+	})
+	mux.HandleFunc("POST /v2/events", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"code": "NotFound", "message": "workflow not found"}`))
+	})
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	h := transport.NewHTTP(zerologr.New(&logger), ts.URL, ts.Client())
+	handler := &transport.WorkflowHandlerMock{
+		RunningWorkflowsFunc: func() []string { return nil },
+	}
+
+	for agentID, expect := range map[string]string{
+		"forbidden": "PermissionDenied: denied",
+		"agent":     "Unauthenticated: missing credentials",
+	} {
+		err := h.Start(context.Background(), agentID, handler)
+		if err == nil || !strings.Contains(err.Error(), expect) {
+			t.Fatalf("Expected error containing %q; Received: %v", expect, err)
+		}
+	}
+
+	err := h.RecordEvent(context.Background(), event.ActionStarted{WorkflowID: "ns/wf", ActionID: "action"})
+	if err == nil || !strings.Contains(err.Error(), "NotFound: workflow not found") {
+		t.Fatalf("Expected not found error; Received: %v", err)
+	}
+}
+
+func TestHTTP_Reconnect(t *testing.T) {
+	logger := zerolog.New(zerolog.NewConsoleWriter())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var running [][]string
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /v2/agents/{agentID}/workflows", func(w http.ResponseWriter, r *http.Request) {
+		running = append(running, r.URL.Query()["running"])
+		switch len(running) {
+		case 1:
+			w.Header().Set("Content-Type", "text/event-stream")
+			fmt.Fprint(w, "event: error\ndata: {\"code\": \"Internal\", \"message\": \"boom\"}\n\n")
+		case 2:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		default:
+			cancel()
+		}
+	})
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	handler := &transport.WorkflowHandlerMock{
+		RunningWorkflowsFunc: func() []string { return []string{"ns/running"} },
+	}
+
+	h := transport.NewHTTP(
+		zerologr.New(&logger),
+		ts.URL,
+		ts.Client(),
+		transport.WithHTTPReconnectBackoff(time.Millisecond, 10*time.Millisecond),
+	)
+	if err := h.Start(ctx, "agent", handler); err != nil {
+		t.Fatal(err)
+	}
+
+	expect := [][]string{{"ns/running"}, {"ns/running"}, {"ns/running"}}
+	if diff := cmp.Diff(expect, running); diff != "" {
+		t.Fatal(diff)
+	}
+}
+
+func TestHTTP_Heartbeat(t *testing.T) {
+	logger := zerolog.New(zerolog.NewConsoleWriter())
+
+	var received workflowproto.HeartbeatRequest
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /v2/heartbeats", func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if err := protojson.Unmarshal(body, &received); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		_, _ = w.Write([]byte("{}"))
+	})
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	h := transport.NewHTTP(zerologr.New(&logger), ts.URL, ts.Client())
+	err := h.Heartbeat(context.Background(), transport.Heartbeat{
+		AgentID:          "agent",
+		Version:          "v1.0.0",
+		Runtime:          "docker",
+		FreeDiskBytes:    1024,
+		RunningWorkflows: []string{"ns/wf"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expect := &workflowproto.HeartbeatRequest{
+		AgentId:            "agent",
+		Version:            "v1.0.0",
+		Runtime:            "docker",
+		FreeDiskBytes:      1024,
+		RunningWorkflowIds: []string{"ns/wf"},
+	}
+	if diff := cmp.Diff(expect, &received, protocmp.Transform()); diff != "" {
+		t.Fatal(diff)
+	}
+}
@@ -21,12 +21,15 @@ var _ WorkflowHandler = &WorkflowHandlerMock{}
 //
 //		// make and configure a mocked WorkflowHandler
 //		mockedWorkflowHandler := &WorkflowHandlerMock{
-//			CancelWorkflowFunc: func(workflowID string)  {
+//			CancelWorkflowFunc: func(ctx context.Context, workflowID string, events event.Recorder)  {
 //				panic("mock out the CancelWorkflow method")
 //			},
 //			HandleWorkflowFunc: func(contextMoqParam context.Context, workflowMoqParam workflow.Workflow, recorder event.Recorder)  {
 //				panic("mock out the HandleWorkflow method")
 //			},
+//			RunningWorkflowsFunc: func() []string {
+//				panic("mock out the RunningWorkflows method")
+//			},
 //		}
 //
 //		// use mockedWorkflowHandler in code that requires WorkflowHandler
@@ -35,17 +38,24 @@ var _ WorkflowHandler = &WorkflowHandlerMock{}
 //	}
 type WorkflowHandlerMock struct {
 	// CancelWorkflowFunc mocks the CancelWorkflow method.
-	CancelWorkflowFunc func(workflowID string)
+	CancelWorkflowFunc func(ctx context.Context, workflowID string, events event.Recorder)
 
 	// HandleWorkflowFunc mocks the HandleWorkflow method.
 	HandleWorkflowFunc func(contextMoqParam context.Context, workflowMoqParam workflow.Workflow, recorder event.Recorder)
 
+	// RunningWorkflowsFunc mocks the RunningWorkflows method.
+	RunningWorkflowsFunc func() []string
+
 	// calls tracks calls to the methods.
 	calls struct {
 		// CancelWorkflow holds details about calls to the CancelWorkflow method.
 		CancelWorkflow []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
 			// WorkflowID is the workflowID argument value.
 			WorkflowID string
+			// Events is the events argument value.
+			Events event.Recorder
 		}
 		// HandleWorkflow holds details about calls to the HandleWorkflow method.
 		HandleWorkflow []struct {
@@ -56,25 +66,33 @@ type WorkflowHandlerMock struct {
 			// Recorder is the recorder argument value.
 			Recorder event.Recorder
 		}
+		// RunningWorkflows holds details about calls to the RunningWorkflows method.
+		RunningWorkflows []struct {
+		}
 	}
-	lockCancelWorkflow sync.RWMutex
-	lockHandleWorkflow sync.RWMutex
+	lockCancelWorkflow   sync.RWMutex
+	lockHandleWorkflow   sync.RWMutex
+	lockRunningWorkflows sync.RWMutex
 }
 
 // CancelWorkflow calls CancelWorkflowFunc.
-func (mock *WorkflowHandlerMock) CancelWorkflow(workflowID string) {
+func (mock *WorkflowHandlerMock) CancelWorkflow(ctx context.Context, workflowID string, events event.Recorder) {
 	if mock.CancelWorkflowFunc == nil {
 		panic("WorkflowHandlerMock.CancelWorkflowFunc: method is nil but WorkflowHandler.CancelWorkflow was just called")
 	}
 	callInfo := struct {
+		Ctx        context.Context
 		WorkflowID string
+		Events     event.Recorder
 	}{
+		Ctx:        ctx,
 		WorkflowID: workflowID,
+		Events:     events,
 	}
 	mock.lockCancelWorkflow.Lock()
 	mock.calls.CancelWorkflow = append(mock.calls.CancelWorkflow, callInfo)
 	mock.lockCancelWorkflow.Unlock()
-	mock.CancelWorkflowFunc(workflowID)
+	mock.CancelWorkflowFunc(ctx, workflowID, events)
 }
 
 // CancelWorkflowCalls gets all the calls that were made to CancelWorkflow.
@@ -82,10 +100,14 @@ func (mock *WorkflowHandlerMock) CancelWorkflow(workflowID string) {
 //
 //	len(mockedWorkflowHandler.CancelWorkflowCalls())
 func (mock *WorkflowHandlerMock) CancelWorkflowCalls() []struct {
+	Ctx        context.Context
 	WorkflowID string
+	Events     event.Recorder
 } {
 	var calls []struct {
+		Ctx        context.Context
 		WorkflowID string
+		Events     event.Recorder
 	}
 	mock.lockCancelWorkflow.RLock()
 	calls = mock.calls.CancelWorkflow
@@ -132,3 +154,30 @@ func (mock *WorkflowHandlerMock) HandleWorkflowCalls() []struct {
 	mock.lockHandleWorkflow.RUnlock()
 	return calls
 }
+
+// RunningWorkflows calls RunningWorkflowsFunc.
+func (mock *WorkflowHandlerMock) RunningWorkflows() []string {
+	if mock.RunningWorkflowsFunc == nil {
+		panic("WorkflowHandlerMock.RunningWorkflowsFunc: method is nil but WorkflowHandler.RunningWorkflows was just called")
+	}
+	callInfo := struct {
+	}{}
+	mock.lockRunningWorkflows.Lock()
+	mock.calls.RunningWorkflows = append(mock.calls.RunningWorkflows, callInfo)
+	mock.lockRunningWorkflows.Unlock()
+	return mock.RunningWorkflowsFunc()
+}
+
+// RunningWorkflowsCalls gets all the calls that were made to RunningWorkflows.
+// Check the length with:
+//
+//	len(mockedWorkflowHandler.RunningWorkflowsCalls())
+func (mock *WorkflowHandlerMock) RunningWorkflowsCalls() []struct {
+} {
+	var calls []struct {
+	}
+	mock.lockRunningWorkflows.RLock()
+	calls = mock.calls.RunningWorkflows
+	mock.lockRunningWorkflows.RUnlock()
+	return calls
+}
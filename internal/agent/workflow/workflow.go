@@ -2,6 +2,8 @@
 // /internal/workflow at a later date when they are required/we transition to the new codebase.
 package workflow
 
+import "time"
+
 // Workflow represents a runnable workflow for the Handler.
 type Workflow struct {
 	// Do we need a workflow name? Does that even come down in the proto definition?
@@ -23,6 +25,26 @@ type Action struct {
 	Env              map[string]string `yaml:"env"`
 	Volumes          []string          `yaml:"volumes"`
 	NetworkNamespace string            `yaml:"networkNamespace"`
+	PIDNamespace     string            `yaml:"pidNamespace"`
+
+	// Retries is the number of times the action is retried after failing.
+	Retries int `yaml:"retries"`
+
+	// Backoff is the delay before the first retry. The delay doubles for each subsequent retry.
+	Backoff time.Duration `yaml:"backoff"`
+
+	// RetryOn restricts retries to failures with a matching exit code or failure reason. When
+	// empty, all failures are retried.
+	RetryOn []string `yaml:"retryOn"`
+
+	// Group identifies a group of actions executed concurrently. Adjacent actions with the same
+	// non-empty Group form a group. The workflow proceeds once all actions in a group have
+	// finished and fails if any of them failed.
+	Group string `yaml:"group"`
+
+	// When is a condition that must evaluate to true for the action to run. Actions whose
+	// condition evaluates to false are skipped. When empty, the action always runs.
+	When string `yaml:"when"`
 }
 
 func (a Action) String() string {
@@ -31,3 +53,17 @@ func (a Action) String() string {
 	// retrieving names.
 	return a.ID
 }
+
+// Groups splits actions into the groups they're executed in. Actions without a Group are
+// executed alone.
+func Groups(actions []Action) [][]Action {
+	var groups [][]Action
+	for i, action := range actions {
+		if i > 0 && action.Group != "" && action.Group == actions[i-1].Group {
+			groups[len(groups)-1] = append(groups[len(groups)-1], action)
+			continue
+		}
+		groups = append(groups, []Action{action})
+	}
+	return groups
+}
@@ -0,0 +1,293 @@
+// Package bolt provides an embedded, file backed store for Tinkerbell objects. It allows
+// tink-server to run without a Kubernetes control plane.
+package bolt
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/tinkerbell/tink/api/v1alpha1"
+	bbolt "go.etcd.io/bbolt"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var (
+	// ErrNotFound indicates the requested object does not exist.
+	ErrNotFound = errors.New("not found")
+
+	// ErrAlreadyExists indicates an object with the same namespace and name already exists.
+	ErrAlreadyExists = errors.New("already exists")
+)
+
+const (
+	hardwareBucket = "hardware"
+	templateBucket = "templates"
+	workflowBucket = "workflows"
+	workerBucket   = "workers"
+)
+
+// Store persists Hardware, Template, Workflow and Worker objects in a bbolt database. Objects are
+// keyed by namespace and name.
+type Store struct {
+	db      *bbolt.DB
+	nowFunc func() time.Time
+}
+
+// Open opens, creating if necessary, the database at path.
+func Open(path string) (*Store, error) {
+	db, err := bbolt.Open(path, 0o600, &bbolt.Options{Timeout: time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("open bolt database: %w", err)
+	}
+
+	err = db.Update(func(tx *bbolt.Tx) error {
+		for _, name := range []string{hardwareBucket, templateBucket, workflowBucket, workerBucket} {
+			if _, err := tx.CreateBucketIfNotExists([]byte(name)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("create bolt buckets: %w", err)
+	}
+
+	return &Store{db: db, nowFunc: time.Now}, nil
+}
+
+// Close closes the underlying database.
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// CreateHardware stores hw. It returns ErrAlreadyExists if hw has already been stored.
+func (s *Store) CreateHardware(hw *v1alpha1.Hardware) error {
+	return create(s, hardwareBucket, hw)
+}
+
+// GetHardware retrieves the Hardware identified by namespace and name.
+func (s *Store) GetHardware(namespace, name string) (*v1alpha1.Hardware, error) {
+	return get[v1alpha1.Hardware](s, hardwareBucket, namespace, name)
+}
+
+// ListHardware retrieves all Hardware in namespace. An empty namespace lists all namespaces.
+func (s *Store) ListHardware(namespace string) ([]v1alpha1.Hardware, error) {
+	return list[v1alpha1.Hardware](s, hardwareBucket, namespace)
+}
+
+// UpdateHardware replaces the stored Hardware with hw.
+func (s *Store) UpdateHardware(hw *v1alpha1.Hardware) error {
+	return update(s, hardwareBucket, hw)
+}
+
+// DeleteHardware removes the Hardware identified by namespace and name.
+func (s *Store) DeleteHardware(namespace, name string) error {
+	return remove(s, hardwareBucket, namespace, name)
+}
+
+// CreateTemplate stores tpl. It returns ErrAlreadyExists if tpl has already been stored.
+func (s *Store) CreateTemplate(tpl *v1alpha1.Template) error {
+	return create(s, templateBucket, tpl)
+}
+
+// GetTemplate retrieves the Template identified by namespace and name.
+func (s *Store) GetTemplate(namespace, name string) (*v1alpha1.Template, error) {
+	return get[v1alpha1.Template](s, templateBucket, namespace, name)
+}
+
+// ListTemplates retrieves all Templates in namespace. An empty namespace lists all namespaces.
+func (s *Store) ListTemplates(namespace string) ([]v1alpha1.Template, error) {
+	return list[v1alpha1.Template](s, templateBucket, namespace)
+}
+
+// UpdateTemplate replaces the stored Template with tpl.
+func (s *Store) UpdateTemplate(tpl *v1alpha1.Template) error {
+	return update(s, templateBucket, tpl)
+}
+
+// DeleteTemplate removes the Template identified by namespace and name.
+func (s *Store) DeleteTemplate(namespace, name string) error {
+	return remove(s, templateBucket, namespace, name)
+}
+
+// CreateWorkflow stores wf. It returns ErrAlreadyExists if wf has already been stored.
+func (s *Store) CreateWorkflow(wf *v1alpha1.Workflow) error {
+	return create(s, workflowBucket, wf)
+}
+
+// GetWorkflow retrieves the Workflow identified by namespace and name.
+func (s *Store) GetWorkflow(namespace, name string) (*v1alpha1.Workflow, error) {
+	return get[v1alpha1.Workflow](s, workflowBucket, namespace, name)
+}
+
+// ListWorkflows retrieves all Workflows in namespace. An empty namespace lists all namespaces.
+func (s *Store) ListWorkflows(namespace string) ([]v1alpha1.Workflow, error) {
+	return list[v1alpha1.Workflow](s, workflowBucket, namespace)
+}
+
+// UpdateWorkflow replaces the stored Workflow with wf.
+func (s *Store) UpdateWorkflow(wf *v1alpha1.Workflow) error {
+	return update(s, workflowBucket, wf)
+}
+
+// ModifyWorkflow atomically applies fn to the Workflow identified by namespace and name. If fn
+// returns an error the Workflow is not modified and the error is returned.
+func (s *Store) ModifyWorkflow(namespace, name string, fn func(*v1alpha1.Workflow) error) error {
+	return s.db.Update(func(tx *bbolt.Tx) error {
+		b := tx.Bucket([]byte(workflowBucket))
+		k := key(namespace, name)
+
+		stored := b.Get(k)
+		if stored == nil {
+			return fmt.Errorf("workflow %s: %w", k, ErrNotFound)
+		}
+
+		var wf v1alpha1.Workflow
+		if err := json.Unmarshal(stored, &wf); err != nil {
+			return err
+		}
+
+		if err := fn(&wf); err != nil {
+			return err
+		}
+
+		modified, err := json.Marshal(&wf)
+		if err != nil {
+			return err
+		}
+		if bytes.Equal(stored, modified) {
+			return nil
+		}
+		return b.Put(k, modified)
+	})
+}
+
+// DeleteWorkflow removes the Workflow identified by namespace and name.
+func (s *Store) DeleteWorkflow(namespace, name string) error {
+	return remove(s, workflowBucket, namespace, name)
+}
+
+// CreateWorker stores w. It returns ErrAlreadyExists if w has already been stored.
+func (s *Store) CreateWorker(w *v1alpha1.Worker) error {
+	return create(s, workerBucket, w)
+}
+
+// GetWorker retrieves the Worker identified by namespace and name.
+func (s *Store) GetWorker(namespace, name string) (*v1alpha1.Worker, error) {
+	return get[v1alpha1.Worker](s, workerBucket, namespace, name)
+}
+
+// ListWorkers retrieves all Workers in namespace. An empty namespace lists all namespaces.
+func (s *Store) ListWorkers(namespace string) ([]v1alpha1.Worker, error) {
+	return list[v1alpha1.Worker](s, workerBucket, namespace)
+}
+
+// UpdateWorker replaces the stored Worker with w.
+func (s *Store) UpdateWorker(w *v1alpha1.Worker) error {
+	return update(s, workerBucket, w)
+}
+
+// DeleteWorker removes the Worker identified by namespace and name.
+func (s *Store) DeleteWorker(namespace, name string) error {
+	return remove(s, workerBucket, namespace, name)
+}
+
+// object is implemented by all objects persisted in the store.
+type object interface {
+	GetNamespace() string
+	GetName() string
+	SetCreationTimestamp(metav1.Time)
+}
+
+func key(namespace, name string) []byte {
+	return []byte(namespace + "/" + name)
+}
+
+func create(s *Store, bucket string, obj object) error {
+	if obj.GetName() == "" {
+		return errors.New("name is required")
+	}
+	obj.SetCreationTimestamp(metav1.NewTime(s.nowFunc()))
+
+	return s.db.Update(func(tx *bbolt.Tx) error {
+		b := tx.Bucket([]byte(bucket))
+		k := key(obj.GetNamespace(), obj.GetName())
+		if b.Get(k) != nil {
+			return fmt.Errorf("%s %s: %w", bucket, k, ErrAlreadyExists)
+		}
+
+		data, err := json.Marshal(obj)
+		if err != nil {
+			return err
+		}
+		return b.Put(k, data)
+	})
+}
+
+func get[T any](s *Store, bucket, namespace, name string) (*T, error) {
+	var obj T
+	err := s.db.View(func(tx *bbolt.Tx) error {
+		k := key(namespace, name)
+		data := tx.Bucket([]byte(bucket)).Get(k)
+		if data == nil {
+			return fmt.Errorf("%s %s: %w", bucket, k, ErrNotFound)
+		}
+		return json.Unmarshal(data, &obj)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &obj, nil
+}
+
+func list[T any](s *Store, bucket, namespace string) ([]T, error) {
+	var objs []T
+	err := s.db.View(func(tx *bbolt.Tx) error {
+		var prefix []byte
+		if namespace != "" {
+			prefix = key(namespace, "")
+		}
+
+		c := tx.Bucket([]byte(bucket)).Cursor()
+		for k, v := c.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, v = c.Next() {
+			var obj T
+			if err := json.Unmarshal(v, &obj); err != nil {
+				return fmt.Errorf("decode %s %s: %w", bucket, k, err)
+			}
+			objs = append(objs, obj)
+		}
+		return nil
+	})
+	return objs, err
+}
+
+func update(s *Store, bucket string, obj object) error {
+	return s.db.Update(func(tx *bbolt.Tx) error {
+		b := tx.Bucket([]byte(bucket))
+		k := key(obj.GetNamespace(), obj.GetName())
+		if b.Get(k) == nil {
+			return fmt.Errorf("%s %s: %w", bucket, k, ErrNotFound)
+		}
+
+		data, err := json.Marshal(obj)
+		if err != nil {
+			return err
+		}
+		return b.Put(k, data)
+	})
+}
+
+func remove(s *Store, bucket, namespace, name string) error {
+	return s.db.Update(func(tx *bbolt.Tx) error {
+		b := tx.Bucket([]byte(bucket))
+		k := key(namespace, name)
+		if b.Get(k) == nil {
+			return fmt.Errorf("%s %s: %w", bucket, k, ErrNotFound)
+		}
+		return b.Delete(k)
+	})
+}
@@ -0,0 +1,163 @@
+package bolt
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/tinkerbell/tink/api/v1alpha1"
+	"github.com/tinkerbell/tink/internal/testtime"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var testTime = testtime.NewFrozenTimeUnix(1637361793)
+
+func newTestStore(t *testing.T) *Store {
+	t.Helper()
+
+	s, err := Open(filepath.Join(t.TempDir(), "tink.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.nowFunc = testTime.Now
+	t.Cleanup(func() { _ = s.Close() })
+	return s
+}
+
+func TestStore_Hardware(t *testing.T) {
+	s := newTestStore(t)
+
+	hw := &v1alpha1.Hardware{
+		ObjectMeta: metav1.ObjectMeta{Name: "machine1", Namespace: "default"},
+		Spec: v1alpha1.HardwareSpec{
+			Interfaces: []v1alpha1.Interface{
+				{DHCP: &v1alpha1.DHCP{MAC: "3c:ec:ef:4c:4f:54"}},
+			},
+		},
+	}
+	if err := s.CreateHardware(hw); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.CreateHardware(hw); !errors.Is(err, ErrAlreadyExists) {
+		t.Fatalf("expected ErrAlreadyExists, got %v", err)
+	}
+
+	got, err := s.GetHardware("default", "machine1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(hw, got); diff != "" {
+		t.Fatalf("unexpected hardware (-want +got):\n%s", diff)
+	}
+	if !got.CreationTimestamp.Equal(&metav1.Time{Time: testTime.Now()}) {
+		t.Fatalf("expected creation timestamp %v, got %v", testTime.Now(), got.CreationTimestamp.Time)
+	}
+
+	hw.Spec.Interfaces[0].DHCP.Hostname = "machine1"
+	if err := s.UpdateHardware(hw); err != nil {
+		t.Fatal(err)
+	}
+	got, err = s.GetHardware("default", "machine1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Spec.Interfaces[0].DHCP.Hostname != "machine1" {
+		t.Fatalf("expected updated hostname, got %q", got.Spec.Interfaces[0].DHCP.Hostname)
+	}
+
+	if err := s.DeleteHardware("default", "machine1"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.GetHardware("default", "machine1"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+	if err := s.DeleteHardware("default", "machine1"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+	if err := s.UpdateHardware(hw); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestStore_ListTemplates(t *testing.T) {
+	s := newTestStore(t)
+
+	for _, tpl := range []*v1alpha1.Template{
+		{ObjectMeta: metav1.ObjectMeta{Name: "a", Namespace: "default"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "b", Namespace: "default"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "a", Namespace: "other"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "c", Namespace: "default-2"}},
+	} {
+		if err := s.CreateTemplate(tpl); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cases := []struct {
+		namespace string
+		want      []string
+	}{
+		{namespace: "", want: []string{"default-2/c", "default/a", "default/b", "other/a"}},
+		{namespace: "default", want: []string{"default/a", "default/b"}},
+		{namespace: "other", want: []string{"other/a"}},
+		{namespace: "missing", want: nil},
+	}
+	for _, tc := range cases {
+		t.Run(tc.namespace, func(t *testing.T) {
+			tpls, err := s.ListTemplates(tc.namespace)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []string
+			for _, tpl := range tpls {
+				got = append(got, tpl.Namespace+"/"+tpl.Name)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Fatalf("unexpected templates (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestStore_ModifyWorkflow(t *testing.T) {
+	s := newTestStore(t)
+
+	wf := &v1alpha1.Workflow{
+		ObjectMeta: metav1.ObjectMeta{Name: "wf", Namespace: "default"},
+		Status:     v1alpha1.WorkflowStatus{State: v1alpha1.WorkflowStatePending},
+	}
+	if err := s.CreateWorkflow(wf); err != nil {
+		t.Fatal(err)
+	}
+
+	err := s.ModifyWorkflow("default", "wf", func(wf *v1alpha1.Workflow) error {
+		wf.Status.State = v1alpha1.WorkflowStateRunning
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	errAbort := errors.New("abort")
+	err = s.ModifyWorkflow("default", "wf", func(wf *v1alpha1.Workflow) error {
+		wf.Status.State = v1alpha1.WorkflowStateFailed
+		return errAbort
+	})
+	if !errors.Is(err, errAbort) {
+		t.Fatalf("expected abort error, got %v", err)
+	}
+
+	got, err := s.GetWorkflow("default", "wf")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Status.State != v1alpha1.WorkflowStateRunning {
+		t.Fatalf("expected state %v, got %v", v1alpha1.WorkflowStateRunning, got.Status.State)
+	}
+
+	err = s.ModifyWorkflow("default", "missing", func(*v1alpha1.Workflow) error { return nil })
+	if !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+}
@@ -2,22 +2,58 @@ package cli
 
 import (
 	"fmt"
+	"os"
+	"strings"
+	"time"
 
 	"github.com/go-logr/zapr"
 	"github.com/spf13/cobra"
 	"github.com/tinkerbell/tink/internal/agent"
 	"github.com/tinkerbell/tink/internal/agent/runtime"
 	"github.com/tinkerbell/tink/internal/agent/transport"
+	"github.com/tinkerbell/tink/internal/client"
 	"github.com/tinkerbell/tink/internal/proto/workflow/v2"
 	"go.uber.org/zap"
-	"google.golang.org/grpc"
+)
+
+const (
+	runtimeDocker     = "docker"
+	runtimeContainerd = "containerd"
+	runtimeProcess    = "process"
+
+	transportGRPC = "grpc"
+	transportHTTP = "http"
+	transportFile = "file"
 )
 
 // NewAgent builds a command that launches the agent component.
-func NewAgent() *cobra.Command {
+func NewAgent(version string) *cobra.Command {
 	var opts struct {
-		AgentID        string
-		TinkServerAddr string
+		AgentID            string
+		TinkServerAddr     string
+		TinkServerHTTPAddr string
+
+		TLS         bool
+		TLSInsecure bool
+		TLSCAFile   string
+		TLSCertFile string
+		TLSKeyFile  string
+		TokenFile   string
+
+		Transport           string
+		FileTransportDir    string
+		FileTransportLogDir string
+		FileTransportEvents string
+
+		Runtime             string
+		ContainerdAddress   string
+		ContainerdNamespace string
+		ProcessImageDir     string
+		Privileged          bool
+
+		HeartbeatInterval time.Duration
+
+		MaxConcurrentWorkflows int
 	}
 
 	// TODO(chrisdoherty4) Handle signals
@@ -30,23 +66,85 @@ func NewAgent() *cobra.Command {
 			}
 			logger := zapr.NewLogger(zl)
 
-			rntime, err := runtime.NewDocker()
+			var rntime agent.ContainerRuntime
+			switch opts.Runtime {
+			case runtimeDocker:
+				rntime, err = runtime.NewDocker(
+					runtime.WithLogger(logger),
+					runtime.WithPrivileged(opts.Privileged),
+				)
+			case runtimeContainerd:
+				rntime, err = runtime.NewContainerd(
+					opts.ContainerdAddress,
+					runtime.WithContainerdLogger(logger),
+					runtime.WithContainerdNamespace(opts.ContainerdNamespace),
+					runtime.WithContainerdPrivileged(opts.Privileged),
+				)
+			case runtimeProcess:
+				rntime = runtime.NewProcess(
+					runtime.WithProcessLogger(logger),
+					runtime.WithProcessImageDir(opts.ProcessImageDir),
+				)
+			default:
+				return fmt.Errorf("unknown runtime: %v", opts.Runtime)
+			}
 			if err != nil {
 				return fmt.Errorf("create runtime: %w", err)
 			}
 
-			conn, err := grpc.NewClient(opts.TinkServerAddr)
-			if err != nil {
-				return fmt.Errorf("dial tink server: %w", err)
+			clientOpts := []client.Option{
+				client.WithRootCA(opts.TLSCAFile),
+				client.WithClientCertificate(opts.TLSCertFile, opts.TLSKeyFile),
+			}
+			if opts.TokenFile != "" && opts.Transport != transportFile {
+				token, err := os.ReadFile(opts.TokenFile)
+				if err != nil {
+					return fmt.Errorf("read token: %w", err)
+				}
+				clientOpts = append(clientOpts, client.WithBearerToken(strings.TrimSpace(string(token))))
+			}
+
+			var trnport agent.Transport
+			switch opts.Transport {
+			case transportGRPC:
+				conn, err := client.NewClientConn(opts.TinkServerAddr, opts.TLS, opts.TLSInsecure, clientOpts...)
+				if err != nil {
+					return fmt.Errorf("dial tink server: %w", err)
+				}
+				defer conn.Close()
+				trnport = transport.NewGRPC(logger, workflow.NewWorkflowServiceClient(conn))
+			case transportHTTP:
+				httpClient, err := client.NewHTTPClient(opts.TLS, opts.TLSInsecure, clientOpts...)
+				if err != nil {
+					return fmt.Errorf("create http client: %w", err)
+				}
+				scheme := "http"
+				if opts.TLS {
+					scheme = "https"
+				}
+				trnport = transport.NewHTTP(logger, scheme+"://"+opts.TinkServerHTTPAddr, httpClient)
+			case transportFile:
+				trnport = &transport.File{
+					Log:        logger,
+					Dir:        opts.FileTransportDir,
+					LogDir:     opts.FileTransportLogDir,
+					EventsFile: opts.FileTransportEvents,
+				}
+			default:
+				return fmt.Errorf("unknown transport: %v", opts.Transport)
 			}
-			defer conn.Close()
-			trnport := transport.NewGRPC(logger, workflow.NewWorkflowServiceClient(conn))
 
 			return (&agent.Agent{
-				Log:       logger,
-				ID:        opts.AgentID,
-				Transport: trnport,
-				Runtime:   rntime,
+				Log:                    logger,
+				ID:                     opts.AgentID,
+				Transport:              trnport,
+				Runtime:                rntime,
+				MaxConcurrentWorkflows: opts.MaxConcurrentWorkflows,
+				Heartbeat: agent.HeartbeatConfig{
+					Interval: opts.HeartbeatInterval,
+					Version:  version,
+					Runtime:  opts.Runtime,
+				},
 			}).Start(cmd.Context())
 		},
 	}
@@ -54,6 +152,24 @@ func NewAgent() *cobra.Command {
 	flgs := cmd.Flags()
 	flgs.StringVar(&opts.AgentID, "agent-id", "", "An ID that uniquely identifies the agent instance")
 	flgs.StringVar(&opts.TinkServerAddr, "tink-server-addr", "127.0.0.1:42113", "Tink server address")
+	flgs.StringVar(&opts.TinkServerHTTPAddr, "tink-server-http-addr", "127.0.0.1:42115", "Tink server HTTP workflow API address. Used with the http transport")
+	flgs.BoolVar(&opts.TLS, "tink-server-tls", false, "Connect to the Tink server using TLS")
+	flgs.BoolVar(&opts.TLSInsecure, "tink-server-insecure-tls", false, "Skip verification of the Tink server certificate")
+	flgs.StringVar(&opts.TLSCAFile, "tink-server-ca-file", "", "CAs used to verify the Tink server certificate instead of the system CAs")
+	flgs.StringVar(&opts.TLSCertFile, "tls-cert-file", "", "A client certificate presented to the Tink server. Its common name must match the agent ID")
+	flgs.StringVar(&opts.TLSKeyFile, "tls-key-file", "", "The key for the client certificate")
+	flgs.StringVar(&opts.TokenFile, "token-file", "", "A file containing a bearer token used to authenticate with the Tink server. Requires TLS")
+	flgs.StringVar(&opts.Transport, "transport", transportGRPC, fmt.Sprintf("The transport used to retrieve workflows. One of: %v, %v, %v", transportGRPC, transportHTTP, transportFile))
+	flgs.StringVar(&opts.FileTransportDir, "file-transport-dir", ".", "The directory watched for workflow files. Used with the file transport")
+	flgs.StringVar(&opts.FileTransportLogDir, "file-transport-log-dir", "", "The directory action output is written to. Output is discarded when unset. Used with the file transport")
+	flgs.StringVar(&opts.FileTransportEvents, "file-transport-events-file", "", "A file events from all workflows are appended to in addition to each workflow's events file. Used with the file transport")
+	flgs.StringVar(&opts.Runtime, "runtime", runtimeDocker, fmt.Sprintf("The container runtime used to run actions. One of: %v, %v, %v", runtimeDocker, runtimeContainerd, runtimeProcess))
+	flgs.StringVar(&opts.ContainerdAddress, "containerd-address", runtime.DefaultContainerdAddress, "The containerd socket address. Used with the containerd runtime")
+	flgs.StringVar(&opts.ContainerdNamespace, "containerd-namespace", runtime.DefaultContainerdNamespace, "The containerd namespace actions are launched in. Used with the containerd runtime")
+	flgs.StringVar(&opts.ProcessImageDir, "process-image-dir", runtime.DefaultProcessImageDir, "The directory image root filesystems are extracted to or pre-staged in. Used with the process runtime")
+	flgs.DurationVar(&opts.HeartbeatInterval, "heartbeat-interval", agent.DefaultHeartbeatInterval, "The interval at which heartbeats are sent to the Tink server. Negative values disable heartbeats. Used with the grpc and http transports")
+	flgs.IntVar(&opts.MaxConcurrentWorkflows, "max-concurrent-workflows", 1, "The maximum number of workflows executed concurrently")
+	flgs.BoolVar(&opts.Privileged, "privileged", true, "Launch action containers in privileged mode granting access to host devices")
 
 	return &cmd
 }
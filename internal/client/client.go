@@ -1,7 +1,12 @@
 package client
 
 import (
+	"context"
 	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"os"
 
 	"github.com/pkg/errors"
 	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
@@ -10,18 +15,144 @@ import (
 	"google.golang.org/grpc/credentials/insecure"
 )
 
-func NewClientConn(authority string, tlsEnabled bool, tlsInsecure bool) (*grpc.ClientConn, error) {
+// Option configures a client connection created with NewClientConn or NewHTTPClient.
+type Option func(*options)
+
+type options struct {
+	caFile   string
+	certFile string
+	keyFile  string
+	token    string
+}
+
+// WithRootCA verifies the server certificate against the CAs in caFile instead of the system
+// CA pool. It only takes effect when TLS is enabled.
+func WithRootCA(caFile string) Option {
+	return func(o *options) {
+		o.caFile = caFile
+	}
+}
+
+// WithClientCertificate presents the certificate and key to the server for mutual TLS. It only
+// takes effect when TLS is enabled.
+func WithClientCertificate(certFile, keyFile string) Option {
+	return func(o *options) {
+		o.certFile = certFile
+		o.keyFile = keyFile
+	}
+}
+
+// WithBearerToken authenticates every request with token. It requires TLS.
+func WithBearerToken(token string) Option {
+	return func(o *options) {
+		o.token = token
+	}
+}
+
+func NewClientConn(authority string, tlsEnabled bool, tlsInsecure bool, opts ...Option) (*grpc.ClientConn, error) {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+
 	var creds grpc.DialOption
 	if tlsEnabled { // #nosec G402
-		creds = grpc.WithTransportCredentials(credentials.NewTLS(&tls.Config{InsecureSkipVerify: tlsInsecure}))
+		cfg := &tls.Config{InsecureSkipVerify: tlsInsecure}
+		if err := o.configureTLS(cfg); err != nil {
+			return nil, err
+		}
+		creds = grpc.WithTransportCredentials(credentials.NewTLS(cfg))
 	} else {
 		creds = grpc.WithTransportCredentials(insecure.NewCredentials())
 	}
 
-	conn, err := grpc.NewClient(authority, creds, grpc.WithStatsHandler(otelgrpc.NewClientHandler()))
+	dialOpts := []grpc.DialOption{creds, grpc.WithStatsHandler(otelgrpc.NewClientHandler())}
+	if o.token != "" {
+		dialOpts = append(dialOpts, grpc.WithPerRPCCredentials(bearerToken(o.token)))
+	}
+
+	conn, err := grpc.NewClient(authority, dialOpts...)
 	if err != nil {
 		return nil, errors.Wrap(err, "dial tinkerbell server")
 	}
 
 	return conn, nil
 }
+
+// NewHTTPClient creates an HTTP client for the Tink server's HTTP APIs. It supports the same
+// options as NewClientConn.
+func NewHTTPClient(tlsEnabled bool, tlsInsecure bool, opts ...Option) (*http.Client, error) {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if tlsEnabled { // #nosec G402
+		cfg := &tls.Config{InsecureSkipVerify: tlsInsecure}
+		if err := o.configureTLS(cfg); err != nil {
+			return nil, err
+		}
+		transport.TLSClientConfig = cfg
+	}
+
+	if o.token != "" && !tlsEnabled {
+		return nil, errors.New("bearer tokens require TLS")
+	}
+
+	var rt http.RoundTripper = transport
+	if o.token != "" {
+		rt = bearerTokenTransport{token: o.token, next: transport}
+	}
+
+	return &http.Client{Transport: rt}, nil
+}
+
+func (o options) configureTLS(cfg *tls.Config) error {
+	if o.caFile != "" {
+		pem, err := os.ReadFile(o.caFile)
+		if err != nil {
+			return errors.Wrap(err, "read CA")
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return fmt.Errorf("no certificates found in %v", o.caFile)
+		}
+		cfg.RootCAs = pool
+	}
+
+	if o.certFile != "" || o.keyFile != "" {
+		cert, err := tls.LoadX509KeyPair(o.certFile, o.keyFile)
+		if err != nil {
+			return errors.Wrap(err, "load client certificate")
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+
+	return nil
+}
+
+// bearerToken provides a bearer token as per-RPC credentials.
+type bearerToken string
+
+func (t bearerToken) GetRequestMetadata(context.Context, ...string) (map[string]string, error) {
+	return map[string]string{"authorization": "Bearer " + string(t)}, nil
+}
+
+// RequireTransportSecurity prevents tokens being sent in plaintext.
+func (bearerToken) RequireTransportSecurity() bool {
+	return true
+}
+
+// bearerTokenTransport authenticates HTTP requests with a bearer token.
+type bearerTokenTransport struct {
+	token string
+	next  http.RoundTripper
+}
+
+func (t bearerTokenTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	// RoundTrippers must not modify the request.
+	r = r.Clone(r.Context())
+	r.Header.Set("Authorization", "Bearer "+t.token)
+	return t.next.RoundTrip(r)
+}
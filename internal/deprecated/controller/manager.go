@@ -27,8 +27,8 @@ func DefaultScheme() *runtime.Scheme {
 }
 
 // NewManager creates a new controller manager with tink controller controllers pre-registered.
-// If opts.Scheme is nil, DefaultScheme() is used.
-func NewManager(cfg *rest.Config, opts ctrl.Options) (ctrl.Manager, error) {
+// If opts.Scheme is nil, DefaultScheme() is used. workflowOpts configure the workflow reconciler.
+func NewManager(cfg *rest.Config, opts ctrl.Options, workflowOpts ...workflow.Option) (ctrl.Manager, error) {
 	if opts.Scheme == nil {
 		opts.Scheme = DefaultScheme()
 	}
@@ -46,7 +46,7 @@ func NewManager(cfg *rest.Config, opts ctrl.Options) (ctrl.Manager, error) {
 		return nil, fmt.Errorf("set up ready check: %w", err)
 	}
 
-	err = workflow.NewReconciler(mgr.GetClient()).SetupWithManager(mgr)
+	err = workflow.NewReconciler(mgr.GetClient(), workflowOpts...).SetupWithManager(mgr)
 	if err != nil {
 		return nil, fmt.Errorf("setup workflow reconciler: %w", err)
 	}
@@ -40,6 +40,11 @@ func YAMLToStatus(wf *Workflow) *v1alpha1.WorkflowStatus {
 				Status:      v1alpha1.WorkflowState(proto.State_name[int32(proto.State_STATE_PENDING)]),
 				Environment: action.Environment,
 				Pid:         action.Pid,
+				Retries:     action.Retries,
+				Backoff:     action.Backoff,
+				RetryOn:     action.RetryOn,
+				Group:       action.Group,
+				When:        action.When,
 			})
 		}
 		tasks = append(tasks, v1alpha1.Task{
@@ -90,7 +95,13 @@ func ActionListCRDToProto(wf *v1alpha1.Workflow) *proto.WorkflowActionList {
 					sort.Strings(resp)
 					return resp
 				}(task.Environment),
-				Pid: action.Pid,
+				Pid:     action.Pid,
+				Retries: int64(action.Retries),
+				Backoff: action.Backoff,
+				RetryOn: action.RetryOn,
+				Group:   action.Group,
+				When:    action.When,
+				Outputs: action.Outputs,
 			})
 		}
 	}
@@ -156,6 +156,9 @@ func TestActionListCRDToProto(t *testing.T) {
 									Volumes: []string{
 										"/tmp/debug:/tmp/debug",
 									},
+									Retries: 2,
+									Backoff: 10,
+									RetryOn: []string{"1"},
 								},
 								{
 									Name:    "kexec",
@@ -202,6 +205,9 @@ func TestActionListCRDToProto(t *testing.T) {
 							"/lib/firmware:/lib/firmware:ro",
 							"/tmp/debug:/tmp/debug",
 						},
+						Retries: 2,
+						Backoff: 10,
+						RetryOn: []string{"1"},
 					},
 					{
 						TaskName: "worker1",
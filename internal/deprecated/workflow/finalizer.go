@@ -0,0 +1,122 @@
+package workflow
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	rufio "github.com/tinkerbell/rufio/api/v1alpha1"
+	"github.com/tinkerbell/tink/api/v1alpha1"
+	"github.com/tinkerbell/tink/internal/deprecated/workflow/journal"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// isoEjectTimeout is the time after a Workflow is deleted that cleanup waits for an ISO to be
+// ejected. If the BMC is unreachable or the eject job fails, cleanup continues regardless so the
+// Workflow can be deleted.
+const isoEjectTimeout = 5 * time.Minute
+
+// cleanup releases resources created on behalf of a Workflow that is being deleted. It ejects
+// any ISO mounted by the Workflow, restores the Hardware's netboot settings and removes BMC jobs
+// created for the Workflow.
+// A zero reconcile.Result and nil error indicate cleanup is complete and the finalizer can be removed.
+// This function will update the Workflow status.
+func (s *state) cleanup(ctx context.Context, now time.Time) (reconcile.Result, error) {
+	// 1. Eject the ISO if we mounted one and it hasn't already been ejected.
+	if s.isoMounted() {
+		hw, err := hardwareFrom(ctx, s.client, s.workflow)
+		switch {
+		case errors.IsNotFound(err):
+			journal.Log(ctx, "hardware not found; skipping iso eject")
+		case err != nil:
+			return reconcile.Result{}, err
+		case hw.Spec.BMCRef == nil:
+			journal.Log(ctx, "hardware has no bmc; skipping iso eject")
+		default:
+			if r, err := s.ejectISO(ctx, now); err != nil || !r.IsZero() {
+				return r, err
+			}
+		}
+	}
+
+	// 2. Restore allowPXE if we toggled it on and it hasn't been toggled back off.
+	allowNetboot := s.workflow.Status.BootOptions.AllowNetboot
+	if s.workflow.Spec.BootOptions.ToggleAllowNetboot && allowNetboot.ToggledTrue && !allowNetboot.ToggledFalse {
+		journal.Log(ctx, "restoring allowPXE to false")
+		err := s.toggleHardware(ctx, false)
+		if err != nil && !errors.IsNotFound(err) {
+			return reconcile.Result{}, err
+		}
+	}
+
+	// 3. Remove any jobs created for the Workflow.
+	for _, name := range []jobName{jobNameNetboot, jobNameISOMount, jobNameISOEject} {
+		job := &rufio.Job{ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("%s-%s", name, s.workflow.GetName()),
+			Namespace: s.workflow.Namespace,
+		}}
+		journal.Log(ctx, "deleting job", "name", job.Name)
+		opts := []client.DeleteOption{
+			client.PropagationPolicy(metav1.DeletePropagationForeground),
+		}
+		if err := s.client.Delete(ctx, job, opts...); client.IgnoreNotFound(err) != nil {
+			return reconcile.Result{}, fmt.Errorf("error deleting job.bmc.tinkerbell.org object: %w", err)
+		}
+	}
+
+	return reconcile.Result{}, nil
+}
+
+// ejectISO ejects the ISO mounted by the Workflow. A zero reconcile.Result and nil error indicate
+// the ISO was ejected or the eject was abandoned because it didn't complete within
+// isoEjectTimeout of the Workflow's deletion.
+func (s *state) ejectISO(ctx context.Context, now time.Time) (reconcile.Result, error) {
+	journal.Log(ctx, "ejecting iso")
+	name := jobName(fmt.Sprintf("%s-%s", jobNameISOEject, s.workflow.GetName()))
+	actions := []rufio.Action{
+		{
+			VirtualMediaAction: &rufio.VirtualMediaAction{
+				MediaURL: "", // empty to unmount/eject the media
+				Kind:     rufio.VirtualMediaCD,
+			},
+		},
+	}
+	r, err := s.handleJob(ctx, actions, name)
+	if err == nil && s.workflow.Status.BootOptions.Jobs[name.String()].Complete {
+		return reconcile.Result{}, nil
+	}
+
+	if now.Before(s.workflow.DeletionTimestamp.Add(isoEjectTimeout)) {
+		return r, err
+	}
+
+	message := fmt.Sprintf("iso eject did not complete within %v of deletion", isoEjectTimeout)
+	if err != nil {
+		message = fmt.Sprintf("%v: %v", message, err)
+	}
+	ctrl.LoggerFrom(ctx).Info("Abandoning iso eject; continuing cleanup", "reason", message)
+	journal.Log(ctx, "abandoning iso eject", "reason", message)
+	s.workflow.Status.SetCondition(v1alpha1.WorkflowCondition{
+		Type:    v1alpha1.ISOEjectFailed,
+		Status:  metav1.ConditionTrue,
+		Reason:  "Timeout",
+		Message: message,
+		Time:    &metav1.Time{Time: now.UTC()},
+	})
+	return reconcile.Result{}, nil
+}
+
+// isoMounted returns true if the Workflow created a job to mount an ISO that hasn't subsequently
+// been ejected.
+func (s *state) isoMounted() bool {
+	if s.workflow.Spec.BootOptions.BootMode != v1alpha1.BootModeISO {
+		return false
+	}
+	mount := s.workflow.Status.BootOptions.Jobs[fmt.Sprintf("%s-%s", jobNameISOMount, s.workflow.GetName())]
+	eject := s.workflow.Status.BootOptions.Jobs[fmt.Sprintf("%s-%s", jobNameISOEject, s.workflow.GetName())]
+	return mount.UID != "" && !eject.Complete
+}
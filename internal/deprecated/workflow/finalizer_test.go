@@ -0,0 +1,161 @@
+package workflow
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	rufio "github.com/tinkerbell/rufio/api/v1alpha1"
+	"github.com/tinkerbell/tink/api/v1alpha1"
+	"github.com/tinkerbell/tink/internal/ptr"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func TestReconcileDeletedWorkflow(t *testing.T) {
+	tests := map[string]struct {
+		bootMode       v1alpha1.BootMode
+		deletedFor     time.Duration
+		jobs           map[string]v1alpha1.JobStatus
+		wantResult     reconcile.Result
+		wantDeleted    bool
+		wantAllowPXE   bool
+		wantJobDeleted bool
+	}{
+		"netboot jobs removed and allowPXE restored": {
+			bootMode: v1alpha1.BootModeNetboot,
+			jobs: map[string]v1alpha1.JobStatus{
+				"netboot-workflow": {ExistingJobDeleted: true, UID: types.UID("1234"), Complete: true},
+			},
+			wantDeleted:    true,
+			wantAllowPXE:   false,
+			wantJobDeleted: true,
+		},
+		"mounted iso is ejected before release": {
+			bootMode: v1alpha1.BootModeISO,
+			jobs: map[string]v1alpha1.JobStatus{
+				"iso-mount-workflow": {ExistingJobDeleted: true, UID: types.UID("1234"), Complete: true},
+			},
+			wantResult:     reconcile.Result{Requeue: true},
+			wantDeleted:    false,
+			wantAllowPXE:   true,
+			wantJobDeleted: false,
+		},
+		"iso eject abandoned after timeout": {
+			bootMode:   v1alpha1.BootModeISO,
+			deletedFor: isoEjectTimeout,
+			jobs: map[string]v1alpha1.JobStatus{
+				"iso-mount-workflow": {ExistingJobDeleted: true, UID: types.UID("1234"), Complete: true},
+				"iso-eject-workflow": {ExistingJobDeleted: true, UID: types.UID("5678")},
+			},
+			wantDeleted:    true,
+			wantAllowPXE:   false,
+			wantJobDeleted: true,
+		},
+		"ejected iso is released": {
+			bootMode: v1alpha1.BootModeISO,
+			jobs: map[string]v1alpha1.JobStatus{
+				"iso-mount-workflow": {ExistingJobDeleted: true, UID: types.UID("1234"), Complete: true},
+				"iso-eject-workflow": {ExistingJobDeleted: true, UID: types.UID("5678"), Complete: true},
+			},
+			wantDeleted:    true,
+			wantAllowPXE:   false,
+			wantJobDeleted: true,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			deleted := metav1.NewTime(TestTime.Now().Add(-tc.deletedFor))
+			wflw := &v1alpha1.Workflow{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:              "workflow",
+					Namespace:         "default",
+					DeletionTimestamp: &deleted,
+					Finalizers:        []string{v1alpha1.WorkflowFinalizer},
+				},
+				Spec: v1alpha1.WorkflowSpec{
+					HardwareRef: "machine1",
+					BootOptions: v1alpha1.BootOptions{
+						ToggleAllowNetboot: true,
+						BootMode:           tc.bootMode,
+						ISOURL:             "http://example.com/image.iso",
+					},
+				},
+				Status: v1alpha1.WorkflowStatus{
+					State: v1alpha1.WorkflowStateRunning,
+					BootOptions: v1alpha1.BootOptionsStatus{
+						AllowNetboot: v1alpha1.AllowNetbootStatus{ToggledTrue: true},
+						Jobs:         tc.jobs,
+					},
+				},
+			}
+			hw := &v1alpha1.Hardware{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "machine1",
+					Namespace: "default",
+				},
+				Spec: v1alpha1.HardwareSpec{
+					BMCRef: &v1.TypedLocalObjectReference{Name: "bmc", Kind: "machine.bmc.tinkerbell.org"},
+					Interfaces: []v1alpha1.Interface{
+						{
+							DHCP:    &v1alpha1.DHCP{MAC: "3c:ec:ef:4c:4f:54"},
+							Netboot: &v1alpha1.Netboot{AllowPXE: ptr.Bool(true)},
+						},
+					},
+				},
+			}
+			job := &rufio.Job{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "netboot-workflow",
+					Namespace: "default",
+				},
+			}
+
+			scheme := runtime.NewScheme()
+			_ = rufio.AddToScheme(scheme)
+			_ = v1alpha1.AddToScheme(scheme)
+			cc := fake.NewClientBuilder().
+				WithScheme(scheme).
+				WithObjects(wflw, hw, job).
+				WithStatusSubresource(wflw).
+				Build()
+
+			r := NewReconciler(cc)
+			r.nowFunc = TestTime.Now
+			result, err := r.Reconcile(context.Background(), reconcile.Request{
+				NamespacedName: client.ObjectKeyFromObject(wflw),
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if result != tc.wantResult {
+				t.Fatalf("expected result %v, got %v", tc.wantResult, result)
+			}
+
+			err = cc.Get(context.Background(), client.ObjectKeyFromObject(wflw), &v1alpha1.Workflow{})
+			if deleted := errors.IsNotFound(err); deleted != tc.wantDeleted {
+				t.Fatalf("expected workflow deleted: %v, got: %v (%v)", tc.wantDeleted, deleted, err)
+			}
+
+			gotHW := &v1alpha1.Hardware{}
+			if err := cc.Get(context.Background(), client.ObjectKeyFromObject(hw), gotHW); err != nil {
+				t.Fatal(err)
+			}
+			if got := *gotHW.Spec.Interfaces[0].Netboot.AllowPXE; got != tc.wantAllowPXE {
+				t.Fatalf("expected allowPXE: %v, got: %v", tc.wantAllowPXE, got)
+			}
+
+			err = cc.Get(context.Background(), client.ObjectKeyFromObject(job), &rufio.Job{})
+			if deleted := errors.IsNotFound(err); deleted != tc.wantJobDeleted {
+				t.Fatalf("expected job deleted: %v, got: %v (%v)", tc.wantJobDeleted, deleted, err)
+			}
+		})
+	}
+}
@@ -16,27 +16,43 @@ import (
 	"knative.dev/pkg/ptr"
 	ctrl "sigs.k8s.io/controller-runtime"
 	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 )
 
 // Reconciler is a type for managing Workflows.
 type Reconciler struct {
-	client  ctrlclient.Client
-	nowFunc func() time.Time
-	backoff *backoff.ExponentialBackOff
+	client        ctrlclient.Client
+	nowFunc       func() time.Time
+	backoff       *backoff.ExponentialBackOff
+	workerTimeout time.Duration
+}
+
+// Option configures a Reconciler.
+type Option func(*Reconciler)
+
+// WithWorkerTimeout fails running workflows whose worker hasn't sent a heartbeat for timeout.
+// Zero disables liveness checks.
+func WithWorkerTimeout(timeout time.Duration) Option {
+	return func(r *Reconciler) {
+		r.workerTimeout = timeout
+	}
 }
 
-// TODO(jacobweinstock): add functional arguments to the signature.
 // TODO(jacobweinstock): write functional argument for customizing the backoff.
-func NewReconciler(client ctrlclient.Client) *Reconciler {
-	return &Reconciler{
+func NewReconciler(client ctrlclient.Client, opts ...Option) *Reconciler {
+	r := &Reconciler{
 		client:  client,
 		nowFunc: time.Now,
 		backoff: backoff.NewExponentialBackOff([]backoff.ExponentialBackOffOpts{
 			backoff.WithMaxInterval(5 * time.Second), // this should keep all NextBackOff's under 10 seconds
 		}...),
 	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
 }
 
 func (r *Reconciler) SetupWithManager(mgr manager.Manager) error {
@@ -55,6 +71,8 @@ type state struct {
 // +kubebuilder:rbac:groups=tinkerbell.org,resources=hardware;hardware/status,verbs=get;list;watch;update;patch
 // +kubebuilder:rbac:groups=tinkerbell.org,resources=templates;templates/status,verbs=get;list;watch;update;patch
 // +kubebuilder:rbac:groups=tinkerbell.org,resources=workflows;workflows/status,verbs=get;list;watch;update;patch;delete
+// +kubebuilder:rbac:groups=tinkerbell.org,resources=workers,verbs=get;list;watch
+// +kubebuilder:rbac:groups=tinkerbell.org,resources=workflows/finalizers,verbs=update
 // +kubebuilder:rbac:groups=bmc.tinkerbell.org,resources=job;job/status,verbs=get;list;watch;delete;create
 
 // Reconcile handles Workflow objects. This includes Template rendering, optional Hardware allowPXE toggling, and optional Hardware one-time netbooting.
@@ -74,12 +92,26 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 		}
 		return reconcile.Result{}, err
 	}
-	if !stored.DeletionTimestamp.IsZero() {
-		return reconcile.Result{}, nil
-	}
 	if stored.Status.BootOptions.Jobs == nil {
 		stored.Status.BootOptions.Jobs = make(map[string]v1alpha1.JobStatus)
 	}
+	if !stored.DeletionTimestamp.IsZero() {
+		journal.Log(ctx, "workflow deleted")
+		return r.processDeletedWorkflow(ctx, stored)
+	}
+	if !controllerutil.ContainsFinalizer(stored, v1alpha1.WorkflowFinalizer) {
+		journal.Log(ctx, "adding finalizer")
+		patch := ctrlclient.MergeFrom(stored.DeepCopy())
+		controllerutil.AddFinalizer(stored, v1alpha1.WorkflowFinalizer)
+		if err := r.client.Patch(ctx, stored, patch); err != nil {
+			return reconcile.Result{}, fmt.Errorf("error adding finalizer to workflow: %s, error: %w", stored.Name, err)
+		}
+	}
+
+	if _, ok := stored.Annotations[v1alpha1.WorkflowResumeAnnotation]; ok {
+		journal.Log(ctx, "resume requested")
+		return r.processResumeRequest(ctx, stored)
+	}
 
 	wflow := stored.DeepCopy()
 
@@ -101,9 +133,9 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 		return resp, serrors.Join(err, mergePatchStatus(ctx, r.client, stored, s.workflow))
 	case v1alpha1.WorkflowStateRunning:
 		journal.Log(ctx, "process running workflow")
-		r.processRunningWorkflow(wflow)
+		resp, err := r.processRunningWorkflow(ctx, wflow)
 
-		return reconcile.Result{}, mergePatchStatus(ctx, r.client, stored, wflow)
+		return resp, serrors.Join(err, mergePatchStatus(ctx, r.client, stored, wflow))
 	case v1alpha1.WorkflowStatePost:
 		journal.Log(ctx, "post actions")
 		s := &state{
@@ -122,6 +154,36 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 	return reconcile.Result{}, nil
 }
 
+// processDeletedWorkflow cleans up resources created on behalf of the Workflow and removes the
+// finalizer once complete.
+func (r *Reconciler) processDeletedWorkflow(ctx context.Context, stored *v1alpha1.Workflow) (reconcile.Result, error) {
+	if !controllerutil.ContainsFinalizer(stored, v1alpha1.WorkflowFinalizer) {
+		return reconcile.Result{}, nil
+	}
+
+	s := &state{
+		client:   r.client,
+		workflow: stored.DeepCopy(),
+		backoff:  r.backoff,
+	}
+	resp, err := s.cleanup(ctx, r.nowFunc())
+	if err := mergePatchStatus(ctx, r.client, stored, s.workflow); err != nil {
+		return reconcile.Result{}, err
+	}
+	if err != nil || !resp.IsZero() {
+		return resp, err
+	}
+
+	journal.Log(ctx, "removing finalizer")
+	patch := ctrlclient.MergeFrom(s.workflow.DeepCopy())
+	controllerutil.RemoveFinalizer(s.workflow, v1alpha1.WorkflowFinalizer)
+	if err := r.client.Patch(ctx, s.workflow, patch); err != nil {
+		return reconcile.Result{}, fmt.Errorf("error removing finalizer from workflow: %s, error: %w", stored.Name, err)
+	}
+
+	return reconcile.Result{}, nil
+}
+
 // mergePatchStatus merges an updated Workflow with an original Workflow and patches the Status object via the client (cc).
 func mergePatchStatus(ctx context.Context, cc ctrlclient.Client, original, updated *v1alpha1.Workflow) error {
 	// Patch any changes, regardless of errors
@@ -200,14 +262,7 @@ func (r *Reconciler) processNewWorkflow(ctx context.Context, logger logr.Logger,
 		)
 	}
 
-	data := make(map[string]interface{})
-	for key, val := range stored.Spec.HardwareMap {
-		data[key] = val
-	}
-	contract := toTemplateHardwareData(hardware)
-	data["Hardware"] = contract
-
-	tinkWf, err := renderTemplateHardware(stored.Name, ptr.StringValue(tpl.Spec.Data), data)
+	tinkWf, err := RenderTemplate(stored, tpl, hardware)
 	if err != nil {
 		stored.Status.TemplateRendering = v1alpha1.TemplateRenderingFailed
 		stored.Status.SetCondition(v1alpha1.WorkflowCondition{
@@ -241,6 +296,17 @@ func (r *Reconciler) processNewWorkflow(ctx context.Context, logger logr.Logger,
 	return reconcile.Result{}, nil
 }
 
+// RenderTemplate renders tpl for wf using data from hardware and the Workflow's hardware map.
+func RenderTemplate(wf *v1alpha1.Workflow, tpl *v1alpha1.Template, hardware v1alpha1.Hardware) (*Workflow, error) {
+	data := make(map[string]interface{})
+	for key, val := range wf.Spec.HardwareMap {
+		data[key] = val
+	}
+	data["Hardware"] = toTemplateHardwareData(hardware)
+
+	return renderTemplateHardware(wf.Name, ptr.StringValue(tpl.Spec.Data), data)
+}
+
 // templateHardwareData defines the data exposed for a Hardware instance to a Template.
 type templateHardwareData struct {
 	Disks      []string
@@ -272,9 +338,40 @@ func toTemplateHardwareData(hardware v1alpha1.Hardware) templateHardwareData {
 	return contract
 }
 
-func (r *Reconciler) processRunningWorkflow(stored *v1alpha1.Workflow) {
-	// Check for global timeout expiration
-	if r.nowFunc().After(stored.GetStartTime().Add(time.Duration(stored.Status.GlobalTimeout) * time.Second)) {
+// processRunningWorkflow applies timeouts to a running Workflow and, if a worker timeout is
+// configured, fails it when its current worker stops sending heartbeats. Workers that have never
+// sent a heartbeat are unaffected as their liveness is unknown.
+func (r *Reconciler) processRunningWorkflow(ctx context.Context, stored *v1alpha1.Workflow) (reconcile.Result, error) {
+	now := r.nowFunc()
+	CheckTimeouts(stored, now)
+	if r.workerTimeout <= 0 || stored.Status.State != v1alpha1.WorkflowStateRunning {
+		return reconcile.Result{}, nil
+	}
+
+	workerID := stored.GetCurrentWorker()
+	if workerID == "" {
+		return reconcile.Result{}, nil
+	}
+	var worker v1alpha1.Worker
+	key := ctrlclient.ObjectKey{Namespace: stored.Namespace, Name: v1alpha1.WorkerName(workerID)}
+	if err := r.client.Get(ctx, key, &worker); err != nil {
+		return reconcile.Result{}, ctrlclient.IgnoreNotFound(err)
+	}
+
+	remaining := CheckWorkerLiveness(stored, worker.Status.LastSeen.Time, now, r.workerTimeout)
+	return reconcile.Result{RequeueAfter: remaining}, nil
+}
+
+// CheckTimeouts marks a running Workflow, and any running action, as timed out if they have
+// exceeded their timeouts. It also updates the current action in the Workflow status.
+func CheckTimeouts(stored *v1alpha1.Workflow, now time.Time) {
+	// Check for global timeout expiration. Resumed workflows are given the full timeout from the
+	// point they were resumed.
+	start := stored.GetStartTime()
+	if resumed := resumedAt(stored); resumed != nil && (start == nil || resumed.After(start.Time)) {
+		start = resumed
+	}
+	if now.After(start.Add(time.Duration(stored.Status.GlobalTimeout) * time.Second)) {
 		stored.Status.State = v1alpha1.WorkflowStateTimeout
 	}
 
@@ -283,11 +380,11 @@ func (r *Reconciler) processRunningWorkflow(stored *v1alpha1.Workflow) {
 		for ai, action := range task.Actions {
 			// A running workflow task action has timed out
 			if action.Status == v1alpha1.WorkflowStateRunning && action.StartedAt != nil &&
-				r.nowFunc().After(action.StartedAt.Add(time.Duration(action.Timeout)*time.Second)) {
+				now.After(action.StartedAt.Add(time.Duration(action.Timeout)*time.Second)) {
 				// Set fields on the timed out action
 				stored.Status.Tasks[ti].Actions[ai].Status = v1alpha1.WorkflowStateTimeout
 				stored.Status.Tasks[ti].Actions[ai].Message = "Action timed out"
-				stored.Status.Tasks[ti].Actions[ai].Seconds = int64(r.nowFunc().Sub(action.StartedAt.Time).Seconds())
+				stored.Status.Tasks[ti].Actions[ai].Seconds = int64(now.Sub(action.StartedAt.Time).Seconds())
 				// Mark the workflow as timed out
 				stored.Status.State = v1alpha1.WorkflowStateTimeout
 			}
@@ -298,3 +395,41 @@ func (r *Reconciler) processRunningWorkflow(stored *v1alpha1.Workflow) {
 		}
 	}
 }
+
+// CheckWorkerLiveness fails a running Workflow whose current worker last sent a heartbeat at
+// lastSeen if the worker has been silent for timeout. Silence is measured from the later of the
+// heartbeat and the Workflow starting or resuming so a stale heartbeat doesn't fail a Workflow
+// that has just started. It returns the time remaining before the worker is considered lost,
+// which is zero once the Workflow has failed.
+func CheckWorkerLiveness(stored *v1alpha1.Workflow, lastSeen, now time.Time, timeout time.Duration) time.Duration {
+	since := lastSeen
+	if start := stored.GetStartTime(); start != nil && start.After(since) {
+		since = start.Time
+	}
+	if resumed := resumedAt(stored); resumed != nil && resumed.After(since) {
+		since = resumed.Time
+	}
+
+	if remaining := since.Add(timeout).Sub(now); remaining > 0 {
+		return remaining
+	}
+
+	message := fmt.Sprintf("Worker sent no heartbeat for %v", timeout)
+	for ti, task := range stored.Status.Tasks {
+		for ai, action := range task.Actions {
+			if action.Status == v1alpha1.WorkflowStateRunning {
+				stored.Status.Tasks[ti].Actions[ai].Status = v1alpha1.WorkflowStateFailed
+				stored.Status.Tasks[ti].Actions[ai].Message = message
+			}
+		}
+	}
+	stored.Status.SetCondition(v1alpha1.WorkflowCondition{
+		Type:    v1alpha1.WorkerLost,
+		Status:  metav1.ConditionTrue,
+		Reason:  "HeartbeatTimeout",
+		Message: message,
+		Time:    &metav1.Time{Time: now},
+	})
+	stored.Status.State = v1alpha1.WorkflowStateFailed
+	return 0
+}
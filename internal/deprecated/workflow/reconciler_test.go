@@ -5,6 +5,7 @@ import (
 	"errors"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
@@ -313,7 +314,8 @@ func TestReconcile(t *testing.T) {
 					APIVersion: "tinkerbell.org/v1alpha1",
 				},
 				ObjectMeta: metav1.ObjectMeta{
-					ResourceVersion: "1000",
+					ResourceVersion: "1001",
+					Finalizers:      []string{v1alpha1.WorkflowFinalizer},
 					Name:            "debian",
 					Namespace:       "default",
 				},
@@ -674,7 +676,8 @@ tasks:
 					APIVersion: "tinkerbell.org/v1alpha1",
 				},
 				ObjectMeta: metav1.ObjectMeta{
-					ResourceVersion: "1000",
+					ResourceVersion: "1001",
+					Finalizers:      []string{v1alpha1.WorkflowFinalizer},
 					Name:            "debian",
 					Namespace:       "default",
 				},
@@ -894,7 +897,8 @@ tasks:
 					APIVersion: "tinkerbell.org/v1alpha1",
 				},
 				ObjectMeta: metav1.ObjectMeta{
-					ResourceVersion: "1000",
+					ResourceVersion: "1001",
+					Finalizers:      []string{v1alpha1.WorkflowFinalizer},
 					Name:            "debian",
 					Namespace:       "default",
 				},
@@ -1005,3 +1009,69 @@ tasks:
 		})
 	}
 }
+
+func TestProcessRunningWorkflowWorkerLiveness(t *testing.T) {
+	cases := map[string]struct {
+		lastSeen   *metav1.Time
+		wantState  v1alpha1.WorkflowState
+		wantResult reconcile.Result
+	}{
+		"no heartbeat": {
+			wantState: v1alpha1.WorkflowStateRunning,
+		},
+		"recent heartbeat": {
+			lastSeen:   TestTime.MetaV1BeforeSec(10),
+			wantState:  v1alpha1.WorkflowStateRunning,
+			wantResult: reconcile.Result{RequeueAfter: 20 * time.Second},
+		},
+		"stale heartbeat": {
+			lastSeen:  TestTime.MetaV1BeforeSec(40),
+			wantState: v1alpha1.WorkflowStateFailed,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			wflw := &v1alpha1.Workflow{
+				ObjectMeta: metav1.ObjectMeta{Name: "workflow", Namespace: "default"},
+				Status: v1alpha1.WorkflowStatus{
+					State:         v1alpha1.WorkflowStateRunning,
+					GlobalTimeout: 600,
+					Tasks: []v1alpha1.Task{
+						{
+							Name:       "task",
+							WorkerAddr: "3c:ec:ef:4c:4f:54",
+							Actions: []v1alpha1.Action{
+								{Name: "action", Status: v1alpha1.WorkflowStateRunning, StartedAt: TestTime.MetaV1BeforeSec(60), Timeout: 300},
+							},
+						},
+					},
+				},
+			}
+
+			builder := GetFakeClientBuilder()
+			if tc.lastSeen != nil {
+				builder = builder.WithObjects(&v1alpha1.Worker{
+					ObjectMeta: metav1.ObjectMeta{Name: "3c-ec-ef-4c-4f-54", Namespace: "default"},
+					Status:     v1alpha1.WorkerStatus{WorkerID: "3c:ec:ef:4c:4f:54", LastSeen: *tc.lastSeen},
+				})
+			}
+			r := NewReconciler(builder.Build(), WithWorkerTimeout(30*time.Second))
+			r.nowFunc = TestTime.Now
+
+			result, err := r.processRunningWorkflow(context.Background(), wflw)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if result != tc.wantResult {
+				t.Fatalf("expected result %v, got %v", tc.wantResult, result)
+			}
+			if wflw.Status.State != tc.wantState {
+				t.Fatalf("expected state %v, got %v", tc.wantState, wflw.Status.State)
+			}
+			lost := wflw.Status.HasCondition(v1alpha1.WorkerLost, metav1.ConditionTrue)
+			if lost != (tc.wantState == v1alpha1.WorkflowStateFailed) {
+				t.Fatalf("unexpected %v condition: %v", v1alpha1.WorkerLost, wflw.Status.Conditions)
+			}
+		})
+	}
+}
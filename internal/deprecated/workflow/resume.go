@@ -0,0 +1,94 @@
+package workflow
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/tinkerbell/tink/api/v1alpha1"
+	"github.com/tinkerbell/tink/internal/deprecated/workflow/journal"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// processResumeRequest handles the resume annotation on a Workflow. Failed and timed out Workflows
+// are resumed from the action that failed, other Workflows are left untouched. The annotation is
+// always removed so a request doesn't linger and unexpectedly resume the Workflow later.
+func (r *Reconciler) processResumeRequest(ctx context.Context, stored *v1alpha1.Workflow) (reconcile.Result, error) {
+	wflow := stored.DeepCopy()
+
+	switch wflow.Status.State {
+	case v1alpha1.WorkflowStateFailed, v1alpha1.WorkflowStateTimeout:
+		journal.Log(ctx, "resuming workflow")
+		resume(wflow, r.nowFunc())
+		if err := mergePatchStatus(ctx, r.client, stored, wflow); err != nil {
+			return reconcile.Result{}, err
+		}
+	default:
+		journal.Log(ctx, "ignoring resume request", "state", wflow.Status.State)
+	}
+
+	journal.Log(ctx, "removing resume annotation")
+	patch := ctrlclient.MergeFrom(wflow.DeepCopy())
+	delete(wflow.Annotations, v1alpha1.WorkflowResumeAnnotation)
+	if err := r.client.Patch(ctx, wflow, patch); err != nil {
+		return reconcile.Result{}, fmt.Errorf("error removing resume annotation from workflow: %s, error: %w", stored.Name, err)
+	}
+
+	return reconcile.Result{Requeue: true}, nil
+}
+
+// resume resets the first action that didn't succeed, and all actions after it, to pending.
+// Actions that succeeded or were skipped before it are kept. If the Workflow has boot options, their status is
+// reset and the Workflow is returned to the preparing state so the boot options are performed
+// again and the machine can pick up where it left off.
+func resume(wf *v1alpha1.Workflow, now time.Time) {
+	var from string
+	for ti := range wf.Status.Tasks {
+		for ai := range wf.Status.Tasks[ti].Actions {
+			action := &wf.Status.Tasks[ti].Actions[ai]
+			if from == "" && (action.Status == v1alpha1.WorkflowStateSuccess || action.Status == v1alpha1.WorkflowStateSkipped) {
+				continue
+			}
+			if from == "" {
+				from = action.Name
+			}
+			action.Status = v1alpha1.WorkflowStatePending
+			action.StartedAt = nil
+			action.Seconds = 0
+			action.Message = ""
+			action.Attempts = nil
+			action.Outputs = nil
+		}
+	}
+
+	wf.Status.CurrentAction = ""
+	wf.Status.SetCondition(v1alpha1.WorkflowCondition{
+		Type:    v1alpha1.WorkflowResumed,
+		Status:  metav1.ConditionTrue,
+		Reason:  "Resumed",
+		Message: fmt.Sprintf("resumed from action %q", from),
+		Time:    &metav1.Time{Time: now},
+	})
+
+	if wf.Spec.BootOptions.ToggleAllowNetboot || wf.Spec.BootOptions.BootMode != "" {
+		wf.Status.BootOptions = v1alpha1.BootOptionsStatus{
+			Jobs: make(map[string]v1alpha1.JobStatus),
+		}
+		wf.Status.State = v1alpha1.WorkflowStatePreparing
+		return
+	}
+
+	wf.Status.State = v1alpha1.WorkflowStatePending
+}
+
+// resumedAt returns the time the Workflow was last resumed, or nil if it has never been resumed.
+func resumedAt(wf *v1alpha1.Workflow) *metav1.Time {
+	for _, c := range wf.Status.Conditions {
+		if c.Type == v1alpha1.WorkflowResumed && c.Status == metav1.ConditionTrue {
+			return c.Time
+		}
+	}
+	return nil
+}
@@ -0,0 +1,186 @@
+package workflow
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/tinkerbell/tink/api/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func TestReconcileResumeWorkflow(t *testing.T) {
+	started := TestTime.MetaV1BeforeSec(60)
+
+	tests := map[string]struct {
+		state           v1alpha1.WorkflowState
+		bootOptions     v1alpha1.BootOptions
+		wantState       v1alpha1.WorkflowState
+		wantActions     []v1alpha1.Action
+		wantBootOptions v1alpha1.BootOptionsStatus
+	}{
+		"failed workflow resumes from failed action": {
+			state:     v1alpha1.WorkflowStateFailed,
+			wantState: v1alpha1.WorkflowStatePending,
+			wantActions: []v1alpha1.Action{
+				{Name: "action1", Status: v1alpha1.WorkflowStateSuccess, StartedAt: started, Seconds: 10},
+				{Name: "action2", Status: v1alpha1.WorkflowStatePending},
+				{Name: "action3", Status: v1alpha1.WorkflowStatePending},
+			},
+			wantBootOptions: v1alpha1.BootOptionsStatus{
+				AllowNetboot: v1alpha1.AllowNetbootStatus{ToggledTrue: true},
+				Jobs: map[string]v1alpha1.JobStatus{
+					"netboot-workflow": {UID: types.UID("1234"), Complete: true},
+				},
+			},
+		},
+		"timed out workflow with boot options is prepared again": {
+			state:       v1alpha1.WorkflowStateTimeout,
+			bootOptions: v1alpha1.BootOptions{ToggleAllowNetboot: true, BootMode: v1alpha1.BootModeNetboot},
+			wantState:   v1alpha1.WorkflowStatePreparing,
+			wantActions: []v1alpha1.Action{
+				{Name: "action1", Status: v1alpha1.WorkflowStateSuccess, StartedAt: started, Seconds: 10},
+				{Name: "action2", Status: v1alpha1.WorkflowStatePending},
+				{Name: "action3", Status: v1alpha1.WorkflowStatePending},
+			},
+			wantBootOptions: v1alpha1.BootOptionsStatus{},
+		},
+		"running workflow is not modified": {
+			state:     v1alpha1.WorkflowStateRunning,
+			wantState: v1alpha1.WorkflowStateRunning,
+			wantActions: []v1alpha1.Action{
+				{Name: "action1", Status: v1alpha1.WorkflowStateSuccess, StartedAt: started, Seconds: 10},
+				{
+					Name:      "action2",
+					Status:    v1alpha1.WorkflowStateFailed,
+					StartedAt: started,
+					Seconds:   5,
+					Message:   "failed",
+					Attempts:  []v1alpha1.ActionAttempt{{Attempt: 1, Status: v1alpha1.WorkflowStateFailed}},
+				},
+				{Name: "action3", Status: v1alpha1.WorkflowStatePending},
+			},
+			wantBootOptions: v1alpha1.BootOptionsStatus{
+				AllowNetboot: v1alpha1.AllowNetbootStatus{ToggledTrue: true},
+				Jobs: map[string]v1alpha1.JobStatus{
+					"netboot-workflow": {UID: types.UID("1234"), Complete: true},
+				},
+			},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			wflw := &v1alpha1.Workflow{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "workflow",
+					Namespace:   "default",
+					Finalizers:  []string{v1alpha1.WorkflowFinalizer},
+					Annotations: map[string]string{v1alpha1.WorkflowResumeAnnotation: "true"},
+				},
+				Spec: v1alpha1.WorkflowSpec{
+					HardwareRef: "machine1",
+					BootOptions: tc.bootOptions,
+				},
+				Status: v1alpha1.WorkflowStatus{
+					State:         tc.state,
+					GlobalTimeout: 600,
+					BootOptions: v1alpha1.BootOptionsStatus{
+						AllowNetboot: v1alpha1.AllowNetbootStatus{ToggledTrue: true},
+						Jobs: map[string]v1alpha1.JobStatus{
+							"netboot-workflow": {UID: types.UID("1234"), Complete: true},
+						},
+					},
+					Tasks: []v1alpha1.Task{
+						{
+							Name: "task",
+							Actions: []v1alpha1.Action{
+								{Name: "action1", Status: v1alpha1.WorkflowStateSuccess, StartedAt: started, Seconds: 10},
+								{
+									Name:      "action2",
+									Status:    v1alpha1.WorkflowStateFailed,
+									StartedAt: started,
+									Seconds:   5,
+									Message:   "failed",
+									Attempts:  []v1alpha1.ActionAttempt{{Attempt: 1, Status: v1alpha1.WorkflowStateFailed}},
+								},
+								{Name: "action3", Status: v1alpha1.WorkflowStatePending},
+							},
+						},
+					},
+				},
+			}
+
+			scheme := runtime.NewScheme()
+			_ = v1alpha1.AddToScheme(scheme)
+			cc := fake.NewClientBuilder().
+				WithScheme(scheme).
+				WithObjects(wflw).
+				WithStatusSubresource(wflw).
+				Build()
+
+			r := NewReconciler(cc)
+			r.nowFunc = TestTime.Now
+			_, err := r.Reconcile(context.Background(), reconcile.Request{
+				NamespacedName: client.ObjectKeyFromObject(wflw),
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got := &v1alpha1.Workflow{}
+			if err := cc.Get(context.Background(), client.ObjectKeyFromObject(wflw), got); err != nil {
+				t.Fatal(err)
+			}
+
+			if _, ok := got.Annotations[v1alpha1.WorkflowResumeAnnotation]; ok {
+				t.Fatal("expected resume annotation to be removed")
+			}
+			if got.Status.State != tc.wantState {
+				t.Fatalf("expected state %v, got %v", tc.wantState, got.Status.State)
+			}
+			if diff := cmp.Diff(tc.wantActions, got.Status.Tasks[0].Actions); diff != "" {
+				t.Errorf("unexpected actions:\n%v", diff)
+			}
+			if diff := cmp.Diff(tc.wantBootOptions, got.Status.BootOptions, cmpopts.EquateEmpty()); diff != "" {
+				t.Errorf("unexpected boot options:\n%v", diff)
+			}
+		})
+	}
+}
+
+func TestProcessRunningWorkflowResumed(t *testing.T) {
+	wflw := &v1alpha1.Workflow{
+		Status: v1alpha1.WorkflowStatus{
+			State:         v1alpha1.WorkflowStateRunning,
+			GlobalTimeout: 600,
+			Tasks: []v1alpha1.Task{
+				{
+					Name: "task",
+					Actions: []v1alpha1.Action{
+						{Name: "action1", Status: v1alpha1.WorkflowStateSuccess, StartedAt: TestTime.MetaV1BeforeSec(3600), Timeout: 60},
+						{Name: "action2", Status: v1alpha1.WorkflowStateRunning, StartedAt: TestTime.MetaV1BeforeSec(10), Timeout: 60},
+					},
+				},
+			},
+			Conditions: []v1alpha1.WorkflowCondition{
+				{Type: v1alpha1.WorkflowResumed, Status: metav1.ConditionTrue, Time: TestTime.MetaV1BeforeSec(30)},
+			},
+		},
+	}
+
+	r := &Reconciler{nowFunc: TestTime.Now}
+	if _, err := r.processRunningWorkflow(context.Background(), wflw); err != nil {
+		t.Fatal(err)
+	}
+
+	if wflw.Status.State != v1alpha1.WorkflowStateRunning {
+		t.Fatalf("expected resumed workflow to be running, got %v", wflw.Status.State)
+	}
+}
@@ -3,17 +3,23 @@ package workflow
 import (
 	"bytes"
 	"fmt"
+	"strconv"
 	"text/template"
+	tplparse "text/template/parse"
 
 	"github.com/Masterminds/sprig/v3"
 	"github.com/distribution/reference"
 	"github.com/pkg/errors"
+	"github.com/tinkerbell/tink/internal/agent/condition"
 	"gopkg.in/yaml.v3"
 )
 
 const (
 	errInvalidLength   = "name cannot be empty or have more than 200 characters: %s"
 	errTemplateParsing = "failed to parse template with ID %s"
+
+	// maxRetries is the maximum number of times an action can be retried.
+	maxRetries = 100
 )
 
 // parse parses the template yaml content into a Workflow.
@@ -31,12 +37,17 @@ func parse(yamlContent []byte) (*Workflow, error) {
 	return &workflow, nil
 }
 
-// renderTemplateHardware renders the workflow template and returns the Workflow and the interpolated bytes.
-func renderTemplateHardware(templateID, templateData string, hardware map[string]interface{}) (*Workflow, error) {
-	t := template.New("workflow-template").
+// newTemplate returns a template configured with the functions available to workflow templates.
+func newTemplate() *template.Template {
+	return template.New("workflow-template").
 		Option("missingkey=error").
 		Funcs(sprig.FuncMap()).
 		Funcs(templateFuncs)
+}
+
+// renderTemplateHardware renders the workflow template and returns the Workflow and the interpolated bytes.
+func renderTemplateHardware(templateID, templateData string, hardware map[string]interface{}) (*Workflow, error) {
+	t := newTemplate()
 
 	_, err := t.Parse(templateData)
 	if err != nil {
@@ -64,6 +75,62 @@ func renderTemplateHardware(templateID, templateData string, hardware map[string
 	return wf, nil
 }
 
+// ValidateTemplateData validates workflow template data without Hardware data so invalid templates
+// can be rejected before a Workflow references them. The data must be a syntactically valid Go
+// template. When the template contains only text and actions, each action is substituted with a
+// placeholder and the resulting workflow is validated. Templates containing control structures,
+// such as if and range, can only be validated once rendered.
+func ValidateTemplateData(templateData string) error {
+	t, err := newTemplate().Parse(templateData)
+	if err != nil {
+		return errors.Wrap(err, "parsing template")
+	}
+
+	skeleton, actions, ok := renderSkeleton(t.Root)
+	if !ok {
+		return nil
+	}
+
+	var wf Workflow
+	if err := yaml.Unmarshal(skeleton, &wf); err != nil {
+		// Actions may render YAML structure, such as a block of environment variables, so the
+		// skeleton is only guaranteed to be valid YAML when there are no actions.
+		if actions > 0 {
+			return nil
+		}
+		return errors.Wrap(err, "parsing yaml data")
+	}
+
+	return errors.Wrap(validate(&wf), "validating workflow template")
+}
+
+// renderSkeleton renders root substituting each action with a unique numeric placeholder. Numeric
+// placeholders are valid names, image references and integers so templated fields pass
+// validation. It returns the number of substituted actions and false if root contains nodes other
+// than text and actions.
+func renderSkeleton(root *tplparse.ListNode) ([]byte, int, bool) {
+	var (
+		buf     bytes.Buffer
+		actions int
+	)
+	for _, node := range root.Nodes {
+		switch n := node.(type) {
+		case *tplparse.TextNode:
+			buf.Write(n.Text)
+		case *tplparse.ActionNode:
+			// Variable declarations don't render anything.
+			if len(n.Pipe.Decl) > 0 {
+				continue
+			}
+			buf.WriteString(strconv.Itoa(actions))
+			actions++
+		default:
+			return nil, 0, false
+		}
+	}
+	return buf.Bytes(), actions, true
+}
+
 // validate validates a workflow template against certain requirements.
 func validate(wf *Workflow) error {
 	if !hasValidLength(wf.Name) {
@@ -86,7 +153,8 @@ func validate(wf *Workflow) error {
 
 		taskNameMap[task.Name] = struct{}{}
 		actionNameMap := make(map[string]struct{})
-		for _, action := range task.Actions {
+		groupNameMap := make(map[string]struct{})
+		for i, action := range task.Actions {
 			if !hasValidLength(action.Name) {
 				return errors.Errorf(errInvalidLength, action.Name)
 			}
@@ -95,11 +163,31 @@ func validate(wf *Workflow) error {
 				return errors.Errorf("invalid action image (%s): %v", action.Image, err)
 			}
 
+			if action.Retries < 0 || action.Backoff < 0 {
+				return errors.Errorf("action retries and backoff cannot be negative: %s", action.Name)
+			}
+
+			if action.Retries > maxRetries {
+				return errors.Errorf("action retries cannot exceed %d: %s", maxRetries, action.Name)
+			}
+
+			if err := condition.Validate(action.When); err != nil {
+				return errors.Errorf("invalid action condition (%s): %v", action.Name, err)
+			}
+
 			_, ok := actionNameMap[action.Name]
 			if ok {
 				return errors.Errorf("two actions in a task cannot have same name: %s", action.Name)
 			}
 			actionNameMap[action.Name] = struct{}{}
+
+			// Groups are formed by adjacent actions so a group cannot be resumed later in the task.
+			if action.Group != "" && (i == 0 || task.Actions[i-1].Group != action.Group) {
+				if _, ok := groupNameMap[action.Group]; ok {
+					return errors.Errorf("actions in a group must be adjacent: %s", action.Group)
+				}
+				groupNameMap[action.Group] = struct{}{}
+			}
 		}
 	}
 	return nil
@@ -1,6 +1,7 @@
 package workflow
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -128,6 +129,38 @@ func TestValidateTemplate(t *testing.T) {
 			wf:            toWorkflow(withActionInvalidImage()),
 			expectedError: true,
 		},
+		{
+			name:          "action retries are negative",
+			wf:            toWorkflow(withActionNegativeRetries()),
+			expectedError: true,
+		},
+		{
+			name:          "action retries exceed maximum",
+			wf:            toWorkflow(withActionRetries(1000)),
+			expectedError: true,
+		},
+		{
+			name: "action has a retry policy",
+			wf:   toWorkflow(withActionRetryPolicy()),
+		},
+		{
+			name: "actions are grouped",
+			wf:   toWorkflow(withActionGroup(1, 2)),
+		},
+		{
+			name:          "action group is not adjacent",
+			wf:            toWorkflow(withActionGroup(0, 2)),
+			expectedError: true,
+		},
+		{
+			name: "action has a condition",
+			wf:   toWorkflow(withActionCondition(`gt 2 1`)),
+		},
+		{
+			name:          "action condition is invalid",
+			wf:            toWorkflow(withActionCondition(`eq (`)),
+			expectedError: true,
+		},
 		{
 			name: "valid task name",
 			wf:   toWorkflow(),
@@ -145,6 +178,119 @@ func TestValidateTemplate(t *testing.T) {
 	}
 }
 
+func TestValidateTemplateData(t *testing.T) {
+	testCases := []struct {
+		name          string
+		data          string
+		expectedError bool
+	}{
+		{
+			name: "valid template",
+			data: validTemplate,
+		},
+		{
+			name: "templated image",
+			data: `
+version: "0.1"
+name: hello_world_workflow
+global_timeout: {{ .timeout }}
+tasks:
+  - name: "hello world"
+    worker: "{{.device_1}}"
+    actions:
+    - name: "hello_world"
+      image: {{ .registry }}/hello-world:{{ .tag }}
+      timeout: 60
+`,
+		},
+		{
+			name: "templated yaml structure",
+			data: `
+version: "0.1"
+name: hello_world_workflow
+global_timeout: 600
+tasks:
+  - name: "hello world"
+    worker: "{{.device_1}}"
+    actions:
+    - name: "hello_world"
+      image: hello-world
+      timeout: 60
+      environment: {{- .env | nindent 8 }}
+`,
+		},
+		{
+			name: "control structures",
+			data: `
+version: "0.1"
+name: hello_world_workflow
+global_timeout: 600
+tasks:
+  - name: "hello world"
+    worker: "{{.device_1}}"
+    actions:
+    {{- range .disks }}
+    - name: "hello_world"
+      image: hello-world
+    {{- end }}
+`,
+		},
+		{
+			name:          "invalid go template",
+			data:          `name: {{ .name `,
+			expectedError: true,
+		},
+		{
+			name:          "invalid yaml",
+			data:          strings.ReplaceAll(invalidTemplate, `"{{.device_1}}"`, "worker"),
+			expectedError: true,
+		},
+		{
+			name: "invalid image",
+			data: `
+version: "0.1"
+name: hello_world_workflow
+global_timeout: 600
+tasks:
+  - name: "hello world"
+    worker: "{{.device_1}}"
+    actions:
+    - name: "hello_world"
+      image: Hello-World
+      timeout: 60
+`,
+			expectedError: true,
+		},
+		{
+			name: "duplicate action names",
+			data: `
+version: "0.1"
+name: hello_world_workflow
+global_timeout: 600
+tasks:
+  - name: "hello world"
+    worker: "{{.device_1}}"
+    actions:
+    - name: "hello_world"
+      image: hello-world
+    - name: "hello_world"
+      image: hello-world
+`,
+			expectedError: true,
+		},
+	}
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			err := ValidateTemplateData(test.data)
+			if test.expectedError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
 type workflowModifier func(*Workflow)
 
 func toWorkflow(m ...workflowModifier) *Workflow {
@@ -238,6 +384,34 @@ func withActionInvalidImage() workflowModifier {
 	return func(wf *Workflow) { wf.Tasks[0].Actions[0].Image = "action-image-with-$#@-" }
 }
 
+func withActionNegativeRetries() workflowModifier {
+	return func(wf *Workflow) { wf.Tasks[0].Actions[0].Retries = -1 }
+}
+
+func withActionRetries(retries int) workflowModifier {
+	return func(wf *Workflow) { wf.Tasks[0].Actions[0].Retries = retries }
+}
+
+func withActionRetryPolicy() workflowModifier {
+	return func(wf *Workflow) {
+		wf.Tasks[0].Actions[0].Retries = 3
+		wf.Tasks[0].Actions[0].Backoff = 5
+		wf.Tasks[0].Actions[0].RetryOn = []string{"1", "ImagePull"}
+	}
+}
+
+func withActionGroup(indices ...int) workflowModifier {
+	return func(wf *Workflow) {
+		for _, i := range indices {
+			wf.Tasks[0].Actions[i].Group = "group"
+		}
+	}
+}
+
+func withActionCondition(when string) workflowModifier {
+	return func(wf *Workflow) { wf.Tasks[0].Actions[0].When = when }
+}
+
 // invalid template modifiers
 
 func withTemplateInvalidName() workflowModifier {
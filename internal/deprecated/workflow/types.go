@@ -29,4 +29,9 @@ type Action struct {
 	Volumes     []string          `yaml:"volumes,omitempty"`
 	Environment map[string]string `yaml:"environment,omitempty"`
 	Pid         string            `yaml:"pid,omitempty"`
+	Retries     int               `yaml:"retries,omitempty"`
+	Backoff     int64             `yaml:"backoff,omitempty"`
+	RetryOn     []string          `yaml:"retry-on,omitempty"`
+	Group       string            `yaml:"group,omitempty"`
+	When        string            `yaml:"when,omitempty"`
 }
@@ -0,0 +1,245 @@
+package grpcserver
+
+import (
+	"bufio"
+	"context"
+	"crypto/subtle"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// errNoCredentials is returned by an Authenticator when a request carries no credentials it
+// understands so the next Authenticator may be tried.
+var errNoCredentials = errors.New("no credentials")
+
+// Authenticator identifies the caller of a gRPC request.
+type Authenticator interface {
+	// Authenticate returns the identity of the caller. The identity is the worker or agent ID the
+	// caller is permitted to act as.
+	Authenticate(ctx context.Context) (string, error)
+}
+
+// ClientCertAuthenticator identifies callers by the common name of a verified client certificate.
+// It requires the server to verify client certificates, see NewTLSConfig.
+type ClientCertAuthenticator struct{}
+
+// Authenticate satisfies Authenticator.
+func (ClientCertAuthenticator) Authenticate(ctx context.Context) (string, error) {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return "", errNoCredentials
+	}
+	info, ok := p.AuthInfo.(credentials.TLSInfo)
+	if !ok || len(info.State.VerifiedChains) == 0 || len(info.State.VerifiedChains[0]) == 0 {
+		return "", errNoCredentials
+	}
+
+	cn := info.State.VerifiedChains[0][0].Subject.CommonName
+	if cn == "" {
+		return "", errors.New("client certificate has no common name")
+	}
+	return cn, nil
+}
+
+// TokenAuthenticator identifies callers by a bearer token provided in the authorization metadata.
+type TokenAuthenticator struct {
+	// Tokens maps bearer tokens to the identity they authenticate.
+	Tokens map[string]string
+}
+
+// Authenticate satisfies Authenticator.
+func (a TokenAuthenticator) Authenticate(ctx context.Context) (string, error) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return "", errNoCredentials
+	}
+	values := md.Get("authorization")
+	if len(values) == 0 {
+		return "", errNoCredentials
+	}
+
+	token, ok := strings.CutPrefix(values[0], "Bearer ")
+	if !ok {
+		return "", errors.New("authorization is not a bearer token")
+	}
+	for candidate, identity := range a.Tokens {
+		if subtle.ConstantTimeCompare([]byte(candidate), []byte(token)) == 1 {
+			return identity, nil
+		}
+	}
+	return "", errors.New("unknown bearer token")
+}
+
+// LoadTokenFile reads bearer tokens from path. Each non-empty line has the form "token,identity";
+// lines beginning with # are ignored.
+func LoadTokenFile(path string) (map[string]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	tokens := map[string]string{}
+	scanner := bufio.NewScanner(f)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		token, identity, ok := strings.Cut(text, ",")
+		token, identity = strings.TrimSpace(token), strings.TrimSpace(identity)
+		if !ok || token == "" || identity == "" {
+			return nil, fmt.Errorf("%v:%d: expected token,identity", path, line)
+		}
+		tokens[token] = identity
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return tokens, nil
+}
+
+// NewTLSConfig creates a server TLS configuration from a certificate and key. When clientCAFile
+// is provided clients must present a certificate signed by one of its CAs.
+func NewTLSConfig(certFile, keyFile, clientCAFile string) (*tls.Config, error) {
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return nil, fmt.Errorf("load server certificate: %w", err)
+	}
+
+	cfg := &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		MinVersion:   tls.VersionTLS12,
+	}
+
+	if clientCAFile != "" {
+		pem, err := os.ReadFile(clientCAFile)
+		if err != nil {
+			return nil, fmt.Errorf("read client CA: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in %v", clientCAFile)
+		}
+		cfg.ClientCAs = pool
+		cfg.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+
+	return cfg, nil
+}
+
+type identityKey struct{}
+
+// ContextWithIdentity returns a copy of ctx carrying the authenticated identity of the caller.
+func ContextWithIdentity(ctx context.Context, identity string) context.Context {
+	return context.WithValue(ctx, identityKey{}, identity)
+}
+
+// IdentityFromContext retrieves the authenticated identity of the caller. It returns false when
+// authentication is disabled.
+func IdentityFromContext(ctx context.Context) (string, bool) {
+	identity, ok := ctx.Value(identityKey{}).(string)
+	return identity, ok
+}
+
+// WithAuthentication creates server options that authenticate every request using the first
+// Authenticator able to identify the caller. Requests that identify a worker or agent must
+// identify the authenticated caller.
+func WithAuthentication(authenticators ...Authenticator) []grpc.ServerOption {
+	authenticate := func(ctx context.Context) (context.Context, error) {
+		return Authenticate(ctx, authenticators...)
+	}
+
+	unary := func(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		ctx, err := authenticate(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if err := AuthorizeRequest(ctx, req); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+
+	stream := func(srv any, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := authenticate(ss.Context())
+		if err != nil {
+			return err
+		}
+		return handler(srv, &authenticatedStream{ServerStream: ss, ctx: ctx})
+	}
+
+	return []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(unary),
+		grpc.ChainStreamInterceptor(stream),
+	}
+}
+
+// Authenticate identifies the caller using the first Authenticator able to identify it and
+// returns a copy of ctx carrying the caller's identity. It's used by servers that expose gRPC
+// services over other protocols; gRPC servers should use WithAuthentication.
+func Authenticate(ctx context.Context, authenticators ...Authenticator) (context.Context, error) {
+	for _, a := range authenticators {
+		identity, err := a.Authenticate(ctx)
+		if errors.Is(err, errNoCredentials) {
+			continue
+		}
+		if err != nil {
+			return nil, status.Errorf(codes.Unauthenticated, "%v", err)
+		}
+		return ContextWithIdentity(ctx, identity), nil
+	}
+	return nil, status.Errorf(codes.Unauthenticated, "missing credentials")
+}
+
+// AuthorizeRequest ensures requests acting on behalf of a worker or agent are made by that
+// worker or agent. Requests are authorized when ctx carries no identity. Requests that identify
+// only a workflow must be authorized by the service against the workflow's assigned worker or
+// agent.
+func AuthorizeRequest(ctx context.Context, req any) error {
+	identity, ok := IdentityFromContext(ctx)
+	if !ok {
+		return nil
+	}
+
+	var id string
+	switch r := req.(type) {
+	case interface{ GetWorkerId() string }:
+		id = r.GetWorkerId()
+	case interface{ GetAgentId() string }:
+		id = r.GetAgentId()
+	default:
+		return nil
+	}
+
+	if id != identity {
+		return status.Errorf(codes.PermissionDenied, "%q may not act as %q", identity, id)
+	}
+	return nil
+}
+
+// authenticatedStream carries the authenticated identity and authorizes each received message.
+type authenticatedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *authenticatedStream) Context() context.Context { return s.ctx }
+
+func (s *authenticatedStream) RecvMsg(m any) error {
+	if err := s.ServerStream.RecvMsg(m); err != nil {
+		return err
+	}
+	return AuthorizeRequest(s.ctx, m)
+}
@@ -0,0 +1,231 @@
+package grpcserver
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/tinkerbell/tink/internal/client"
+	"github.com/tinkerbell/tink/internal/proto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/status"
+)
+
+// reportServer records the identity of callers to ReportActionStatus.
+type reportServer struct {
+	proto.UnimplementedWorkflowServiceServer
+	identity string
+}
+
+func (s *reportServer) Register(server *grpc.Server) {
+	proto.RegisterWorkflowServiceServer(server, s)
+}
+
+func (s *reportServer) ReportActionStatus(ctx context.Context, _ *proto.WorkflowActionStatus) (*proto.Empty, error) {
+	s.identity, _ = IdentityFromContext(ctx)
+	return &proto.Empty{}, nil
+}
+
+// writePEM writes a PEM block of type typ containing der to dir/name and returns the path.
+func writePEM(t *testing.T, dir, name, typ string, der []byte) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: typ, Bytes: der}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+type certFiles struct {
+	cert, key string
+}
+
+// newCert creates a certificate for commonName signed by parent, or self-signed when parent is
+// nil, and writes it to dir.
+func newCert(t *testing.T, dir, commonName string, parent *x509.Certificate, parentKey *ecdsa.PrivateKey) (*x509.Certificate, *ecdsa.PrivateKey, certFiles) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tpl := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+	}
+	if parent == nil {
+		tpl.IsCA = true
+		tpl.BasicConstraintsValid = true
+		parent, parentKey = tpl, key
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, tpl, parent, &key.PublicKey, parentKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return cert, key, certFiles{
+		cert: writePEM(t, dir, commonName+".crt", "CERTIFICATE", der),
+		key:  writePEM(t, dir, commonName+".key", "EC PRIVATE KEY", keyDER),
+	}
+}
+
+func TestAuthentication(t *testing.T) {
+	dir := t.TempDir()
+	ca, caKey, caFiles := newCert(t, dir, "ca", nil, nil)
+	_, _, serverFiles := newCert(t, dir, "server", ca, caKey)
+	_, _, workerFiles := newCert(t, dir, "worker-1", ca, caKey)
+
+	tokenFile := filepath.Join(dir, "tokens")
+	if err := os.WriteFile(tokenFile, []byte("# worker tokens\nsecret,worker-2\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	tokens, err := LoadTokenFile(tokenFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tlsConfig, err := NewTLSConfig(serverFiles.cert, serverFiles.key, caFiles.cert)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Allow token authenticated clients that don't present a certificate.
+	tlsConfig.ClientAuth = tls.VerifyClientCertIfGiven
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	srv := &reportServer{}
+	opts := append(
+		[]grpc.ServerOption{grpc.Creds(credentials.NewTLS(tlsConfig))},
+		WithAuthentication(ClientCertAuthenticator{}, TokenAuthenticator{Tokens: tokens})...,
+	)
+	addr, err := SetupGRPC(ctx, srv, "127.0.0.1:0", make(chan error, 1), opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		name         string
+		opts         []client.Option
+		workerID     string
+		wantCode     codes.Code
+		wantIdentity string
+	}{
+		{
+			name:         "ClientCertificate",
+			opts:         []client.Option{client.WithClientCertificate(workerFiles.cert, workerFiles.key)},
+			workerID:     "worker-1",
+			wantIdentity: "worker-1",
+		},
+		{
+			name:     "ClientCertificateOtherWorker",
+			opts:     []client.Option{client.WithClientCertificate(workerFiles.cert, workerFiles.key)},
+			workerID: "worker-2",
+			wantCode: codes.PermissionDenied,
+		},
+		{
+			name:         "Token",
+			opts:         []client.Option{client.WithBearerToken("secret")},
+			workerID:     "worker-2",
+			wantIdentity: "worker-2",
+		},
+		{
+			name:     "UnknownToken",
+			opts:     []client.Option{client.WithBearerToken("unknown")},
+			workerID: "worker-2",
+			wantCode: codes.Unauthenticated,
+		},
+		{
+			name:     "NoCredentials",
+			workerID: "worker-1",
+			wantCode: codes.Unauthenticated,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			srv.identity = ""
+			conn, err := client.NewClientConn(addr, true, false, append(tc.opts, client.WithRootCA(caFiles.cert))...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer conn.Close()
+
+			_, err = proto.NewWorkflowServiceClient(conn).ReportActionStatus(ctx, &proto.WorkflowActionStatus{
+				WorkflowId: "default/workflow",
+				WorkerId:   tc.workerID,
+			})
+			if code := status.Code(err); code != tc.wantCode {
+				t.Fatalf("expected code %v, got %v: %v", tc.wantCode, code, err)
+			}
+			if diff := cmp.Diff(tc.wantIdentity, srv.identity); diff != "" {
+				t.Fatalf("unexpected identity (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestLoadTokenFile(t *testing.T) {
+	cases := []struct {
+		name    string
+		content string
+		want    map[string]string
+		wantErr bool
+	}{
+		{
+			name:    "Valid",
+			content: "# comment\n\ntoken1,worker-1\n token2 , worker-2 \n",
+			want:    map[string]string{"token1": "worker-1", "token2": "worker-2"},
+		},
+		{
+			name:    "MissingIdentity",
+			content: "token1\n",
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "tokens")
+			if err := os.WriteFile(path, []byte(tc.content), 0o600); err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := LoadTokenFile(path)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("expected error: %v, got: %v", tc.wantErr, err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Fatalf("unexpected tokens (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
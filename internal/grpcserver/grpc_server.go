@@ -17,12 +17,14 @@ type Registrar interface {
 }
 
 // SetupGRPC opens a listener and serves a given Registrar's APIs on a gRPC server and returns the listener's address or an error.
-func SetupGRPC(ctx context.Context, r Registrar, listenAddr string, errCh chan<- error) (string, error) {
+// Additional opts, such as transport credentials or authentication, are applied to the server.
+func SetupGRPC(ctx context.Context, r Registrar, listenAddr string, errCh chan<- error, opts ...grpc.ServerOption) (string, error) {
 	params := []grpc.ServerOption{
 		grpc.StatsHandler(otelgrpc.NewServerHandler()),
 		grpc.UnaryInterceptor(grpcprometheus.UnaryServerInterceptor),
 		grpc.StreamInterceptor(grpcprometheus.StreamServerInterceptor),
 	}
+	params = append(params, opts...)
 
 	// register servers
 	s := grpc.NewServer(params...)
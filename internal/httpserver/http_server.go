@@ -2,6 +2,7 @@ package httpserver
 
 import (
 	"context"
+	"crypto/tls"
 	"encoding/json"
 	"net/http"
 	"runtime"
@@ -27,9 +28,31 @@ func SetupHTTP(ctx context.Context, logger logr.Logger, authority string, errCh
 	srv := &http.Server{ //nolint:gosec // TODO: fix Potential Slowloris Attack because ReadHeaderTimeout is not configured
 		Addr: authority,
 	}
+	serve(ctx, logger, srv, errCh)
+}
+
+// SetupHandler serves handler on a dedicated listener at authority. When tlsConfig is non-nil
+// the listener serves HTTPS using tlsConfig, which must contain a server certificate.
+func SetupHandler(ctx context.Context, logger logr.Logger, authority string, handler http.Handler, tlsConfig *tls.Config, errCh chan<- error) {
+	srv := &http.Server{ //nolint:gosec // Handlers stream responses so timeouts are left to them.
+		Addr:      authority,
+		Handler:   handler,
+		TLSConfig: tlsConfig,
+	}
+	serve(ctx, logger, srv, errCh)
+}
+
+// serve runs srv in the background until ctx is done. The result of serving is sent to errCh.
+func serve(ctx context.Context, logger logr.Logger, srv *http.Server, errCh chan<- error) {
 	go func() {
-		logger.Info("serving http")
-		err := srv.ListenAndServe()
+		logger.Info("serving http", "address", srv.Addr, "tls", srv.TLSConfig != nil)
+		var err error
+		if srv.TLSConfig != nil {
+			// The certificate is provided by the TLS config.
+			err = srv.ListenAndServeTLS("", "")
+		} else {
+			err = srv.ListenAndServe()
+		}
 		if errors.Is(err, http.ErrServerClosed) {
 			err = nil
 		}
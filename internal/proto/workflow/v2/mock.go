@@ -23,6 +23,9 @@ var _ WorkflowServiceClient = &WorkflowServiceClientMock{}
 //			GetWorkflowsFunc: func(ctx context.Context, in *GetWorkflowsRequest, opts ...grpc.CallOption) (WorkflowService_GetWorkflowsClient, error) {
 //				panic("mock out the GetWorkflows method")
 //			},
+//			HeartbeatFunc: func(ctx context.Context, in *HeartbeatRequest, opts ...grpc.CallOption) (*HeartbeatResponse, error) {
+//				panic("mock out the Heartbeat method")
+//			},
 //			PublishEventFunc: func(ctx context.Context, in *PublishEventRequest, opts ...grpc.CallOption) (*PublishEventResponse, error) {
 //				panic("mock out the PublishEvent method")
 //			},
@@ -36,6 +39,9 @@ type WorkflowServiceClientMock struct {
 	// GetWorkflowsFunc mocks the GetWorkflows method.
 	GetWorkflowsFunc func(ctx context.Context, in *GetWorkflowsRequest, opts ...grpc.CallOption) (WorkflowService_GetWorkflowsClient, error)
 
+	// HeartbeatFunc mocks the Heartbeat method.
+	HeartbeatFunc func(ctx context.Context, in *HeartbeatRequest, opts ...grpc.CallOption) (*HeartbeatResponse, error)
+
 	// PublishEventFunc mocks the PublishEvent method.
 	PublishEventFunc func(ctx context.Context, in *PublishEventRequest, opts ...grpc.CallOption) (*PublishEventResponse, error)
 
@@ -50,6 +56,15 @@ type WorkflowServiceClientMock struct {
 			// Opts is the opts argument value.
 			Opts []grpc.CallOption
 		}
+		// Heartbeat holds details about calls to the Heartbeat method.
+		Heartbeat []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// In is the in argument value.
+			In *HeartbeatRequest
+			// Opts is the opts argument value.
+			Opts []grpc.CallOption
+		}
 		// PublishEvent holds details about calls to the PublishEvent method.
 		PublishEvent []struct {
 			// Ctx is the ctx argument value.
@@ -61,6 +76,7 @@ type WorkflowServiceClientMock struct {
 		}
 	}
 	lockGetWorkflows sync.RWMutex
+	lockHeartbeat    sync.RWMutex
 	lockPublishEvent sync.RWMutex
 }
 
@@ -104,6 +120,46 @@ func (mock *WorkflowServiceClientMock) GetWorkflowsCalls() []struct {
 	return calls
 }
 
+// Heartbeat calls HeartbeatFunc.
+func (mock *WorkflowServiceClientMock) Heartbeat(ctx context.Context, in *HeartbeatRequest, opts ...grpc.CallOption) (*HeartbeatResponse, error) {
+	if mock.HeartbeatFunc == nil {
+		panic("WorkflowServiceClientMock.HeartbeatFunc: method is nil but WorkflowServiceClient.Heartbeat was just called")
+	}
+	callInfo := struct {
+		Ctx  context.Context
+		In   *HeartbeatRequest
+		Opts []grpc.CallOption
+	}{
+		Ctx:  ctx,
+		In:   in,
+		Opts: opts,
+	}
+	mock.lockHeartbeat.Lock()
+	mock.calls.Heartbeat = append(mock.calls.Heartbeat, callInfo)
+	mock.lockHeartbeat.Unlock()
+	return mock.HeartbeatFunc(ctx, in, opts...)
+}
+
+// HeartbeatCalls gets all the calls that were made to Heartbeat.
+// Check the length with:
+//
+//	len(mockedWorkflowServiceClient.HeartbeatCalls())
+func (mock *WorkflowServiceClientMock) HeartbeatCalls() []struct {
+	Ctx  context.Context
+	In   *HeartbeatRequest
+	Opts []grpc.CallOption
+} {
+	var calls []struct {
+		Ctx  context.Context
+		In   *HeartbeatRequest
+		Opts []grpc.CallOption
+	}
+	mock.lockHeartbeat.RLock()
+	calls = mock.calls.Heartbeat
+	mock.lockHeartbeat.RUnlock()
+	return calls
+}
+
 // PublishEvent calls PublishEventFunc.
 func (mock *WorkflowServiceClientMock) PublishEvent(ctx context.Context, in *PublishEventRequest, opts ...grpc.CallOption) (*PublishEventResponse, error) {
 	if mock.PublishEventFunc == nil {
@@ -28,6 +28,8 @@ type WorkflowServiceClient interface {
 	GetWorkflows(ctx context.Context, in *GetWorkflowsRequest, opts ...grpc.CallOption) (WorkflowService_GetWorkflowsClient, error)
 	// PublishEvent publishes a workflow event.
 	PublishEvent(ctx context.Context, in *PublishEventRequest, opts ...grpc.CallOption) (*PublishEventResponse, error)
+	// Heartbeat informs the server the agent is alive. Agents should call it periodically.
+	Heartbeat(ctx context.Context, in *HeartbeatRequest, opts ...grpc.CallOption) (*HeartbeatResponse, error)
 }
 
 type workflowServiceClient struct {
@@ -79,6 +81,15 @@ func (c *workflowServiceClient) PublishEvent(ctx context.Context, in *PublishEve
 	return out, nil
 }
 
+func (c *workflowServiceClient) Heartbeat(ctx context.Context, in *HeartbeatRequest, opts ...grpc.CallOption) (*HeartbeatResponse, error) {
+	out := new(HeartbeatResponse)
+	err := c.cc.Invoke(ctx, "/internal.proto.workflow.v2.WorkflowService/Heartbeat", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // WorkflowServiceServer is the server API for WorkflowService service.
 // All implementations should embed UnimplementedWorkflowServiceServer
 // for forward compatibility
@@ -88,6 +99,8 @@ type WorkflowServiceServer interface {
 	GetWorkflows(*GetWorkflowsRequest, WorkflowService_GetWorkflowsServer) error
 	// PublishEvent publishes a workflow event.
 	PublishEvent(context.Context, *PublishEventRequest) (*PublishEventResponse, error)
+	// Heartbeat informs the server the agent is alive. Agents should call it periodically.
+	Heartbeat(context.Context, *HeartbeatRequest) (*HeartbeatResponse, error)
 }
 
 // UnimplementedWorkflowServiceServer should be embedded to have forward compatible implementations.
@@ -101,6 +114,10 @@ func (UnimplementedWorkflowServiceServer) PublishEvent(context.Context, *Publish
 	return nil, status.Errorf(codes.Unimplemented, "method PublishEvent not implemented")
 }
 
+func (UnimplementedWorkflowServiceServer) Heartbeat(context.Context, *HeartbeatRequest) (*HeartbeatResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Heartbeat not implemented")
+}
+
 // UnsafeWorkflowServiceServer may be embedded to opt out of forward compatibility for this service.
 // Use of this interface is not recommended, as added methods to WorkflowServiceServer will
 // result in compilation errors.
@@ -151,6 +168,24 @@ func _WorkflowService_PublishEvent_Handler(srv interface{}, ctx context.Context,
 	return interceptor(ctx, in, info, handler)
 }
 
+func _WorkflowService_Heartbeat_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(HeartbeatRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WorkflowServiceServer).Heartbeat(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/internal.proto.workflow.v2.WorkflowService/Heartbeat",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WorkflowServiceServer).Heartbeat(ctx, req.(*HeartbeatRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // WorkflowService_ServiceDesc is the grpc.ServiceDesc for WorkflowService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -162,6 +197,10 @@ var WorkflowService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "PublishEvent",
 			Handler:    _WorkflowService_PublishEvent_Handler,
 		},
+		{
+			MethodName: "Heartbeat",
+			Handler:    _WorkflowService_Heartbeat_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -26,6 +26,11 @@ type WorkflowServiceClient interface {
 	GetWorkflowContexts(ctx context.Context, in *WorkflowContextRequest, opts ...grpc.CallOption) (WorkflowService_GetWorkflowContextsClient, error)
 	GetWorkflowActions(ctx context.Context, in *WorkflowActionsRequest, opts ...grpc.CallOption) (*WorkflowActionList, error)
 	ReportActionStatus(ctx context.Context, in *WorkflowActionStatus, opts ...grpc.CallOption) (*Empty, error)
+	// UploadActionLogs receives the output of action containers so it outlives the machine
+	// the action ran on.
+	UploadActionLogs(ctx context.Context, opts ...grpc.CallOption) (WorkflowService_UploadActionLogsClient, error)
+	// WorkerHeartbeat informs the server the worker is alive. Workers should call it periodically.
+	WorkerHeartbeat(ctx context.Context, in *WorkerHeartbeatRequest, opts ...grpc.CallOption) (*Empty, error)
 }
 
 type workflowServiceClient struct {
@@ -86,6 +91,49 @@ func (c *workflowServiceClient) ReportActionStatus(ctx context.Context, in *Work
 	return out, nil
 }
 
+func (c *workflowServiceClient) UploadActionLogs(ctx context.Context, opts ...grpc.CallOption) (WorkflowService_UploadActionLogsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &WorkflowService_ServiceDesc.Streams[1], "/proto.WorkflowService/UploadActionLogs", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &workflowServiceUploadActionLogsClient{stream}
+	return x, nil
+}
+
+type WorkflowService_UploadActionLogsClient interface {
+	Send(*ActionLogChunk) error
+	CloseAndRecv() (*Empty, error)
+	grpc.ClientStream
+}
+
+type workflowServiceUploadActionLogsClient struct {
+	grpc.ClientStream
+}
+
+func (x *workflowServiceUploadActionLogsClient) Send(m *ActionLogChunk) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *workflowServiceUploadActionLogsClient) CloseAndRecv() (*Empty, error) {
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	m := new(Empty)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *workflowServiceClient) WorkerHeartbeat(ctx context.Context, in *WorkerHeartbeatRequest, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, "/proto.WorkflowService/WorkerHeartbeat", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // WorkflowServiceServer is the server API for WorkflowService service.
 // All implementations should embed UnimplementedWorkflowServiceServer
 // for forward compatibility
@@ -93,6 +141,11 @@ type WorkflowServiceServer interface {
 	GetWorkflowContexts(*WorkflowContextRequest, WorkflowService_GetWorkflowContextsServer) error
 	GetWorkflowActions(context.Context, *WorkflowActionsRequest) (*WorkflowActionList, error)
 	ReportActionStatus(context.Context, *WorkflowActionStatus) (*Empty, error)
+	// UploadActionLogs receives the output of action containers so it outlives the machine
+	// the action ran on.
+	UploadActionLogs(WorkflowService_UploadActionLogsServer) error
+	// WorkerHeartbeat informs the server the worker is alive. Workers should call it periodically.
+	WorkerHeartbeat(context.Context, *WorkerHeartbeatRequest) (*Empty, error)
 }
 
 // UnimplementedWorkflowServiceServer should be embedded to have forward compatible implementations.
@@ -110,6 +163,14 @@ func (UnimplementedWorkflowServiceServer) ReportActionStatus(context.Context, *W
 	return nil, status.Errorf(codes.Unimplemented, "method ReportActionStatus not implemented")
 }
 
+func (UnimplementedWorkflowServiceServer) UploadActionLogs(WorkflowService_UploadActionLogsServer) error {
+	return status.Errorf(codes.Unimplemented, "method UploadActionLogs not implemented")
+}
+
+func (UnimplementedWorkflowServiceServer) WorkerHeartbeat(context.Context, *WorkerHeartbeatRequest) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method WorkerHeartbeat not implemented")
+}
+
 // UnsafeWorkflowServiceServer may be embedded to opt out of forward compatibility for this service.
 // Use of this interface is not recommended, as added methods to WorkflowServiceServer will
 // result in compilation errors.
@@ -178,6 +239,50 @@ func _WorkflowService_ReportActionStatus_Handler(srv interface{}, ctx context.Co
 	return interceptor(ctx, in, info, handler)
 }
 
+func _WorkflowService_UploadActionLogs_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(WorkflowServiceServer).UploadActionLogs(&workflowServiceUploadActionLogsServer{stream})
+}
+
+type WorkflowService_UploadActionLogsServer interface {
+	SendAndClose(*Empty) error
+	Recv() (*ActionLogChunk, error)
+	grpc.ServerStream
+}
+
+type workflowServiceUploadActionLogsServer struct {
+	grpc.ServerStream
+}
+
+func (x *workflowServiceUploadActionLogsServer) SendAndClose(m *Empty) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *workflowServiceUploadActionLogsServer) Recv() (*ActionLogChunk, error) {
+	m := new(ActionLogChunk)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _WorkflowService_WorkerHeartbeat_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(WorkerHeartbeatRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WorkflowServiceServer).WorkerHeartbeat(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.WorkflowService/WorkerHeartbeat",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WorkflowServiceServer).WorkerHeartbeat(ctx, req.(*WorkerHeartbeatRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // WorkflowService_ServiceDesc is the grpc.ServiceDesc for WorkflowService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -193,6 +298,10 @@ var WorkflowService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "ReportActionStatus",
 			Handler:    _WorkflowService_ReportActionStatus_Handler,
 		},
+		{
+			MethodName: "WorkerHeartbeat",
+			Handler:    _WorkflowService_WorkerHeartbeat_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -200,6 +309,11 @@ var WorkflowService_ServiceDesc = grpc.ServiceDesc{
 			Handler:       _WorkflowService_GetWorkflowContexts_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "UploadActionLogs",
+			Handler:       _WorkflowService_UploadActionLogs_Handler,
+			ClientStreams: true,
+		},
 	},
 	Metadata: "internal/proto/workflow.proto",
 }
@@ -0,0 +1,174 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/tinkerbell/tink/api/v1alpha1"
+	"github.com/tinkerbell/tink/internal/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// DefaultMaxActionLogSize is the default maximum size of a single action's log.
+const DefaultMaxActionLogSize = 64 << 20
+
+// ErrActionLogTooLarge is returned when appending to an action log would exceed its maximum size.
+var ErrActionLogTooLarge = errors.New("action log exceeds maximum size")
+
+// ActionLogStore persists action logs uploaded by workers.
+type ActionLogStore interface {
+	// Append appends the data in chunk to the log of the action it identifies.
+	Append(chunk *proto.ActionLogChunk) error
+}
+
+// FileActionLogStore is an ActionLogStore that writes logs to files under Dir. Each action's log
+// is written to <Dir>/<namespace>/<workflow>/<task>/<action>.log.
+type FileActionLogStore struct {
+	Dir string
+
+	// MaxSize is the maximum size of an action's log in bytes. Appends that would exceed it fail
+	// with ErrActionLogTooLarge. Zero means no limit.
+	MaxSize int64
+}
+
+// NewFileActionLogStore creates a FileActionLogStore that writes to dir limiting each action's
+// log to DefaultMaxActionLogSize.
+func NewFileActionLogStore(dir string) *FileActionLogStore {
+	return &FileActionLogStore{Dir: dir, MaxSize: DefaultMaxActionLogSize}
+}
+
+// Path returns the file the log for the action identified by workflowID, taskName and actionName
+// is written to.
+func (s *FileActionLogStore) Path(workflowID, taskName, actionName string) (string, error) {
+	namespace, name, _ := strings.Cut(workflowID, "/")
+	elems := []string{namespace, name, taskName, actionName}
+	for _, e := range elems {
+		if e == "" || e == "." || e == ".." || strings.ContainsAny(e, `/\`) {
+			return "", fmt.Errorf("invalid log path element: %q", e)
+		}
+	}
+	elems[len(elems)-1] += ".log"
+	return filepath.Join(append([]string{s.Dir}, elems...)...), nil
+}
+
+// Append satisfies ActionLogStore.
+func (s *FileActionLogStore) Append(chunk *proto.ActionLogChunk) error {
+	path, err := s.Path(chunk.GetWorkflowId(), chunk.GetTaskName(), chunk.GetActionName())
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o640)
+	if err != nil {
+		return err
+	}
+	if s.MaxSize > 0 {
+		info, err := f.Stat()
+		if err != nil {
+			_ = f.Close()
+			return err
+		}
+		if info.Size()+int64(len(chunk.GetData())) > s.MaxSize {
+			_ = f.Close()
+			return ErrActionLogTooLarge
+		}
+	}
+	if _, err := f.Write(chunk.GetData()); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// workflowGetter retrieves the workflow identified by workflowID. It returns a gRPC status
+// error, codes.NotFound if the workflow doesn't exist.
+type workflowGetter func(ctx context.Context, workflowID string) (*v1alpha1.Workflow, error)
+
+// receiveActionLogs appends every chunk received on stream to store. Chunks must identify an
+// action in a task of an existing workflow, retrieved with getWorkflow, that's assigned to the
+// chunk's worker.
+func receiveActionLogs(store ActionLogStore, stream proto.WorkflowService_UploadActionLogsServer, getWorkflow workflowGetter) error {
+	if store == nil {
+		return status.Errorf(codes.Unimplemented, "action log storage is not configured")
+	}
+
+	// authorized tracks actions chunks were accepted for so workflows are retrieved once per
+	// action rather than per chunk.
+	authorized := map[actionLogKey]bool{}
+
+	for {
+		chunk, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return stream.SendAndClose(&proto.Empty{})
+		}
+		if err != nil {
+			return err
+		}
+
+		if chunk.GetWorkflowId() == "" {
+			return status.Errorf(codes.InvalidArgument, errInvalidWorkflowID)
+		}
+		if chunk.GetTaskName() == "" {
+			return status.Errorf(codes.InvalidArgument, errInvalidTaskName)
+		}
+		if chunk.GetActionName() == "" {
+			return status.Errorf(codes.InvalidArgument, errInvalidActionName)
+		}
+
+		key := actionLogKey{
+			workflowID: chunk.GetWorkflowId(),
+			taskName:   chunk.GetTaskName(),
+			actionName: chunk.GetActionName(),
+			workerID:   chunk.GetWorkerId(),
+		}
+		if !authorized[key] {
+			wf, err := getWorkflow(stream.Context(), chunk.GetWorkflowId())
+			if err != nil {
+				return err
+			}
+			if err := authorizeActionLog(wf, chunk); err != nil {
+				return err
+			}
+			authorized[key] = true
+		}
+
+		if err := store.Append(chunk); err != nil {
+			if errors.Is(err, ErrActionLogTooLarge) {
+				return status.Errorf(codes.ResourceExhausted, "store action log: %v", err)
+			}
+			return status.Errorf(codes.Internal, "store action log: %v", err)
+		}
+	}
+}
+
+// actionLogKey identifies the action, and the worker uploading it, a log chunk belongs to.
+type actionLogKey struct {
+	workflowID, taskName, actionName, workerID string
+}
+
+// authorizeActionLog ensures chunk identifies an action in wf that belongs to a task assigned to
+// the chunk's worker.
+func authorizeActionLog(wf *v1alpha1.Workflow, chunk *proto.ActionLogChunk) error {
+	for _, task := range wf.Status.Tasks {
+		if task.Name != chunk.GetTaskName() {
+			continue
+		}
+		if task.WorkerAddr != chunk.GetWorkerId() {
+			return status.Errorf(codes.PermissionDenied, errWorkflowNotOwned)
+		}
+		if findAction(wf, task.Name, chunk.GetActionName()) == nil {
+			return status.Errorf(codes.InvalidArgument, errInvalidActionName)
+		}
+		return nil
+	}
+	return status.Errorf(codes.InvalidArgument, errInvalidTaskName)
+}
@@ -0,0 +1,166 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/tinkerbell/tink/api/v1alpha1"
+	"github.com/tinkerbell/tink/internal/proto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestFileActionLogStore(t *testing.T) {
+	dir := t.TempDir()
+	store := NewFileActionLogStore(dir)
+
+	for _, data := range []string{"wiping /dev/sda\n", "done\n"} {
+		err := store.Append(&proto.ActionLogChunk{
+			WorkflowId: "default/machine1",
+			TaskName:   "os-installation",
+			ActionName: "disk-wipe",
+			Data:       []byte(data),
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	got, err := os.ReadFile(filepath.Join(dir, "default", "machine1", "os-installation", "disk-wipe.log"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != "wiping /dev/sda\ndone\n" {
+		t.Fatalf("unexpected log content: %q", got)
+	}
+}
+
+func TestFileActionLogStore_InvalidPath(t *testing.T) {
+	store := NewFileActionLogStore(t.TempDir())
+
+	cases := []*proto.ActionLogChunk{
+		{WorkflowId: "default/machine1", TaskName: "..", ActionName: "disk-wipe"},
+		{WorkflowId: "default/machine1", TaskName: "os-installation", ActionName: "../../etc/passwd"},
+		{WorkflowId: "machine1", TaskName: "os-installation", ActionName: "disk-wipe"},
+	}
+	for _, chunk := range cases {
+		if err := store.Append(chunk); err == nil {
+			t.Fatalf("expected an error for %v", chunk)
+		}
+	}
+}
+
+func TestUploadActionLogs_NotConfigured(t *testing.T) {
+	s := &KubernetesBackedServer{}
+	if code := status.Code(s.UploadActionLogs(nil)); code != codes.Unimplemented {
+		t.Fatalf("expected code %v, got %v", codes.Unimplemented, code)
+	}
+}
+
+func TestFileActionLogStore_MaxSize(t *testing.T) {
+	store := &FileActionLogStore{Dir: t.TempDir(), MaxSize: 8}
+
+	chunk := &proto.ActionLogChunk{
+		WorkflowId: "default/machine1",
+		TaskName:   "os-installation",
+		ActionName: "disk-wipe",
+		Data:       []byte("wiping\n"),
+	}
+	if err := store.Append(chunk); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Append(chunk); !errors.Is(err, ErrActionLogTooLarge) {
+		t.Fatalf("expected %v, got %v", ErrActionLogTooLarge, err)
+	}
+}
+
+func TestReceiveActionLogs(t *testing.T) {
+	wf := &v1alpha1.Workflow{
+		Status: v1alpha1.WorkflowStatus{
+			Tasks: []v1alpha1.Task{{
+				Name:       "os-installation",
+				WorkerAddr: "worker",
+				Actions:    []v1alpha1.Action{{Name: "disk-wipe"}},
+			}},
+		},
+	}
+	getWorkflow := func(_ context.Context, workflowID string) (*v1alpha1.Workflow, error) {
+		if workflowID != "default/machine1" {
+			return nil, status.Errorf(codes.NotFound, "workflow %v not found", workflowID)
+		}
+		return wf, nil
+	}
+
+	cases := []struct {
+		Name   string
+		Chunk  *proto.ActionLogChunk
+		Expect codes.Code
+	}{
+		{
+			Name:   "Assigned",
+			Chunk:  &proto.ActionLogChunk{WorkflowId: "default/machine1", TaskName: "os-installation", ActionName: "disk-wipe", WorkerId: "worker"},
+			Expect: codes.OK,
+		},
+		{
+			Name:   "WorkflowNotFound",
+			Chunk:  &proto.ActionLogChunk{WorkflowId: "default/machine2", TaskName: "os-installation", ActionName: "disk-wipe", WorkerId: "worker"},
+			Expect: codes.NotFound,
+		},
+		{
+			Name:   "NotAssigned",
+			Chunk:  &proto.ActionLogChunk{WorkflowId: "default/machine1", TaskName: "os-installation", ActionName: "disk-wipe", WorkerId: "other"},
+			Expect: codes.PermissionDenied,
+		},
+		{
+			Name:   "UnknownTask",
+			Chunk:  &proto.ActionLogChunk{WorkflowId: "default/machine1", TaskName: "unknown", ActionName: "disk-wipe", WorkerId: "worker"},
+			Expect: codes.InvalidArgument,
+		},
+		{
+			Name:   "UnknownAction",
+			Chunk:  &proto.ActionLogChunk{WorkflowId: "default/machine1", TaskName: "os-installation", ActionName: "unknown", WorkerId: "worker"},
+			Expect: codes.InvalidArgument,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.Name, func(t *testing.T) {
+			dir := t.TempDir()
+			stream := &uploadActionLogsStream{chunks: []*proto.ActionLogChunk{tc.Chunk}}
+
+			err := receiveActionLogs(NewFileActionLogStore(dir), stream, getWorkflow)
+			if code := status.Code(err); code != tc.Expect {
+				t.Fatalf("expected code %v, got %v: %v", tc.Expect, code, err)
+			}
+
+			_, err = os.Stat(filepath.Join(dir, "default", "machine1", "os-installation", "disk-wipe.log"))
+			if written := err == nil; written != (tc.Expect == codes.OK) {
+				t.Fatalf("unexpected log file state: %v", err)
+			}
+		})
+	}
+}
+
+// uploadActionLogsStream is a proto.WorkflowService_UploadActionLogsServer that receives chunks.
+type uploadActionLogsStream struct {
+	grpc.ServerStream
+	chunks []*proto.ActionLogChunk
+}
+
+func (s *uploadActionLogsStream) Context() context.Context { return context.Background() }
+
+func (s *uploadActionLogsStream) Recv() (*proto.ActionLogChunk, error) {
+	if len(s.chunks) == 0 {
+		return nil, io.EOF
+	}
+	chunk := s.chunks[0]
+	s.chunks = s.chunks[1:]
+	return chunk, nil
+}
+
+func (s *uploadActionLogsStream) SendAndClose(*proto.Empty) error { return nil }
@@ -0,0 +1,401 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/tinkerbell/tink/api/v1alpha1"
+	"github.com/tinkerbell/tink/internal/bolt"
+	"github.com/tinkerbell/tink/internal/deprecated/workflow"
+	"github.com/tinkerbell/tink/internal/proto"
+	workflowproto "github.com/tinkerbell/tink/internal/proto/workflow/v2"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	googleproto "google.golang.org/protobuf/proto"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// NewBoltBackedServer returns a server that implements the Workflow server interface using
+// store for persistence.
+func NewBoltBackedServer(logger logr.Logger, store *bolt.Store) *BoltBackedServer {
+	return &BoltBackedServer{
+		logger:   logger,
+		store:    store,
+		nowFunc:  time.Now,
+		notifier: newWorkflowNotifier(),
+	}
+}
+
+// BoltBackedServer is a server that implements a workflow API on an embedded bbolt store. It
+// serves the same workflow state transitions as KubernetesBackedServer without requiring a
+// Kubernetes control plane.
+type BoltBackedServer struct {
+	logger logr.Logger
+	store  *bolt.Store
+
+	nowFunc func() time.Time
+
+	// notifier notifies watching workers of workflow changes made through the server.
+	notifier *workflowNotifier
+
+	// timeoutCheckInterval is the interval at which RunTimeoutChecks applies timeouts. Defaults to
+	// defaultWorkflowPollInterval.
+	timeoutCheckInterval time.Duration
+
+	// ActionLogs persists action logs uploaded by workers. When nil, uploads are rejected.
+	ActionLogs ActionLogStore
+
+	// WorkerTimeout is the duration after which a running workflow fails if its worker hasn't
+	// sent a heartbeat. Zero disables liveness checks.
+	WorkerTimeout time.Duration
+}
+
+// Register registers the v1 workflow service on the gRPC server. Agents require the v2 workflow
+// service which this backend doesn't support so it's registered to reject them.
+func (s *BoltBackedServer) Register(server *grpc.Server) {
+	proto.RegisterWorkflowServiceServer(server, s)
+	workflowproto.RegisterWorkflowServiceServer(server, boltAgentService{})
+}
+
+// errAgentsUnsupported is returned to agents calling the v2 workflow service.
+const errAgentsUnsupported = "the bolt backend doesn't support agents; use tink-worker or --backend=kubernetes"
+
+// boltAgentService is a v2 WorkflowServiceServer that rejects agents.
+type boltAgentService struct {
+	workflowproto.UnimplementedWorkflowServiceServer
+}
+
+func (boltAgentService) GetWorkflows(*workflowproto.GetWorkflowsRequest, workflowproto.WorkflowService_GetWorkflowsServer) error {
+	return status.Error(codes.Unimplemented, errAgentsUnsupported)
+}
+
+func (boltAgentService) PublishEvent(context.Context, *workflowproto.PublishEventRequest) (*workflowproto.PublishEventResponse, error) {
+	return nil, status.Error(codes.Unimplemented, errAgentsUnsupported)
+}
+
+func (boltAgentService) Heartbeat(context.Context, *workflowproto.HeartbeatRequest) (*workflowproto.HeartbeatResponse, error) {
+	return nil, status.Error(codes.Unimplemented, errAgentsUnsupported)
+}
+
+// storeStatus converts errors returned by the store to gRPC status errors. Errors that are already
+// status errors are returned unchanged.
+func storeStatus(err error) error {
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+	switch {
+	case errors.Is(err, bolt.ErrNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, bolt.ErrAlreadyExists):
+		return status.Error(codes.AlreadyExists, err.Error())
+	default:
+		return status.Error(codes.Internal, err.Error())
+	}
+}
+
+// CreateWorkflow renders the Template referenced by wf and stores wf ready to be served to
+// workers. Boot options require Kubernetes and are rejected. Errors are gRPC status errors.
+func (s *BoltBackedServer) CreateWorkflow(wf *v1alpha1.Workflow) error {
+	if wf.Spec.BootOptions.ToggleAllowNetboot || wf.Spec.BootOptions.BootMode != "" {
+		return status.Error(codes.InvalidArgument, "boot options are not supported by the bolt backend")
+	}
+
+	tpl, err := s.store.GetTemplate(wf.Namespace, wf.Spec.TemplateRef)
+	if err != nil {
+		return storeStatus(fmt.Errorf("get template: %w", err))
+	}
+
+	var hardware v1alpha1.Hardware
+	if wf.Spec.HardwareRef != "" {
+		hw, err := s.store.GetHardware(wf.Namespace, wf.Spec.HardwareRef)
+		if err != nil {
+			return storeStatus(fmt.Errorf("get hardware: %w", err))
+		}
+		hardware = *hw
+	}
+
+	tinkWf, err := workflow.RenderTemplate(wf, tpl, hardware)
+	if err != nil {
+		return status.Errorf(codes.InvalidArgument, "render template: %v", err)
+	}
+
+	wf.Status = *workflow.YAMLToStatus(tinkWf)
+	wf.Status.TemplateRendering = v1alpha1.TemplateRenderingSuccessful
+	wf.Status.SetCondition(v1alpha1.WorkflowCondition{
+		Type:    v1alpha1.TemplateRenderedSuccess,
+		Status:  metav1.ConditionTrue,
+		Reason:  "Complete",
+		Message: "template rendered successfully",
+		Time:    &metav1.Time{Time: s.nowFunc().UTC()},
+	})
+	wf.Status.State = v1alpha1.WorkflowStatePending
+
+	if err := s.store.CreateWorkflow(wf); err != nil {
+		return storeStatus(err)
+	}
+	s.notifier.notify(wf)
+	return nil
+}
+
+// RunTimeoutChecks applies workflow timeouts and worker liveness to running workflows until ctx
+// is done. Without a controller to reconcile them, workflows are checked periodically.
+func (s *BoltBackedServer) RunTimeoutChecks(ctx context.Context) {
+	interval := s.timeoutCheckInterval
+	if interval == 0 {
+		interval = defaultWorkflowPollInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.checkTimeouts(); err != nil {
+			s.logger.Error(err, "check workflow timeouts")
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// checkTimeouts applies workflow timeouts and worker liveness to running workflows. Workflows are
+// evaluated from a read-only listing and only those whose status changes are written.
+func (s *BoltBackedServer) checkTimeouts() error {
+	wfs, err := s.store.ListWorkflows("")
+	if err != nil {
+		return err
+	}
+	for _, wf := range wfs {
+		if wf.Status.State != v1alpha1.WorkflowStateRunning || wf.GetStartTime() == nil {
+			continue
+		}
+		lastSeen, err := s.workerLastSeen(&wf)
+		if err != nil {
+			return err
+		}
+		apply := func(wf *v1alpha1.Workflow) {
+			now := s.nowFunc()
+			workflow.CheckTimeouts(wf, now)
+			if lastSeen != nil && wf.Status.State == v1alpha1.WorkflowStateRunning {
+				workflow.CheckWorkerLiveness(wf, *lastSeen, now, s.WorkerTimeout)
+			}
+		}
+
+		checked := wf.DeepCopy()
+		apply(checked)
+		if checked.Status.State == wf.Status.State {
+			continue
+		}
+
+		// The workflow may have changed since it was listed so apply the checks to the stored
+		// workflow.
+		var modified *v1alpha1.Workflow
+		err = s.store.ModifyWorkflow(wf.Namespace, wf.Name, func(stored *v1alpha1.Workflow) error {
+			if stored.Status.State != v1alpha1.WorkflowStateRunning {
+				return nil
+			}
+			apply(stored)
+			modified = stored
+			return nil
+		})
+		switch {
+		case errors.Is(err, bolt.ErrNotFound):
+			continue
+		case err != nil:
+			return err
+		}
+		if modified != nil {
+			s.notifier.notify(modified)
+		}
+	}
+	return nil
+}
+
+// workerLastSeen returns the time the current worker of wf last sent a heartbeat. It returns nil
+// if liveness checks are disabled or the worker has never sent a heartbeat.
+func (s *BoltBackedServer) workerLastSeen(wf *v1alpha1.Workflow) (*time.Time, error) {
+	workerID := wf.GetCurrentWorker()
+	if s.WorkerTimeout <= 0 || workerID == "" {
+		return nil, nil
+	}
+	worker, err := s.store.GetWorker(wf.Namespace, v1alpha1.WorkerName(workerID))
+	switch {
+	case errors.Is(err, bolt.ErrNotFound):
+		return nil, nil
+	case err != nil:
+		return nil, err
+	}
+	return &worker.Status.LastSeen.Time, nil
+}
+
+// The following APIs are used by the worker.
+
+func (s *BoltBackedServer) GetWorkflowContexts(req *proto.WorkflowContextRequest, stream proto.WorkflowService_GetWorkflowContextsServer) error {
+	if req.GetWorkerId() == "" {
+		return status.Errorf(codes.InvalidArgument, errInvalidWorkflowID)
+	}
+	if !req.GetWatch() {
+		return s.sendWorkflowContexts(stream, req.GetWorkerId(), nil)
+	}
+
+	// Subscribe before sending the current contexts so no change is missed.
+	changed, unsubscribe := s.notifier.subscribe(req.GetWorkerId())
+	defer unsubscribe()
+
+	sent := map[string]*proto.WorkflowContext{}
+	for {
+		if err := s.sendWorkflowContexts(stream, req.GetWorkerId(), sent); err != nil {
+			return err
+		}
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case <-changed:
+		}
+	}
+}
+
+// sendWorkflowContexts sends the contexts of workflows whose current task is assigned to
+// workerID. When sent is non-nil, contexts identical to those previously sent are skipped and
+// sent is updated.
+func (s *BoltBackedServer) sendWorkflowContexts(stream proto.WorkflowService_GetWorkflowContextsServer, workerID string, sent map[string]*proto.WorkflowContext) error {
+	wflows, err := s.store.ListWorkflows("")
+	if err != nil {
+		return storeStatus(err)
+	}
+	for _, wf := range wflows {
+		if !slices.Contains(workflowByNonTerminalStateFunc(&wf), workerID) {
+			continue
+		}
+		// If the current assigned or running action is assigned to the requested worker, include it
+		if wf.Status.Tasks[wf.GetCurrentTaskIndex()].WorkerAddr != workerID {
+			continue
+		}
+		wfContext := getWorkflowContext(wf)
+		if sent != nil {
+			if prev, ok := sent[wfContext.GetWorkflowId()]; ok && googleproto.Equal(prev, wfContext) {
+				continue
+			}
+			sent[wfContext.GetWorkflowId()] = wfContext
+		}
+		if err := stream.Send(wfContext); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *BoltBackedServer) GetWorkflowActions(ctx context.Context, req *proto.WorkflowActionsRequest) (*proto.WorkflowActionList, error) {
+	wfID := req.GetWorkflowId()
+	if wfID == "" {
+		return nil, status.Errorf(codes.InvalidArgument, errInvalidWorkflowID)
+	}
+	namespace, name, _ := strings.Cut(wfID, "/")
+	wf, err := s.store.GetWorkflow(namespace, name)
+	if err != nil {
+		s.logger.Error(err, "get workflow", "workflow", wfID)
+		return nil, storeStatus(err)
+	}
+	if err := authorizeWorkflowWorker(ctx, wf); err != nil {
+		return nil, err
+	}
+	return workflow.ActionListCRDToProto(wf), nil
+}
+
+func (s *BoltBackedServer) ReportActionStatus(ctx context.Context, req *proto.WorkflowActionStatus) (*proto.Empty, error) {
+	err := validateActionStatusRequest(req)
+	if err != nil {
+		return nil, err
+	}
+	l := s.logger.WithValues("actionName", req.GetActionName(), "status", req.GetActionStatus(), "workflowID", req.GetWorkflowId(), "taskName", req.GetTaskName(), "worker", req.WorkerId)
+
+	namespace, name, _ := strings.Cut(req.GetWorkflowId(), "/")
+	var modified *v1alpha1.Workflow
+	err = s.store.ModifyWorkflow(namespace, name, func(wf *v1alpha1.Workflow) error {
+		if err := applyActionStatus(ctx, l, wf, req, s.nowFunc); err != nil {
+			return err
+		}
+		modified = wf
+
+		// Post actions only perform boot options which this backend doesn't support so the
+		// workflow completes immediately.
+		if wf.Status.State == v1alpha1.WorkflowStatePost {
+			wf.Status.State = v1alpha1.WorkflowStateSuccess
+		}
+		return nil
+	})
+	if err != nil {
+		if _, ok := status.FromError(err); ok {
+			return nil, err
+		}
+		if errors.Is(err, bolt.ErrNotFound) {
+			return nil, status.Errorf(codes.NotFound, "workflow %v not found", req.GetWorkflowId())
+		}
+		l.Error(err, "applying update to workflow")
+		return nil, status.Errorf(codes.Internal, "update workflow: %v", err)
+	}
+	s.notifier.notify(modified)
+	return &proto.Empty{}, nil
+}
+
+// WorkerHeartbeat records the worker identified by req.WorkerId as alive. Liveness is recorded
+// in a Worker in the namespace of the Hardware whose MAC matches the worker ID.
+func (s *BoltBackedServer) WorkerHeartbeat(_ context.Context, req *proto.WorkerHeartbeatRequest) (*proto.Empty, error) {
+	workerID := req.GetWorkerId()
+	if workerID == "" {
+		return nil, status.Errorf(codes.InvalidArgument, errInvalidWorkerID)
+	}
+
+	hardware, err := s.store.ListHardware("")
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "list hardware: %v", err)
+	}
+	i := slices.IndexFunc(hardware, func(hw v1alpha1.Hardware) bool {
+		return slices.Contains(hw.GetMACs(), workerID)
+	})
+	if i < 0 {
+		return nil, status.Errorf(codes.NotFound, errHardwareNotFoundForWorker)
+	}
+
+	worker := &v1alpha1.Worker{ObjectMeta: metav1.ObjectMeta{
+		Namespace: hardware[i].Namespace,
+		Name:      v1alpha1.WorkerName(workerID),
+	}}
+	if stored, err := s.store.GetWorker(worker.Namespace, worker.Name); err == nil {
+		worker = stored
+	}
+	worker.Status = workerStatus(req, s.nowFunc())
+
+	err = s.store.UpdateWorker(worker)
+	if errors.Is(err, bolt.ErrNotFound) {
+		err = s.store.CreateWorker(worker)
+	}
+	if err != nil {
+		s.logger.Error(err, "record worker heartbeat", "workerID", workerID)
+		return nil, status.Errorf(codes.Internal, "record heartbeat: %v", err)
+	}
+
+	return &proto.Empty{}, nil
+}
+
+// UploadActionLogs persists action logs uploaded by workers to s.ActionLogs.
+func (s *BoltBackedServer) UploadActionLogs(stream proto.WorkflowService_UploadActionLogsServer) error {
+	return receiveActionLogs(s.ActionLogs, stream, func(_ context.Context, workflowID string) (*v1alpha1.Workflow, error) {
+		namespace, name, _ := strings.Cut(workflowID, "/")
+		wf, err := s.store.GetWorkflow(namespace, name)
+		switch {
+		case errors.Is(err, bolt.ErrNotFound):
+			return nil, status.Errorf(codes.NotFound, "workflow %v not found", workflowID)
+		case err != nil:
+			return nil, status.Errorf(codes.Internal, "get workflow: %v", err)
+		}
+		return wf, nil
+	})
+}
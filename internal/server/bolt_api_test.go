@@ -0,0 +1,373 @@
+package server
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/google/go-cmp/cmp"
+	"github.com/tinkerbell/tink/api/v1alpha1"
+	"github.com/tinkerbell/tink/internal/bolt"
+	"github.com/tinkerbell/tink/internal/grpcserver"
+	"github.com/tinkerbell/tink/internal/proto"
+	workflowproto "github.com/tinkerbell/tink/internal/proto/workflow/v2"
+	"github.com/tinkerbell/tink/internal/ptr"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/testing/protocmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// workflowContextsStream is a fake WorkflowService_GetWorkflowContextsServer that records sent
+// contexts.
+type workflowContextsStream struct {
+	grpc.ServerStream
+	sent []*proto.WorkflowContext
+}
+
+func (s *workflowContextsStream) Context() context.Context { return context.Background() }
+
+func (s *workflowContextsStream) Send(c *proto.WorkflowContext) error {
+	s.sent = append(s.sent, c)
+	return nil
+}
+
+const boltTestTemplate = `version: "0.1"
+name: debian_provisioning
+global_timeout: 1800
+tasks:
+  - name: "os-installation"
+    worker: "{{.device_1}}"
+    actions:
+      - name: "stream-image"
+        image: quay.io/tinkerbell-actions/image2disk:v1.0.0
+        timeout: 60
+      - name: "kexec"
+        image: quay.io/tinkerbell-actions/kexec:v1.0.0
+        timeout: 90
+`
+
+func newBoltTestServer(t *testing.T) *BoltBackedServer {
+	t.Helper()
+
+	store, err := bolt.Open(filepath.Join(t.TempDir(), "tink.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+
+	err = store.CreateTemplate(&v1alpha1.Template{
+		ObjectMeta: metav1.ObjectMeta{Name: "debian", Namespace: "default"},
+		Spec:       v1alpha1.TemplateSpec{Data: ptr.String(boltTestTemplate)},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := NewBoltBackedServer(logr.Discard(), store)
+	s.nowFunc = TestTime.Now
+
+	err = s.CreateWorkflow(&v1alpha1.Workflow{
+		ObjectMeta: metav1.ObjectMeta{Name: "debian", Namespace: "default"},
+		Spec: v1alpha1.WorkflowSpec{
+			TemplateRef: "debian",
+			HardwareMap: map[string]string{"device_1": "3c:ec:ef:4c:4f:54"},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return s
+}
+
+func TestBoltBackedServer_CreateWorkflowBootOptions(t *testing.T) {
+	s := newBoltTestServer(t)
+
+	err := s.CreateWorkflow(&v1alpha1.Workflow{
+		ObjectMeta: metav1.ObjectMeta{Name: "netboot", Namespace: "default"},
+		Spec: v1alpha1.WorkflowSpec{
+			TemplateRef: "debian",
+			BootOptions: v1alpha1.BootOptions{ToggleAllowNetboot: true},
+		},
+	})
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+}
+
+func TestBoltBackedServer_GetWorkflowContexts(t *testing.T) {
+	s := newBoltTestServer(t)
+
+	stream := &workflowContextsStream{}
+	if err := s.GetWorkflowContexts(&proto.WorkflowContextRequest{WorkerId: "other"}, stream); err != nil {
+		t.Fatal(err)
+	}
+	if len(stream.sent) != 0 {
+		t.Fatalf("expected no contexts for other worker, got %v", stream.sent)
+	}
+
+	if err := s.GetWorkflowContexts(&proto.WorkflowContextRequest{WorkerId: "3c:ec:ef:4c:4f:54"}, stream); err != nil {
+		t.Fatal(err)
+	}
+	want := []*proto.WorkflowContext{
+		{
+			WorkflowId:           "default/debian",
+			CurrentWorker:        "3c:ec:ef:4c:4f:54",
+			CurrentTask:          "os-installation",
+			CurrentAction:        "stream-image",
+			CurrentActionState:   proto.State_STATE_PENDING,
+			TotalNumberOfActions: 2,
+		},
+	}
+	if diff := cmp.Diff(want, stream.sent, protocmp.Transform()); diff != "" {
+		t.Fatalf("unexpected contexts (-want +got):\n%s", diff)
+	}
+}
+
+func TestBoltBackedServer_ReportActionStatus(t *testing.T) {
+	s := newBoltTestServer(t)
+	ctx := context.Background()
+
+	report := func(action string, state proto.State) {
+		t.Helper()
+		_, err := s.ReportActionStatus(ctx, &proto.WorkflowActionStatus{
+			WorkflowId:   "default/debian",
+			TaskName:     "os-installation",
+			ActionName:   action,
+			ActionStatus: state,
+			WorkerId:     "3c:ec:ef:4c:4f:54",
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	report("stream-image", proto.State_STATE_RUNNING)
+	report("stream-image", proto.State_STATE_SUCCESS)
+	report("kexec", proto.State_STATE_RUNNING)
+	report("kexec", proto.State_STATE_SUCCESS)
+
+	wf, err := s.store.GetWorkflow("default", "debian")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if wf.Status.State != v1alpha1.WorkflowStateSuccess {
+		t.Fatalf("expected state %v, got %v", v1alpha1.WorkflowStateSuccess, wf.Status.State)
+	}
+	for _, action := range wf.Status.Tasks[0].Actions {
+		if len(action.Attempts) != 1 {
+			t.Fatalf("expected 1 attempt for %v, got %v", action.Name, len(action.Attempts))
+		}
+	}
+
+	_, err = s.ReportActionStatus(ctx, &proto.WorkflowActionStatus{
+		WorkflowId:   "default/missing",
+		TaskName:     "os-installation",
+		ActionName:   "kexec",
+		ActionStatus: proto.State_STATE_RUNNING,
+	})
+	if err == nil {
+		t.Fatal("expected an error for a missing workflow")
+	}
+}
+
+func TestBoltBackedServer_Timeout(t *testing.T) {
+	s := newBoltTestServer(t)
+
+	_, err := s.ReportActionStatus(context.Background(), &proto.WorkflowActionStatus{
+		WorkflowId:   "default/debian",
+		TaskName:     "os-installation",
+		ActionName:   "stream-image",
+		ActionStatus: proto.State_STATE_RUNNING,
+		WorkerId:     "3c:ec:ef:4c:4f:54",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s.nowFunc = func() time.Time { return TestTime.Now().Add(2 * time.Minute) }
+	if err := s.checkTimeouts(); err != nil {
+		t.Fatal(err)
+	}
+
+	stream := &workflowContextsStream{}
+	if err := s.GetWorkflowContexts(&proto.WorkflowContextRequest{WorkerId: "3c:ec:ef:4c:4f:54"}, stream); err != nil {
+		t.Fatal(err)
+	}
+	if len(stream.sent) != 0 {
+		t.Fatalf("expected no contexts for a timed out workflow, got %v", stream.sent)
+	}
+
+	wf, err := s.store.GetWorkflow("default", "debian")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if wf.Status.State != v1alpha1.WorkflowStateTimeout {
+		t.Fatalf("expected state %v, got %v", v1alpha1.WorkflowStateTimeout, wf.Status.State)
+	}
+}
+
+func TestBoltBackedServer_ReportActionStatusNotOwned(t *testing.T) {
+	s := newBoltTestServer(t)
+
+	ctx := grpcserver.ContextWithIdentity(context.Background(), "other")
+	_, err := s.ReportActionStatus(ctx, &proto.WorkflowActionStatus{
+		WorkflowId:   "default/debian",
+		TaskName:     "os-installation",
+		ActionName:   "stream-image",
+		ActionStatus: proto.State_STATE_RUNNING,
+		WorkerId:     "other",
+	})
+	if code := status.Code(err); code != codes.PermissionDenied {
+		t.Fatalf("expected code %v, got %v: %v", codes.PermissionDenied, code, err)
+	}
+}
+
+func TestBoltBackedServer_GetWorkflowActionsNotOwned(t *testing.T) {
+	s := newBoltTestServer(t)
+	req := &proto.WorkflowActionsRequest{WorkflowId: "default/debian"}
+
+	ctx := grpcserver.ContextWithIdentity(context.Background(), "other")
+	if _, err := s.GetWorkflowActions(ctx, req); status.Code(err) != codes.PermissionDenied {
+		t.Fatalf("expected code %v, got %v: %v", codes.PermissionDenied, status.Code(err), err)
+	}
+
+	ctx = grpcserver.ContextWithIdentity(context.Background(), "3c:ec:ef:4c:4f:54")
+	if _, err := s.GetWorkflowActions(ctx, req); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestBoltBackedServer_WorkerLost(t *testing.T) {
+	s := newBoltTestServer(t)
+	s.WorkerTimeout = 30 * time.Second
+	ctx := context.Background()
+
+	err := s.store.CreateHardware(&v1alpha1.Hardware{
+		ObjectMeta: metav1.ObjectMeta{Name: "hardware", Namespace: "default"},
+		Spec: v1alpha1.HardwareSpec{
+			Interfaces: []v1alpha1.Interface{{DHCP: &v1alpha1.DHCP{MAC: "3c:ec:ef:4c:4f:54"}}},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := s.WorkerHeartbeat(ctx, &proto.WorkerHeartbeatRequest{WorkerId: "00:00:00:00:00:02"}); status.Code(err) != codes.NotFound {
+		t.Fatalf("expected code %v for an unknown worker, got %v", codes.NotFound, err)
+	}
+	if _, err := s.WorkerHeartbeat(ctx, &proto.WorkerHeartbeatRequest{WorkerId: "3c:ec:ef:4c:4f:54"}); err != nil {
+		t.Fatal(err)
+	}
+	_, err = s.ReportActionStatus(ctx, &proto.WorkflowActionStatus{
+		WorkflowId:   "default/debian",
+		TaskName:     "os-installation",
+		ActionName:   "stream-image",
+		ActionStatus: proto.State_STATE_RUNNING,
+		WorkerId:     "3c:ec:ef:4c:4f:54",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	getState := func() *v1alpha1.Workflow {
+		t.Helper()
+		if err := s.checkTimeouts(); err != nil {
+			t.Fatal(err)
+		}
+		wf, err := s.store.GetWorkflow("default", "debian")
+		if err != nil {
+			t.Fatal(err)
+		}
+		return wf
+	}
+
+	s.nowFunc = func() time.Time { return TestTime.Now().Add(20 * time.Second) }
+	if wf := getState(); wf.Status.State != v1alpha1.WorkflowStateRunning {
+		t.Fatalf("expected state %v, got %v", v1alpha1.WorkflowStateRunning, wf.Status.State)
+	}
+
+	s.nowFunc = func() time.Time { return TestTime.Now().Add(45 * time.Second) }
+	wf := getState()
+	if wf.Status.State != v1alpha1.WorkflowStateFailed {
+		t.Fatalf("expected state %v, got %v", v1alpha1.WorkflowStateFailed, wf.Status.State)
+	}
+	if st := wf.Status.Tasks[0].Actions[0].Status; st != v1alpha1.WorkflowStateFailed {
+		t.Fatalf("expected action state %v, got %v", v1alpha1.WorkflowStateFailed, st)
+	}
+	if !wf.Status.HasCondition(v1alpha1.WorkerLost, metav1.ConditionTrue) {
+		t.Fatalf("expected %v condition, got %v", v1alpha1.WorkerLost, wf.Status.Conditions)
+	}
+}
+
+func TestBoltBackedServer_GetWorkflowActionsNotFound(t *testing.T) {
+	s := newBoltTestServer(t)
+
+	_, err := s.GetWorkflowActions(context.Background(), &proto.WorkflowActionsRequest{WorkflowId: "default/missing"})
+	if code := status.Code(err); code != codes.NotFound {
+		t.Fatalf("expected code %v, got %v: %v", codes.NotFound, code, err)
+	}
+}
+
+func TestBoltBackedServer_WatchWorkflowContexts(t *testing.T) {
+	s := newBoltTestServer(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	stream := &watchContextsStream{ctx: ctx, sent: make(chan *proto.WorkflowContext, 10)}
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- s.GetWorkflowContexts(&proto.WorkflowContextRequest{WorkerId: "3c:ec:ef:4c:4f:54", Watch: true}, stream)
+	}()
+
+	receive := func() *proto.WorkflowContext {
+		t.Helper()
+		select {
+		case c := <-stream.sent:
+			return c
+		case <-time.After(5 * time.Second):
+			t.Fatal("timed out waiting for workflow context")
+			return nil
+		}
+	}
+
+	if state := receive().GetCurrentActionState(); state != proto.State_STATE_PENDING {
+		t.Fatalf("expected initial state %v, got %v", proto.State_STATE_PENDING, state)
+	}
+
+	_, err := s.ReportActionStatus(ctx, &proto.WorkflowActionStatus{
+		WorkflowId:   "default/debian",
+		TaskName:     "os-installation",
+		ActionName:   "stream-image",
+		ActionStatus: proto.State_STATE_RUNNING,
+		WorkerId:     "3c:ec:ef:4c:4f:54",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if state := receive().GetCurrentActionState(); state != proto.State_STATE_RUNNING {
+		t.Fatalf("expected pushed state %v, got %v", proto.State_STATE_RUNNING, state)
+	}
+
+	cancel()
+	if err := <-errCh; err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestBoltBackedServer_RejectsAgents(t *testing.T) {
+	var agents boltAgentService
+
+	_, err := agents.Heartbeat(context.Background(), &workflowproto.HeartbeatRequest{AgentId: "agent"})
+	if code := status.Code(err); code != codes.Unimplemented {
+		t.Fatalf("expected code %v, got %v: %v", codes.Unimplemented, code, err)
+	}
+	if err := agents.GetWorkflows(&workflowproto.GetWorkflowsRequest{AgentId: "agent"}, nil); status.Code(err) != codes.Unimplemented {
+		t.Fatalf("expected code %v, got %v: %v", codes.Unimplemented, status.Code(err), err)
+	}
+}
@@ -0,0 +1,318 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/go-logr/logr"
+	"github.com/tinkerbell/tink/api/v1alpha1"
+	"github.com/tinkerbell/tink/internal/deprecated/workflow"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+)
+
+// HTTP paths for the bolt management API. {kind} is one of hardware, templates or workflows.
+const (
+	BoltAPIListAllPath  = "/v1alpha1/{kind}"
+	BoltAPIListPath     = "/v1alpha1/namespaces/{namespace}/{kind}"
+	BoltAPIResourcePath = "/v1alpha1/namespaces/{namespace}/{kind}/{name}"
+)
+
+// maxBoltAPIRequestSize is the maximum size of a management API request body.
+const maxBoltAPIRequestSize = 1 << 20
+
+// BoltHTTPHandler exposes management of the Hardware, Templates and Workflows stored by a
+// BoltBackedServer over HTTP. It's the bolt backend's equivalent of managing objects with the
+// Kubernetes API. Objects are accepted as JSON or YAML and returned as JSON.
+//
+// Hardware and Templates can be created, retrieved, listed, replaced and deleted. They're validated
+// as the Kubernetes admission webhooks would. Workflows are rendered when created and can't be
+// replaced. Requests aren't authenticated so the handler
+// shouldn't be exposed beyond trusted networks.
+type BoltHTTPHandler struct {
+	logger    logr.Logger
+	resources map[string]boltResource
+	mux       *http.ServeMux
+}
+
+// NewBoltHTTPHandler creates a BoltHTTPHandler managing the objects stored by srv.
+func NewBoltHTTPHandler(logger logr.Logger, srv *BoltBackedServer) *BoltHTTPHandler {
+	h := &BoltHTTPHandler{
+		logger: logger,
+		resources: map[string]boltResource{
+			"hardware": newBoltResource(
+				srv.validateHardware,
+				srv.store.CreateHardware,
+				srv.store.GetHardware,
+				srv.store.ListHardware,
+				srv.store.UpdateHardware,
+				srv.store.DeleteHardware,
+			),
+			"templates": newBoltResource(
+				validateTemplate,
+				srv.store.CreateTemplate,
+				srv.store.GetTemplate,
+				srv.store.ListTemplates,
+				srv.store.UpdateTemplate,
+				srv.store.DeleteTemplate,
+			),
+			"workflows": newBoltResource(
+				nil,
+				srv.CreateWorkflow,
+				srv.store.GetWorkflow,
+				srv.store.ListWorkflows,
+				nil,
+				srv.store.DeleteWorkflow,
+			),
+		},
+		mux: http.NewServeMux(),
+	}
+	h.mux.HandleFunc("GET "+BoltAPIListAllPath, h.list)
+	h.mux.HandleFunc("GET "+BoltAPIListPath, h.list)
+	h.mux.HandleFunc("POST "+BoltAPIListPath, h.create)
+	h.mux.HandleFunc("GET "+BoltAPIResourcePath, h.get)
+	h.mux.HandleFunc("PUT "+BoltAPIResourcePath, h.update)
+	h.mux.HandleFunc("DELETE "+BoltAPIResourcePath, h.delete)
+	return h
+}
+
+func (h *BoltHTTPHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.mux.ServeHTTP(w, r)
+}
+
+// boltResource adapts the store operations of a kind to the untyped handler.
+type boltResource struct {
+	decode func([]byte) (metav1.Object, error)
+	create func(metav1.Object) error
+	get    func(namespace, name string) (any, error)
+	list   func(namespace string) (any, error)
+
+	// update is nil for kinds that can't be replaced.
+	update func(metav1.Object) error
+	remove func(namespace, name string) error
+}
+
+func newBoltResource[T any, PT interface {
+	*T
+	metav1.Object
+}](
+	validate func(PT) error,
+	create func(PT) error,
+	get func(namespace, name string) (PT, error),
+	list func(namespace string) ([]T, error),
+	update func(PT) error,
+	remove func(namespace, name string) error,
+) boltResource {
+	r := boltResource{
+		decode: func(data []byte) (metav1.Object, error) {
+			obj := PT(new(T))
+			return obj, yaml.UnmarshalStrict(data, obj)
+		},
+		create: func(obj metav1.Object) error {
+			if validate != nil {
+				if err := validate(obj.(PT)); err != nil {
+					return err
+				}
+			}
+			return create(obj.(PT))
+		},
+		get: func(namespace, name string) (any, error) { return get(namespace, name) },
+		list: func(namespace string) (any, error) {
+			items, err := list(namespace)
+			return boltList[T]{Items: items}, err
+		},
+		remove: remove,
+	}
+	if update != nil {
+		r.update = func(obj metav1.Object) error {
+			// Preserve fields maintained by the store.
+			stored, err := get(obj.GetNamespace(), obj.GetName())
+			if err != nil {
+				return err
+			}
+			obj.SetCreationTimestamp(stored.GetCreationTimestamp())
+			if validate != nil {
+				if err := validate(obj.(PT)); err != nil {
+					return err
+				}
+			}
+			return update(obj.(PT))
+		}
+	}
+	return r
+}
+
+// validateTemplate validates tpl the way the Template admission webhook does for Kubernetes.
+func validateTemplate(tpl *v1alpha1.Template) error {
+	// Templates without data are permitted and fail when a Workflow is rendered.
+	if tpl.Spec.Data == nil {
+		return nil
+	}
+	if err := workflow.ValidateTemplateData(*tpl.Spec.Data); err != nil {
+		return status.Errorf(codes.InvalidArgument, "invalid Template: %v", err)
+	}
+	return nil
+}
+
+// macRegex matches valid MAC addresses. MACs must be lowercase because workers are matched to
+// Hardware by comparing MACs.
+var macRegex = regexp.MustCompile("^([0-9a-f]{2}:){5}([0-9a-f]{2})$")
+
+// validateHardware ensures the MACs of hw are valid and not associated with other Hardware.
+func (s *BoltBackedServer) validateHardware(hw *v1alpha1.Hardware) error {
+	var invalid []string
+	for _, mac := range hw.GetMACs() {
+		if !macRegex.MatchString(mac) {
+			invalid = append(invalid, fmt.Sprintf("%q", mac))
+		}
+	}
+	if len(invalid) > 0 {
+		return status.Errorf(codes.InvalidArgument, "invalid MAC address (%v): %v", macRegex, strings.Join(invalid, ", "))
+	}
+
+	stored, err := s.store.ListHardware("")
+	if err != nil {
+		return err
+	}
+	for _, other := range stored {
+		if other.Namespace == hw.Namespace && other.Name == hw.Name {
+			continue
+		}
+		for _, mac := range hw.GetMACs() {
+			if slices.Contains(other.GetMACs(), mac) {
+				return status.Errorf(codes.InvalidArgument, "MAC %v associated with existing Hardware %v/%v", mac, other.Namespace, other.Name)
+			}
+		}
+	}
+	return nil
+}
+
+// boltList is the body of list responses.
+type boltList[T any] struct {
+	Items []T `json:"items"`
+}
+
+// resource returns the boltResource for the kind identified by r. It writes a NotFound error and
+// returns false for unknown kinds.
+func (h *BoltHTTPHandler) resource(w http.ResponseWriter, r *http.Request) (boltResource, bool) {
+	res, ok := h.resources[r.PathValue("kind")]
+	if !ok {
+		writeHTTPError(w, status.Errorf(codes.NotFound, "unknown kind %q", r.PathValue("kind")))
+	}
+	return res, ok
+}
+
+func (h *BoltHTTPHandler) list(w http.ResponseWriter, r *http.Request) {
+	res, ok := h.resource(w, r)
+	if !ok {
+		return
+	}
+	items, err := res.list(r.PathValue("namespace"))
+	h.writeResponse(w, http.StatusOK, items, err)
+}
+
+func (h *BoltHTTPHandler) get(w http.ResponseWriter, r *http.Request) {
+	res, ok := h.resource(w, r)
+	if !ok {
+		return
+	}
+	obj, err := res.get(r.PathValue("namespace"), r.PathValue("name"))
+	h.writeResponse(w, http.StatusOK, obj, err)
+}
+
+func (h *BoltHTTPHandler) create(w http.ResponseWriter, r *http.Request) {
+	res, ok := h.resource(w, r)
+	if !ok {
+		return
+	}
+	obj, ok := h.decode(w, r, res)
+	if !ok {
+		return
+	}
+	h.writeResponse(w, http.StatusCreated, obj, res.create(obj))
+}
+
+func (h *BoltHTTPHandler) update(w http.ResponseWriter, r *http.Request) {
+	res, ok := h.resource(w, r)
+	if !ok {
+		return
+	}
+	if res.update == nil {
+		writeHTTPError(w, status.Errorf(codes.FailedPrecondition, "%v can't be replaced; delete and recreate them", r.PathValue("kind")))
+		return
+	}
+	obj, ok := h.decode(w, r, res)
+	if !ok {
+		return
+	}
+	h.writeResponse(w, http.StatusOK, obj, res.update(obj))
+}
+
+func (h *BoltHTTPHandler) delete(w http.ResponseWriter, r *http.Request) {
+	res, ok := h.resource(w, r)
+	if !ok {
+		return
+	}
+	if err := res.remove(r.PathValue("namespace"), r.PathValue("name")); err != nil {
+		writeHTTPError(w, storeStatus(err))
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// decode decodes the object in the body of r. The object's namespace and name default to those in
+// the path and must match them if set. It writes an error and returns false if the object is
+// invalid.
+func (h *BoltHTTPHandler) decode(w http.ResponseWriter, r *http.Request, res boltResource) (metav1.Object, bool) {
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxBoltAPIRequestSize))
+	if err != nil {
+		writeHTTPError(w, status.Errorf(codes.InvalidArgument, "read body: %v", err))
+		return nil, false
+	}
+	obj, err := res.decode(body)
+	if err != nil {
+		writeHTTPError(w, status.Errorf(codes.InvalidArgument, "invalid object: %v", err))
+		return nil, false
+	}
+
+	namespace, name := r.PathValue("namespace"), r.PathValue("name")
+	if obj.GetNamespace() == "" {
+		obj.SetNamespace(namespace)
+	}
+	if obj.GetName() == "" {
+		obj.SetName(name)
+	}
+	if obj.GetNamespace() != namespace || (name != "" && obj.GetName() != name) {
+		writeHTTPError(w, status.Errorf(codes.InvalidArgument, "object %v/%v doesn't match the request path", obj.GetNamespace(), obj.GetName()))
+		return nil, false
+	}
+	return obj, true
+}
+
+// writeResponse writes obj as JSON with code, or err if it's non-nil.
+func (h *BoltHTTPHandler) writeResponse(w http.ResponseWriter, code int, obj any, err error) {
+	if err != nil {
+		err = storeStatus(err)
+		if status.Code(err) == codes.Internal {
+			h.logger.Error(err, "bolt management request")
+		}
+		writeHTTPError(w, err)
+		return
+	}
+
+	data, err := json.Marshal(obj)
+	if err != nil {
+		writeHTTPError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_, _ = w.Write(data)
+}
@@ -0,0 +1,122 @@
+package server
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/tinkerbell/tink/api/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const boltHTTPTestHardware = `
+metadata:
+  name: machine1
+spec:
+  interfaces:
+    - dhcp:
+        mac: 3c:ec:ef:4c:4f:54
+`
+
+func TestBoltHTTPHandler(t *testing.T) {
+	s := newBoltTestServer(t)
+	ts := httptest.NewServer(NewBoltHTTPHandler(logr.Discard(), s))
+	defer ts.Close()
+
+	do := func(method, path, body string, expectStatus int) []byte {
+		t.Helper()
+		req, err := http.NewRequest(method, ts.URL+path, strings.NewReader(body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		data, err := io.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if resp.StatusCode != expectStatus {
+			t.Fatalf("%v %v: expected status %v, got %v: %s", method, path, expectStatus, resp.StatusCode, data)
+		}
+		return data
+	}
+
+	do(http.MethodPost, "/v1alpha1/namespaces/default/hardware", boltHTTPTestHardware, http.StatusCreated)
+	do(http.MethodPost, "/v1alpha1/namespaces/default/hardware", boltHTTPTestHardware, http.StatusConflict)
+	do(http.MethodPost, "/v1alpha1/namespaces/other/hardware", "metadata: {name: machine2, namespace: default}", http.StatusBadRequest)
+	do(http.MethodPost, "/v1alpha1/namespaces/default/hardware", "unknown: field", http.StatusBadRequest)
+	do(http.MethodGet, "/v1alpha1/namespaces/default/machines", "", http.StatusNotFound)
+	do(http.MethodPost, "/v1alpha1/namespaces/default/hardware", strings.Replace(boltHTTPTestHardware, "machine1", "machine2", 1), http.StatusBadRequest)
+	do(http.MethodPost, "/v1alpha1/namespaces/default/hardware", strings.Replace(boltHTTPTestHardware, "3c:ec:ef:4c:4f:54", "3C:EC:EF:4C:4F:55", 1), http.StatusBadRequest)
+	do(http.MethodPost, "/v1alpha1/namespaces/default/templates", "{metadata: {name: invalid}, spec: {data: '{{ .missing'}}", http.StatusBadRequest)
+	do(http.MethodPost, "/v1alpha1/namespaces/default/templates", `
+metadata:
+  name: notasks
+spec:
+  data: |
+    version: "0.1"
+    name: notasks
+`, http.StatusBadRequest)
+	do(http.MethodPut, "/v1alpha1/namespaces/default/templates/debian", "{spec: {data: '{{ .missing'}}", http.StatusBadRequest)
+
+	var hw v1alpha1.Hardware
+	if err := json.Unmarshal(do(http.MethodGet, "/v1alpha1/namespaces/default/hardware/machine1", "", http.StatusOK), &hw); err != nil {
+		t.Fatal(err)
+	}
+	if macs := hw.GetMACs(); len(macs) != 1 || macs[0] != "3c:ec:ef:4c:4f:54" {
+		t.Fatalf("unexpected hardware MACs: %v", macs)
+	}
+	created := hw.CreationTimestamp
+
+	hw.Spec.Interfaces[0].DHCP.Hostname = "machine1"
+	hw.CreationTimestamp = metav1.Time{}
+	update, _ := json.Marshal(hw)
+	do(http.MethodPut, "/v1alpha1/namespaces/default/hardware/machine1", string(update), http.StatusOK)
+	do(http.MethodPut, "/v1alpha1/namespaces/default/hardware/machine2", string(update), http.StatusBadRequest)
+
+	stored, err := s.store.GetHardware("default", "machine1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stored.Spec.Interfaces[0].DHCP.Hostname != "machine1" || !stored.CreationTimestamp.Equal(&created) {
+		t.Fatalf("unexpected updated hardware: %+v", stored)
+	}
+
+	do(http.MethodPost, "/v1alpha1/namespaces/default/workflows", `
+metadata:
+  name: machine1
+spec:
+  templateRef: debian
+  hardwareRef: machine1
+  hardwareMap:
+    device_1: 3c:ec:ef:4c:4f:54
+`, http.StatusCreated)
+	do(http.MethodPost, "/v1alpha1/namespaces/default/workflows", `{"metadata": {"name": "missing"}, "spec": {"templateRef": "missing"}}`, http.StatusNotFound)
+	do(http.MethodPut, "/v1alpha1/namespaces/default/workflows/machine1", `{}`, http.StatusConflict)
+
+	var workflows struct {
+		Items []v1alpha1.Workflow `json:"items"`
+	}
+	if err := json.Unmarshal(do(http.MethodGet, "/v1alpha1/workflows", "", http.StatusOK), &workflows); err != nil {
+		t.Fatal(err)
+	}
+	if len(workflows.Items) != 2 {
+		t.Fatalf("expected 2 workflows, got %v", len(workflows.Items))
+	}
+	for _, wf := range workflows.Items {
+		if wf.Status.State != v1alpha1.WorkflowStatePending {
+			t.Fatalf("expected workflow %v to be rendered, got state %q", wf.Name, wf.Status.State)
+		}
+	}
+
+	do(http.MethodDelete, "/v1alpha1/namespaces/default/workflows/machine1", "", http.StatusNoContent)
+	do(http.MethodDelete, "/v1alpha1/namespaces/default/workflows/machine1", "", http.StatusNotFound)
+	do(http.MethodGet, "/v1alpha1/namespaces/default/workflows/machine1", "", http.StatusNotFound)
+}
@@ -0,0 +1,316 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/tinkerbell/tink/internal/grpcserver"
+	workflowproto "github.com/tinkerbell/tink/internal/proto/workflow/v2"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	googleproto "google.golang.org/protobuf/proto"
+)
+
+// HTTP paths for the workflow API. They mirror the v2 WorkflowService RPCs.
+const (
+	HTTPGetWorkflowsPath = "/v2/agents/{agentID}/workflows"
+	HTTPPublishEventPath = "/v2/events"
+	HTTPHeartbeatPath    = "/v2/heartbeats"
+)
+
+// defaultSSEKeepAliveInterval is the interval at which comments are written to idle workflow
+// streams so proxies don't close them.
+const defaultSSEKeepAliveInterval = 15 * time.Second
+
+// maxHTTPRequestSize is the maximum size of a request body. Events carrying action output are the
+// largest requests.
+const maxHTTPRequestSize = 4 << 20
+
+// WorkflowHTTPHandler exposes a v2 WorkflowServiceServer over HTTP/1.1 for agents on networks
+// that block HTTP/2 or gRPC. Workflow commands are streamed as Server-Sent Events and events are
+// published as JSON. Messages use the protobuf JSON mapping so semantics match the gRPC API.
+type WorkflowHTTPHandler struct {
+	logger         logr.Logger
+	srv            workflowproto.WorkflowServiceServer
+	authenticators []grpcserver.Authenticator
+	mux            *http.ServeMux
+
+	keepAliveInterval time.Duration
+}
+
+// NewWorkflowHTTPHandler creates a WorkflowHTTPHandler serving srv. When authenticators are
+// provided, requests are authenticated and authorized the same way as gRPC requests; bearer
+// tokens are read from the Authorization header and client certificates from the TLS connection.
+func NewWorkflowHTTPHandler(logger logr.Logger, srv workflowproto.WorkflowServiceServer, authenticators ...grpcserver.Authenticator) *WorkflowHTTPHandler {
+	h := &WorkflowHTTPHandler{
+		logger:            logger,
+		srv:               srv,
+		authenticators:    authenticators,
+		mux:               http.NewServeMux(),
+		keepAliveInterval: defaultSSEKeepAliveInterval,
+	}
+	h.mux.HandleFunc("GET "+HTTPGetWorkflowsPath, h.getWorkflows)
+	h.mux.HandleFunc("POST "+HTTPPublishEventPath, h.publishEvent)
+	h.mux.HandleFunc("POST "+HTTPHeartbeatPath, h.heartbeat)
+	return h
+}
+
+func (h *WorkflowHTTPHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.mux.ServeHTTP(w, r)
+}
+
+// authenticate authenticates r returning a context carrying the caller's identity.
+func (h *WorkflowHTTPHandler) authenticate(r *http.Request) (context.Context, error) {
+	ctx := r.Context()
+	if len(h.authenticators) == 0 {
+		return ctx, nil
+	}
+
+	// Present credentials the way gRPC would so the same authenticators can be used.
+	if v := r.Header.Get("Authorization"); v != "" {
+		ctx = metadata.NewIncomingContext(ctx, metadata.Pairs("authorization", v))
+	}
+	if r.TLS != nil {
+		ctx = peer.NewContext(ctx, &peer.Peer{AuthInfo: credentials.TLSInfo{State: *r.TLS}})
+	}
+
+	return grpcserver.Authenticate(ctx, h.authenticators...)
+}
+
+func (h *WorkflowHTTPHandler) getWorkflows(w http.ResponseWriter, r *http.Request) {
+	ctx, err := h.authenticate(r)
+	if err != nil {
+		writeHTTPError(w, err)
+		return
+	}
+
+	req := &workflowproto.GetWorkflowsRequest{
+		AgentId:            r.PathValue("agentID"),
+		RunningWorkflowIds: r.URL.Query()["running"],
+	}
+	if err := grpcserver.AuthorizeRequest(ctx, req); err != nil {
+		writeHTTPError(w, err)
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeHTTPError(w, status.Error(codes.Internal, "streaming unsupported"))
+		return
+	}
+
+	// Start the stream immediately so clients know they're connected before any workflow is
+	// available. Errors from here on are sent as "error" events.
+	stream := &sseWorkflowStream{ctx: ctx, w: w, flusher: flusher}
+	if err := stream.write(": connected\n\n"); err != nil {
+		return
+	}
+
+	// Keep idle streams alive until GetWorkflows returns. The goroutine must exit before we
+	// return as the ResponseWriter is invalid afterward.
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		ticker := time.NewTicker(h.keepAliveInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				if err := stream.write(": keepalive\n\n"); err != nil {
+					return
+				}
+			}
+		}
+	}()
+
+	err = h.srv.GetWorkflows(req, stream)
+	close(done)
+	wg.Wait()
+
+	if err != nil {
+		h.logger.Info("Workflow stream closed with error", "agentID", req.GetAgentId(), "error", err)
+		stream.writeError(err)
+	}
+}
+
+func (h *WorkflowHTTPHandler) publishEvent(w http.ResponseWriter, r *http.Request) {
+	var req workflowproto.PublishEventRequest
+	h.serveUnary(w, r, &req, func(ctx context.Context) (googleproto.Message, error) {
+		return h.srv.PublishEvent(ctx, &req)
+	})
+}
+
+func (h *WorkflowHTTPHandler) heartbeat(w http.ResponseWriter, r *http.Request) {
+	var req workflowproto.HeartbeatRequest
+	h.serveUnary(w, r, &req, func(ctx context.Context) (googleproto.Message, error) {
+		return h.srv.Heartbeat(ctx, &req)
+	})
+}
+
+// serveUnary decodes the body of r into req and writes the response of call. It authenticates
+// and authorizes the request the same way as gRPC unary calls.
+func (h *WorkflowHTTPHandler) serveUnary(
+	w http.ResponseWriter,
+	r *http.Request,
+	req googleproto.Message,
+	call func(context.Context) (googleproto.Message, error),
+) {
+	ctx, err := h.authenticate(r)
+	if err != nil {
+		writeHTTPError(w, err)
+		return
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxHTTPRequestSize))
+	if err != nil {
+		writeHTTPError(w, status.Errorf(codes.InvalidArgument, "read body: %v", err))
+		return
+	}
+
+	if err := protojson.Unmarshal(body, req); err != nil {
+		writeHTTPError(w, status.Errorf(codes.InvalidArgument, "invalid request: %v", err))
+		return
+	}
+	if err := grpcserver.AuthorizeRequest(ctx, req); err != nil {
+		writeHTTPError(w, err)
+		return
+	}
+
+	resp, err := call(ctx)
+	if err != nil {
+		writeHTTPError(w, err)
+		return
+	}
+
+	data, err := protojson.Marshal(resp)
+	if err != nil {
+		writeHTTPError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(data)
+}
+
+// sseWorkflowStream satisfies workflowproto.WorkflowService_GetWorkflowsServer by writing
+// responses as Server-Sent Events.
+type sseWorkflowStream struct {
+	grpc.ServerStream
+
+	ctx     context.Context
+	w       http.ResponseWriter
+	flusher http.Flusher
+
+	mtx     sync.Mutex
+	started bool
+}
+
+func (s *sseWorkflowStream) Context() context.Context { return s.ctx }
+
+func (s *sseWorkflowStream) Send(resp *workflowproto.GetWorkflowsResponse) error {
+	data, err := protojson.Marshal(resp)
+	if err != nil {
+		return err
+	}
+	return s.write(fmt.Sprintf("data: %s\n\n", data))
+}
+
+func (s *sseWorkflowStream) SendMsg(m any) error {
+	resp, ok := m.(*workflowproto.GetWorkflowsResponse)
+	if !ok {
+		return status.Errorf(codes.Internal, "unexpected message type %T", m)
+	}
+	return s.Send(resp)
+}
+
+// write writes a raw Server-Sent Events message and flushes it to the client.
+func (s *sseWorkflowStream) write(msg string) error {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	if !s.started {
+		s.w.Header().Set("Content-Type", "text/event-stream")
+		s.w.Header().Set("Cache-Control", "no-cache")
+
+		// Prevent proxies, such as NGINX, buffering the stream.
+		s.w.Header().Set("X-Accel-Buffering", "no")
+		s.w.WriteHeader(http.StatusOK)
+		s.started = true
+	}
+
+	if _, err := io.WriteString(s.w, msg); err != nil {
+		return err
+	}
+	s.flusher.Flush()
+	return nil
+}
+
+// writeError reports err to the client as an "error" event. The status code can't change once
+// the stream has started.
+func (s *sseWorkflowStream) writeError(err error) {
+	data, _ := json.Marshal(toHTTPError(err))
+	_ = s.write(fmt.Sprintf("event: error\ndata: %s\n\n", data))
+}
+
+// HTTPError is the body of HTTP workflow API error responses.
+type HTTPError struct {
+	// Code is the gRPC status code name, such as NotFound.
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+func toHTTPError(err error) HTTPError {
+	st := status.Convert(err)
+	return HTTPError{Code: st.Code().String(), Message: st.Message()}
+}
+
+func writeHTTPError(w http.ResponseWriter, err error) {
+	data, _ := json.Marshal(toHTTPError(err))
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(httpStatusFromCode(status.Code(err)))
+	_, _ = w.Write(data)
+}
+
+// httpStatusFromCode maps gRPC status codes to HTTP status codes.
+func httpStatusFromCode(code codes.Code) int {
+	switch code {
+	case codes.OK:
+		return http.StatusOK
+	case codes.InvalidArgument, codes.OutOfRange:
+		return http.StatusBadRequest
+	case codes.Unauthenticated:
+		return http.StatusUnauthorized
+	case codes.PermissionDenied:
+		return http.StatusForbidden
+	case codes.NotFound:
+		return http.StatusNotFound
+	case codes.AlreadyExists, codes.Aborted, codes.FailedPrecondition:
+		return http.StatusConflict
+	case codes.ResourceExhausted:
+		return http.StatusTooManyRequests
+	case codes.Canceled:
+		return 499 // Client closed request.
+	case codes.Unimplemented:
+		return http.StatusNotImplemented
+	case codes.Unavailable:
+		return http.StatusServiceUnavailable
+	case codes.DeadlineExceeded:
+		return http.StatusGatewayTimeout
+	default:
+		return http.StatusInternalServerError
+	}
+}
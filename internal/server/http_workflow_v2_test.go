@@ -0,0 +1,218 @@
+package server
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/google/go-cmp/cmp"
+	"github.com/tinkerbell/tink/internal/grpcserver"
+	workflowproto "github.com/tinkerbell/tink/internal/proto/workflow/v2"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/testing/protocmp"
+)
+
+// fakeWorkflowServer is a WorkflowServiceServer with configurable behavior.
+type fakeWorkflowServer struct {
+	workflowproto.UnimplementedWorkflowServiceServer
+
+	getWorkflows func(*workflowproto.GetWorkflowsRequest, workflowproto.WorkflowService_GetWorkflowsServer) error
+	publishEvent func(context.Context, *workflowproto.PublishEventRequest) (*workflowproto.PublishEventResponse, error)
+}
+
+func (f *fakeWorkflowServer) GetWorkflows(req *workflowproto.GetWorkflowsRequest, stream workflowproto.WorkflowService_GetWorkflowsServer) error {
+	return f.getWorkflows(req, stream)
+}
+
+func (f *fakeWorkflowServer) PublishEvent(ctx context.Context, req *workflowproto.PublishEventRequest) (*workflowproto.PublishEventResponse, error) {
+	return f.publishEvent(ctx, req)
+}
+
+func TestWorkflowHTTPHandler_GetWorkflows(t *testing.T) {
+	expect := &workflowproto.GetWorkflowsResponse{
+		Cmd: &workflowproto.GetWorkflowsResponse_StopWorkflow_{
+			StopWorkflow: &workflowproto.GetWorkflowsResponse_StopWorkflow{WorkflowId: "ns/wf"},
+		},
+	}
+
+	var (
+		agentID string
+		running []string
+	)
+	srv := &fakeWorkflowServer{
+		getWorkflows: func(req *workflowproto.GetWorkflowsRequest, stream workflowproto.WorkflowService_GetWorkflowsServer) error {
+			agentID = req.GetAgentId()
+			running = req.GetRunningWorkflowIds()
+			if err := stream.Send(expect); err != nil {
+				return err
+			}
+			return status.Error(codes.Internal, "boom")
+		},
+	}
+
+	ts := httptest.NewServer(NewWorkflowHTTPHandler(logr.Discard(), srv))
+	defer ts.Close()
+
+	resp, err := http.Get(ts.URL + "/v2/agents/00:00:00:00:00:01/workflows?running=ns%2Fa&running=ns%2Fb")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("Expected status 200; Received %v", resp.StatusCode)
+	}
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Fatalf("Expected event stream; Received content type %q", ct)
+	}
+	if agentID != "00:00:00:00:00:01" {
+		t.Fatalf("Unexpected agent ID: %q", agentID)
+	}
+	if diff := cmp.Diff([]string{"ns/a", "ns/b"}, running); diff != "" {
+		t.Fatalf("Unexpected running workflows: %v", diff)
+	}
+
+	var events, data []string
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		switch line := scanner.Text(); {
+		case strings.HasPrefix(line, "event: "):
+			events = append(events, strings.TrimPrefix(line, "event: "))
+		case strings.HasPrefix(line, "data: "):
+			data = append(data, strings.TrimPrefix(line, "data: "))
+		}
+	}
+
+	if len(data) != 2 {
+		t.Fatalf("Expected 2 messages; Received %v", data)
+	}
+
+	var received workflowproto.GetWorkflowsResponse
+	if err := protojson.Unmarshal([]byte(data[0]), &received); err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(expect, &received, protocmp.Transform()); diff != "" {
+		t.Fatal(diff)
+	}
+
+	if diff := cmp.Diff([]string{"error"}, events); diff != "" {
+		t.Fatal(diff)
+	}
+	if !strings.Contains(data[1], `"code":"Internal"`) {
+		t.Fatalf("Unexpected error event: %v", data[1])
+	}
+}
+
+func TestWorkflowHTTPHandler_PublishEvent(t *testing.T) {
+	var received *workflowproto.PublishEventRequest
+	srv := &fakeWorkflowServer{
+		publishEvent: func(_ context.Context, req *workflowproto.PublishEventRequest) (*workflowproto.PublishEventResponse, error) {
+			if req.GetEvent().GetWorkflowId() == "missing" {
+				return nil, status.Error(codes.NotFound, "workflow not found")
+			}
+			received = req
+			return &workflowproto.PublishEventResponse{}, nil
+		},
+	}
+
+	ts := httptest.NewServer(NewWorkflowHTTPHandler(logr.Discard(), srv))
+	defer ts.Close()
+
+	cases := []struct {
+		Name         string
+		Body         string
+		ExpectStatus int
+	}{
+		{
+			Name:         "Success",
+			Body:         `{"event": {"workflowId": "ns/wf", "workflowRejected": {"message": "nope"}}}`,
+			ExpectStatus: http.StatusOK,
+		},
+		{
+			Name:         "InvalidJSON",
+			Body:         `{`,
+			ExpectStatus: http.StatusBadRequest,
+		},
+		{
+			Name:         "NotFound",
+			Body:         `{"event": {"workflowId": "missing"}}`,
+			ExpectStatus: http.StatusNotFound,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.Name, func(t *testing.T) {
+			resp, err := http.Post(ts.URL+"/v2/events", "application/json", strings.NewReader(tc.Body))
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp.Body.Close()
+
+			if resp.StatusCode != tc.ExpectStatus {
+				t.Fatalf("Expected status %v; Received %v", tc.ExpectStatus, resp.StatusCode)
+			}
+		})
+	}
+
+	expect := &workflowproto.PublishEventRequest{
+		Event: &workflowproto.Event{
+			WorkflowId: "ns/wf",
+			Event: &workflowproto.Event_WorkflowRejected_{
+				WorkflowRejected: &workflowproto.Event_WorkflowRejected{Message: "nope"},
+			},
+		},
+	}
+	if diff := cmp.Diff(expect, received, protocmp.Transform()); diff != "" {
+		t.Fatal(diff)
+	}
+}
+
+func TestWorkflowHTTPHandler_Authentication(t *testing.T) {
+	srv := &fakeWorkflowServer{
+		getWorkflows: func(*workflowproto.GetWorkflowsRequest, workflowproto.WorkflowService_GetWorkflowsServer) error {
+			return nil
+		},
+	}
+	authenticator := grpcserver.TokenAuthenticator{Tokens: map[string]string{"secret": "agent"}}
+
+	ts := httptest.NewServer(NewWorkflowHTTPHandler(logr.Discard(), srv, authenticator))
+	defer ts.Close()
+
+	cases := []struct {
+		Name         string
+		AgentID      string
+		Token        string
+		ExpectStatus int
+	}{
+		{Name: "MissingToken", AgentID: "agent", ExpectStatus: http.StatusUnauthorized},
+		{Name: "InvalidToken", AgentID: "agent", Token: "wrong", ExpectStatus: http.StatusUnauthorized},
+		{Name: "OtherAgent", AgentID: "other", Token: "secret", ExpectStatus: http.StatusForbidden},
+		{Name: "Authorized", AgentID: "agent", Token: "secret", ExpectStatus: http.StatusOK},
+	}
+	for _, tc := range cases {
+		t.Run(tc.Name, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodGet, ts.URL+"/v2/agents/"+tc.AgentID+"/workflows", nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tc.Token != "" {
+				req.Header.Set("Authorization", "Bearer "+tc.Token)
+			}
+
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp.Body.Close()
+
+			if resp.StatusCode != tc.ExpectStatus {
+				t.Fatalf("Expected status %v; Received %v", tc.ExpectStatus, resp.StatusCode)
+			}
+		})
+	}
+}
@@ -1,7 +1,11 @@
 package server
 
 import (
+	"slices"
+	"strings"
+
 	"github.com/tinkerbell/tink/api/v1alpha1"
+	"github.com/tinkerbell/tink/api/v1alpha2"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -28,3 +32,47 @@ func workflowByNonTerminalStateFunc(obj client.Object) []string {
 
 	return resp
 }
+
+// hardwareByMAC is the index name for retrieving v1alpha2 Hardware by the lowercase MAC address of
+// its network interfaces.
+const hardwareByMAC = ".spec.networkInterfaces.mac"
+
+// hardwareByMACFunc returns the lowercase MAC addresses of obj, which must be a v1alpha2
+// Hardware.
+func hardwareByMACFunc(obj client.Object) []string {
+	hw, ok := obj.(*v1alpha2.Hardware)
+	if !ok {
+		return nil
+	}
+
+	var macs []string
+	for _, mac := range hw.GetMACs() {
+		macs = append(macs, strings.ToLower(mac))
+	}
+	return macs
+}
+
+// workflowByNonTerminalHardware is the index name for retrieving v1alpha2 Workflows in a
+// non-terminal state by their Hardware.
+const workflowByNonTerminalHardware = ".spec.hardwareRef.nonTerminal"
+
+// workflowByNonTerminalHardwareFunc returns the namespaced name of the Hardware referenced by obj,
+// which must be a v1alpha2 Workflow, if it isn't in a terminal state.
+func workflowByNonTerminalHardwareFunc(obj client.Object) []string {
+	wflw, ok := obj.(*v1alpha2.Workflow)
+	if !ok || wflw.Status.State.IsTerminal() {
+		return nil
+	}
+	return []string{hardwareKey(wflw.Namespace, wflw.Spec.HardwareRef.Name)}
+}
+
+// hardwareKey returns the workflowByNonTerminalHardware index value for the Hardware identified by
+// namespace and name.
+func hardwareKey(namespace, name string) string {
+	return client.ObjectKey{Namespace: namespace, Name: name}.String()
+}
+
+// hasMAC determines if macs contains the MAC address identified by id, ignoring case.
+func hasMAC(macs []string, id string) bool {
+	return slices.ContainsFunc(macs, func(mac string) bool { return strings.EqualFold(mac, id) })
+}
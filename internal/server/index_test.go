@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/tinkerbell/tink/api/v1alpha1"
+	"github.com/tinkerbell/tink/api/v1alpha2"
 
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
@@ -86,3 +87,42 @@ func TestWorkflowIndexFuncs(t *testing.T) {
 		})
 	}
 }
+
+func TestHardwareByMACFunc(t *testing.T) {
+	hw := &v1alpha2.Hardware{
+		Spec: v1alpha2.HardwareSpec{
+			NetworkInterfaces: v1alpha2.NetworkInterfaces{
+				"AA:BB:CC:DD:EE:FF": v1alpha2.NetworkInterface{},
+			},
+		},
+	}
+
+	if got := hardwareByMACFunc(hw); !reflect.DeepEqual(got, []string{"aa:bb:cc:dd:ee:ff"}) {
+		t.Errorf("Unexpected MACs: %#v", got)
+	}
+	if got := hardwareByMACFunc(&v1alpha2.Workflow{}); got != nil {
+		t.Errorf("Unexpected MACs for non hardware: %#v", got)
+	}
+}
+
+func TestWorkflowByNonTerminalHardwareFunc(t *testing.T) {
+	cases := []struct {
+		name  string
+		input client.Object
+		want  []string
+	}{
+		{"non workflow", &v1alpha2.Hardware{}, nil},
+		{"pending workflow", newV2Workflow(v1alpha2.WorkflowStatePending), []string{"default/hardware"}},
+		{"running workflow", newV2Workflow(v1alpha2.WorkflowStateRunning), []string{"default/hardware"}},
+		{"succeeded workflow", newV2Workflow(v1alpha2.WorkflowStateSucceeded), nil},
+		{"canceled workflow", newV2Workflow(v1alpha2.WorkflowStateCanceled), nil},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := workflowByNonTerminalHardwareFunc(tc.input); !reflect.DeepEqual(tc.want, got) {
+				t.Errorf("Unexpected hardware: wanted %#v, got %#v", tc.want, got)
+			}
+		})
+	}
+}
@@ -8,11 +8,14 @@ import (
 	"github.com/go-logr/logr"
 	"github.com/go-logr/zapr"
 	"github.com/tinkerbell/tink/api/v1alpha1"
+	"github.com/tinkerbell/tink/api/v1alpha2"
 	"github.com/tinkerbell/tink/internal/deprecated/controller"
 	"github.com/tinkerbell/tink/internal/proto"
+	workflowproto "github.com/tinkerbell/tink/internal/proto/workflow/v2"
 	"go.uber.org/zap"
 	"google.golang.org/grpc"
 	"k8s.io/client-go/rest"
+	toolscache "k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/tools/clientcmd"
 	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
 	"sigs.k8s.io/controller-runtime/pkg/cache"
@@ -49,8 +52,13 @@ func NewKubeBackedServer(logger logr.Logger, kubeconfig, apiserver, namespace st
 // NewKubeBackedServerFromREST returns a server that implements the Workflow
 // server interface with the given Kubernetes rest client and namespace.
 func NewKubeBackedServerFromREST(logger logr.Logger, config *rest.Config, namespace string) (*KubernetesBackedServer, error) {
+	scheme := controller.DefaultScheme()
+	if err := v1alpha2.AddToScheme(scheme); err != nil {
+		return nil, fmt.Errorf("init scheme: %w", err)
+	}
+
 	clstr, err := cluster.New(config, func(opts *cluster.Options) {
-		opts.Scheme = controller.DefaultScheme()
+		opts.Scheme = scheme
 		opts.Logger = zapr.NewLogger(zap.NewNop())
 		if namespace != "" {
 			opts.Cache.DefaultNamespaces = map[string]cache.Config{
@@ -72,6 +80,43 @@ func NewKubeBackedServerFromREST(logger logr.Logger, config *rest.Config, namesp
 		return nil, fmt.Errorf("setup %s index: %w", workflowByNonTerminalState, err)
 	}
 
+	err = clstr.GetFieldIndexer().IndexField(
+		context.Background(),
+		&v1alpha2.Hardware{},
+		hardwareByMAC,
+		hardwareByMACFunc,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("setup %s index: %w", hardwareByMAC, err)
+	}
+
+	err = clstr.GetFieldIndexer().IndexField(
+		context.Background(),
+		&v1alpha2.Workflow{},
+		workflowByNonTerminalHardware,
+		workflowByNonTerminalHardwareFunc,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("setup %s index: %w", workflowByNonTerminalHardware, err)
+	}
+
+	informer, err := clstr.GetCache().GetInformer(context.Background(), &v1alpha1.Workflow{})
+	if err != nil {
+		return nil, fmt.Errorf("get workflow informer: %w", err)
+	}
+	notifier := newWorkflowNotifier()
+	_, err = informer.AddEventHandler(toolscache.ResourceEventHandlerFuncs{
+		AddFunc: notifier.notify,
+		UpdateFunc: func(oldObj, newObj any) {
+			notifier.notify(oldObj)
+			notifier.notify(newObj)
+		},
+		DeleteFunc: notifier.notify,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("watch workflows: %w", err)
+	}
+
 	go func() {
 		err := clstr.Start(context.Background())
 		if err != nil {
@@ -83,6 +128,7 @@ func NewKubeBackedServerFromREST(logger logr.Logger, config *rest.Config, namesp
 		logger:     logger,
 		ClientFunc: clstr.GetClient,
 		nowFunc:    time.Now,
+		notifier:   notifier,
 	}, nil
 }
 
@@ -92,9 +138,29 @@ type KubernetesBackedServer struct {
 	ClientFunc func() client.Client
 
 	nowFunc func() time.Time
+
+	// ActionLogs persists action logs uploaded by workers. When nil, uploads are rejected.
+	ActionLogs ActionLogStore
+
+	// notifier notifies watching workers of workflow changes. When nil, GetWorkflowContexts
+	// requests to watch are served as a single poll.
+	notifier *workflowNotifier
+
+	// workflowPollInterval is the interval at which v2 GetWorkflows streams check for workflow
+	// changes. Defaults to defaultWorkflowPollInterval.
+	workflowPollInterval time.Duration
+
+	// dispatchTimeout is the duration after which a Scheduled workflow the agent hasn't started is
+	// dispatched again. Defaults to defaultDispatchTimeout.
+	dispatchTimeout time.Duration
+
+	// rejectionBackoff is the duration after which a workflow rejected by its agent is dispatched
+	// again. Defaults to defaultRejectionBackoff.
+	rejectionBackoff time.Duration
 }
 
-// Register registers the service on the gRPC server.
+// Register registers the v1 and v2 workflow services on the gRPC server.
 func (s *KubernetesBackedServer) Register(server *grpc.Server) {
 	proto.RegisterWorkflowServiceServer(server, s)
+	workflowproto.RegisterWorkflowServiceServer(server, s)
 }
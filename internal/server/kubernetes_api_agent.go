@@ -0,0 +1,90 @@
+package server
+
+import (
+	"context"
+	"math"
+	"strings"
+
+	"github.com/tinkerbell/tink/api/v1alpha2"
+	workflowproto "github.com/tinkerbell/tink/internal/proto/workflow/v2"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+const errHardwareNotFound = "no hardware found for agent"
+
+// +kubebuilder:rbac:groups=tinkerbell.org,resources=agents;agents/status,verbs=get;list;watch;create;update;patch
+
+// Heartbeat records the agent identified by req.AgentId as alive. Liveness is recorded in the
+// status of an Agent in the namespace of the Hardware whose MAC matches the agent ID. Agents are
+// owned by their Hardware so they're deleted with it.
+func (s *KubernetesBackedServer) Heartbeat(ctx context.Context, req *workflowproto.HeartbeatRequest) (*workflowproto.HeartbeatResponse, error) {
+	agentID := req.GetAgentId()
+	if agentID == "" {
+		return nil, status.Errorf(codes.InvalidArgument, errInvalidAgentID)
+	}
+
+	hw, err := s.getHardwareForAgent(ctx, agentID)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "get hardware: %v", err)
+	}
+	if hw == nil {
+		return nil, status.Errorf(codes.NotFound, errHardwareNotFound)
+	}
+
+	key := client.ObjectKey{Namespace: hw.Namespace, Name: v1alpha2.AgentName(agentID)}
+	retriable := func(err error) bool {
+		return errors.IsConflict(err) || errors.IsAlreadyExists(err)
+	}
+	err = retry.OnError(retry.DefaultRetry, retriable, func() error {
+		var agent v1alpha2.Agent
+		err := s.ClientFunc().Get(ctx, key, &agent)
+		switch {
+		case errors.IsNotFound(err):
+			agent = v1alpha2.Agent{ObjectMeta: metav1.ObjectMeta{Namespace: key.Namespace, Name: key.Name}}
+			if err := controllerutil.SetOwnerReference(hw, &agent, s.ClientFunc().Scheme()); err != nil {
+				return err
+			}
+			if err := s.ClientFunc().Create(ctx, &agent); err != nil {
+				return err
+			}
+		case err != nil:
+			return err
+		}
+
+		agent.Status = v1alpha2.AgentStatus{
+			AgentID:          agentID,
+			Version:          req.GetVersion(),
+			Runtime:          req.GetRuntime(),
+			FreeDiskBytes:    int64(min(req.GetFreeDiskBytes(), math.MaxInt64)),
+			RunningWorkflows: req.GetRunningWorkflowIds(),
+			LastSeen:         metav1.NewTime(s.nowFunc()),
+		}
+		return s.ClientFunc().Status().Update(ctx, &agent)
+	})
+	if err != nil {
+		s.logger.Error(err, "record agent heartbeat", "agentID", agentID)
+		return nil, status.Errorf(codes.Internal, "record heartbeat: %v", err)
+	}
+
+	return &workflowproto.HeartbeatResponse{}, nil
+}
+
+// getHardwareForAgent retrieves the Hardware with a MAC matching agentID. It returns nil if no
+// such Hardware exists.
+func (s *KubernetesBackedServer) getHardwareForAgent(ctx context.Context, agentID string) (*v1alpha2.Hardware, error) {
+	var hardware v1alpha2.HardwareList
+	if err := s.ClientFunc().List(ctx, &hardware, client.MatchingFields{hardwareByMAC: strings.ToLower(agentID)}); err != nil {
+		return nil, err
+	}
+
+	if len(hardware.Items) == 0 {
+		return nil, nil
+	}
+	return &hardware.Items[0], nil
+}
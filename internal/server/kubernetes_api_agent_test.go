@@ -0,0 +1,76 @@
+package server
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/tinkerbell/tink/api/v1alpha2"
+	workflowproto "github.com/tinkerbell/tink/internal/proto/workflow/v2"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestHeartbeat(t *testing.T) {
+	server := newV2TestServer(t, newV2Hardware())
+
+	req := &workflowproto.HeartbeatRequest{
+		AgentId:            "00:00:00:00:00:01",
+		Version:            "v1.0.0",
+		Runtime:            "docker",
+		FreeDiskBytes:      1024,
+		RunningWorkflowIds: []string{"default/workflow"},
+	}
+
+	// Send twice to exercise both creating and updating the Agent.
+	for range 2 {
+		if _, err := server.Heartbeat(context.Background(), req); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var agent v1alpha2.Agent
+	key := client.ObjectKey{Namespace: "default", Name: "00-00-00-00-00-01"}
+	if err := server.ClientFunc().Get(context.Background(), key, &agent); err != nil {
+		t.Fatal(err)
+	}
+
+	expect := v1alpha2.AgentStatus{
+		AgentID:          "00:00:00:00:00:01",
+		Version:          "v1.0.0",
+		Runtime:          "docker",
+		FreeDiskBytes:    1024,
+		RunningWorkflows: []string{"default/workflow"},
+		LastSeen:         metav1.NewTime(TestTime.Now()),
+	}
+	if diff := cmp.Diff(expect, agent.Status); diff != "" {
+		t.Fatal(diff)
+	}
+
+	if refs := agent.GetOwnerReferences(); len(refs) != 1 || refs[0].Kind != "Hardware" || refs[0].Name != "hardware" {
+		t.Fatalf("Expected Agent to be owned by Hardware; received %+v", refs)
+	}
+}
+
+func TestHeartbeat_Errors(t *testing.T) {
+	server := newV2TestServer(t, newV2Hardware())
+
+	cases := []struct {
+		Name       string
+		AgentID    string
+		ExpectCode codes.Code
+	}{
+		{Name: "MissingAgentID", ExpectCode: codes.InvalidArgument},
+		{Name: "UnknownAgent", AgentID: "00:00:00:00:00:02", ExpectCode: codes.NotFound},
+	}
+	for _, tc := range cases {
+		t.Run(tc.Name, func(t *testing.T) {
+			_, err := server.Heartbeat(context.Background(), &workflowproto.HeartbeatRequest{AgentId: tc.AgentID})
+			if status.Code(err) != tc.ExpectCode {
+				t.Fatalf("Expected code %v; received %v", tc.ExpectCode, err)
+			}
+		})
+	}
+}
@@ -1,7 +1,9 @@
 package server
 
 import (
+	"context"
 	"errors"
+	"slices"
 	"testing"
 	"time"
 
@@ -11,6 +13,15 @@ import (
 	"github.com/tinkerbell/tink/internal/proto"
 	"github.com/tinkerbell/tink/internal/testtime"
 	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
 )
 
 var TestTime = testtime.NewFrozenTimeUnix(1637361793)
@@ -436,6 +447,95 @@ func TestModifyWorkflowState(t *testing.T) {
 	}
 }
 
+func TestRecordActionAttempt(t *testing.T) {
+	started := TestTime.MetaV1Before(10 * time.Second)
+	now := TestTime.MetaV1Now()
+
+	cases := []struct {
+		name     string
+		attempts []v1alpha1.ActionAttempt
+		req      *proto.WorkflowActionStatus
+		want     []v1alpha1.ActionAttempt
+	}{
+		{
+			name: "first attempt started",
+			req: &proto.WorkflowActionStatus{
+				ActionStatus: proto.State_STATE_RUNNING,
+				Attempt:      1,
+			},
+			want: []v1alpha1.ActionAttempt{
+				{Attempt: 1, Status: "STATE_RUNNING", StartedAt: now},
+			},
+		},
+		{
+			name: "retry fails previous attempt",
+			attempts: []v1alpha1.ActionAttempt{
+				{Attempt: 1, Status: "STATE_RUNNING", StartedAt: started},
+			},
+			req: &proto.WorkflowActionStatus{
+				ActionStatus: proto.State_STATE_RUNNING,
+				Attempt:      2,
+				Message:      "exit status 1",
+			},
+			want: []v1alpha1.ActionAttempt{
+				{Attempt: 1, Status: "STATE_FAILED", StartedAt: started, Seconds: 10, Message: "exit status 1"},
+				{Attempt: 2, Status: "STATE_RUNNING", StartedAt: now},
+			},
+		},
+		{
+			name: "attempt succeeded",
+			attempts: []v1alpha1.ActionAttempt{
+				{Attempt: 1, Status: "STATE_RUNNING", StartedAt: started},
+			},
+			req: &proto.WorkflowActionStatus{
+				ActionStatus: proto.State_STATE_SUCCESS,
+				Message:      "finished execution successfully",
+			},
+			want: []v1alpha1.ActionAttempt{
+				{Attempt: 1, Status: "STATE_SUCCESS", StartedAt: started, Seconds: 10, Message: "finished execution successfully"},
+			},
+		},
+		{
+			name: "attempt number inferred",
+			attempts: []v1alpha1.ActionAttempt{
+				{Attempt: 1, Status: "STATE_FAILED", StartedAt: started, Seconds: 10},
+			},
+			req: &proto.WorkflowActionStatus{
+				ActionStatus: proto.State_STATE_RUNNING,
+			},
+			want: []v1alpha1.ActionAttempt{
+				{Attempt: 1, Status: "STATE_FAILED", StartedAt: started, Seconds: 10},
+				{Attempt: 2, Status: "STATE_RUNNING", StartedAt: now},
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			wf := &v1alpha1.Workflow{
+				Status: v1alpha1.WorkflowStatus{
+					Tasks: []v1alpha1.Task{
+						{
+							Name: "provision",
+							Actions: []v1alpha1.Action{
+								{Name: "stream", Attempts: tc.attempts},
+							},
+						},
+					},
+				},
+			}
+			tc.req.TaskName = "provision"
+			tc.req.ActionName = "stream"
+
+			recordActionAttempt(wf, tc.req, TestTime.Now())
+
+			if diff := cmp.Diff(tc.want, wf.Status.Tasks[0].Actions[0].Attempts); diff != "" {
+				t.Errorf("unexpected difference:\n%v", diff)
+			}
+		})
+	}
+}
+
 // compareErrors is a helper function for comparing an error value and a desired error.
 func compareErrors(t *testing.T, got, want error) {
 	t.Helper()
@@ -451,3 +551,279 @@ func compareErrors(t *testing.T, got, want error) {
 		t.Fatalf("Missing expected error: %v", want)
 	}
 }
+
+// newTestWorkflow creates a pending v1alpha1 workflow with a single task assigned to "worker".
+func newTestWorkflow() *v1alpha1.Workflow {
+	return &v1alpha1.Workflow{
+		ObjectMeta: metav1.ObjectMeta{Name: "workflow", Namespace: "default"},
+		Status: v1alpha1.WorkflowStatus{
+			State: v1alpha1.WorkflowStatePending,
+			Tasks: []v1alpha1.Task{
+				{
+					Name:       "provision",
+					WorkerAddr: "worker",
+					Actions: []v1alpha1.Action{
+						{Name: "stream", Status: v1alpha1.WorkflowStatePending},
+						{Name: "kexec", Status: v1alpha1.WorkflowStatePending},
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestReportActionStatus(t *testing.T) {
+	conflict := k8serrors.NewConflict(schema.GroupResource{Group: "tinkerbell.org", Resource: "workflows"}, "workflow", errors.New("modified"))
+
+	cases := []struct {
+		name       string
+		req        *proto.WorkflowActionStatus
+		conflicts  int
+		wantCode   codes.Code
+		wantStatus v1alpha1.WorkflowState
+	}{
+		{
+			name: "Running",
+			req: &proto.WorkflowActionStatus{
+				WorkflowId:   "default/workflow",
+				TaskName:     "provision",
+				ActionName:   "stream",
+				ActionStatus: proto.State_STATE_RUNNING,
+				WorkerId:     "worker",
+			},
+			wantStatus: v1alpha1.WorkflowStateRunning,
+		},
+		{
+			name: "RetriedConflict",
+			req: &proto.WorkflowActionStatus{
+				WorkflowId:   "default/workflow",
+				TaskName:     "provision",
+				ActionName:   "stream",
+				ActionStatus: proto.State_STATE_RUNNING,
+				WorkerId:     "worker",
+			},
+			conflicts:  2,
+			wantStatus: v1alpha1.WorkflowStateRunning,
+		},
+		{
+			name: "PersistentConflict",
+			req: &proto.WorkflowActionStatus{
+				WorkflowId:   "default/workflow",
+				TaskName:     "provision",
+				ActionName:   "stream",
+				ActionStatus: proto.State_STATE_RUNNING,
+				WorkerId:     "worker",
+			},
+			conflicts:  100,
+			wantCode:   codes.Aborted,
+			wantStatus: v1alpha1.WorkflowStatePending,
+		},
+		{
+			name: "NotFound",
+			req: &proto.WorkflowActionStatus{
+				WorkflowId:   "default/missing",
+				TaskName:     "provision",
+				ActionName:   "stream",
+				ActionStatus: proto.State_STATE_RUNNING,
+				WorkerId:     "worker",
+			},
+			wantCode:   codes.NotFound,
+			wantStatus: v1alpha1.WorkflowStatePending,
+		},
+		{
+			name: "WrongAction",
+			req: &proto.WorkflowActionStatus{
+				WorkflowId:   "default/workflow",
+				TaskName:     "provision",
+				ActionName:   "kexec",
+				ActionStatus: proto.State_STATE_RUNNING,
+				WorkerId:     "worker",
+			},
+			wantCode:   codes.FailedPrecondition,
+			wantStatus: v1alpha1.WorkflowStatePending,
+		},
+		{
+			name: "MissingTask",
+			req: &proto.WorkflowActionStatus{
+				WorkflowId:   "default/workflow",
+				ActionName:   "stream",
+				ActionStatus: proto.State_STATE_RUNNING,
+				WorkerId:     "worker",
+			},
+			wantCode:   codes.InvalidArgument,
+			wantStatus: v1alpha1.WorkflowStatePending,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			scheme := runtime.NewScheme()
+			if err := v1alpha1.AddToScheme(scheme); err != nil {
+				t.Fatal(err)
+			}
+
+			conflicts := tc.conflicts
+			clnt := fake.NewClientBuilder().
+				WithScheme(scheme).
+				WithObjects(newTestWorkflow()).
+				WithStatusSubresource(&v1alpha1.Workflow{}).
+				WithInterceptorFuncs(interceptor.Funcs{
+					SubResourceUpdate: func(ctx context.Context, c client.Client, subResource string, obj client.Object, opts ...client.SubResourceUpdateOption) error {
+						if conflicts > 0 {
+							conflicts--
+							return conflict
+						}
+						return c.SubResource(subResource).Update(ctx, obj, opts...)
+					},
+				}).
+				Build()
+
+			s := &KubernetesBackedServer{
+				logger:     zapr.NewLogger(zap.NewNop()),
+				ClientFunc: func() client.Client { return clnt },
+				nowFunc:    TestTime.Now,
+			}
+
+			_, err := s.ReportActionStatus(context.Background(), tc.req)
+			if code := status.Code(err); code != tc.wantCode {
+				t.Fatalf("expected code %v, got %v: %v", tc.wantCode, code, err)
+			}
+
+			var wf v1alpha1.Workflow
+			if err := clnt.Get(context.Background(), client.ObjectKey{Namespace: "default", Name: "workflow"}, &wf); err != nil {
+				t.Fatal(err)
+			}
+			if wf.Status.State != tc.wantStatus {
+				t.Fatalf("expected workflow state %v, got %v", tc.wantStatus, wf.Status.State)
+			}
+		})
+	}
+}
+
+func TestReportActionStatus_Group(t *testing.T) {
+	type report struct {
+		action     string
+		state      proto.State
+		wantCode   codes.Code
+		wantStatus v1alpha1.WorkflowState
+	}
+
+	groupFirst := []v1alpha1.Action{
+		{Name: "wipe-0", Group: "wipe", Status: v1alpha1.WorkflowStatePending},
+		{Name: "wipe-1", Group: "wipe", Status: v1alpha1.WorkflowStatePending},
+		{Name: "kexec", Status: v1alpha1.WorkflowStatePending},
+	}
+	groupLast := []v1alpha1.Action{
+		{Name: "kexec", Status: v1alpha1.WorkflowStatePending},
+		{Name: "wipe-0", Group: "wipe", Status: v1alpha1.WorkflowStatePending},
+		{Name: "wipe-1", Group: "wipe", Status: v1alpha1.WorkflowStatePending},
+	}
+
+	cases := []struct {
+		name    string
+		actions []v1alpha1.Action
+		reports []report
+	}{
+		{
+			name:    "Succeeded",
+			actions: groupFirst,
+			reports: []report{
+				{action: "wipe-0", state: proto.State_STATE_RUNNING, wantStatus: v1alpha1.WorkflowStateRunning},
+				{action: "wipe-1", state: proto.State_STATE_RUNNING, wantStatus: v1alpha1.WorkflowStateRunning},
+				{action: "kexec", state: proto.State_STATE_RUNNING, wantCode: codes.FailedPrecondition, wantStatus: v1alpha1.WorkflowStateRunning},
+				{action: "wipe-1", state: proto.State_STATE_SUCCESS, wantStatus: v1alpha1.WorkflowStateRunning},
+				{action: "wipe-0", state: proto.State_STATE_SUCCESS, wantStatus: v1alpha1.WorkflowStateRunning},
+				{action: "kexec", state: proto.State_STATE_RUNNING, wantStatus: v1alpha1.WorkflowStateRunning},
+				{action: "kexec", state: proto.State_STATE_SUCCESS, wantStatus: v1alpha1.WorkflowStatePost},
+			},
+		},
+		{
+			name:    "Failed",
+			actions: groupFirst,
+			reports: []report{
+				{action: "wipe-0", state: proto.State_STATE_RUNNING, wantStatus: v1alpha1.WorkflowStateRunning},
+				{action: "wipe-1", state: proto.State_STATE_RUNNING, wantStatus: v1alpha1.WorkflowStateRunning},
+				{action: "wipe-0", state: proto.State_STATE_FAILED, wantStatus: v1alpha1.WorkflowStateRunning},
+				{action: "wipe-1", state: proto.State_STATE_SUCCESS, wantStatus: v1alpha1.WorkflowStateFailed},
+			},
+		},
+		{
+			name:    "Skipped",
+			actions: groupFirst,
+			reports: []report{
+				{action: "wipe-1", state: proto.State_STATE_RUNNING, wantStatus: v1alpha1.WorkflowStateRunning},
+				{action: "wipe-0", state: proto.State_STATE_SKIPPED, wantStatus: v1alpha1.WorkflowStateRunning},
+				{action: "wipe-1", state: proto.State_STATE_SUCCESS, wantStatus: v1alpha1.WorkflowStateRunning},
+				{action: "kexec", state: proto.State_STATE_SKIPPED, wantStatus: v1alpha1.WorkflowStatePost},
+			},
+		},
+		{
+			name:    "LastGroupSucceeded",
+			actions: groupLast,
+			reports: []report{
+				{action: "kexec", state: proto.State_STATE_RUNNING, wantStatus: v1alpha1.WorkflowStateRunning},
+				{action: "kexec", state: proto.State_STATE_SUCCESS, wantStatus: v1alpha1.WorkflowStateRunning},
+				{action: "wipe-0", state: proto.State_STATE_RUNNING, wantStatus: v1alpha1.WorkflowStateRunning},
+				{action: "wipe-1", state: proto.State_STATE_RUNNING, wantStatus: v1alpha1.WorkflowStateRunning},
+				{action: "wipe-1", state: proto.State_STATE_SUCCESS, wantStatus: v1alpha1.WorkflowStateRunning},
+				{action: "wipe-0", state: proto.State_STATE_SUCCESS, wantStatus: v1alpha1.WorkflowStatePost},
+			},
+		},
+		{
+			name:    "LastGroupFailed",
+			actions: groupLast,
+			reports: []report{
+				{action: "kexec", state: proto.State_STATE_RUNNING, wantStatus: v1alpha1.WorkflowStateRunning},
+				{action: "kexec", state: proto.State_STATE_SUCCESS, wantStatus: v1alpha1.WorkflowStateRunning},
+				{action: "wipe-0", state: proto.State_STATE_RUNNING, wantStatus: v1alpha1.WorkflowStateRunning},
+				{action: "wipe-1", state: proto.State_STATE_RUNNING, wantStatus: v1alpha1.WorkflowStateRunning},
+				{action: "wipe-1", state: proto.State_STATE_SUCCESS, wantStatus: v1alpha1.WorkflowStateRunning},
+				{action: "wipe-0", state: proto.State_STATE_FAILED, wantStatus: v1alpha1.WorkflowStateFailed},
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			scheme := runtime.NewScheme()
+			if err := v1alpha1.AddToScheme(scheme); err != nil {
+				t.Fatal(err)
+			}
+
+			wf := newTestWorkflow()
+			wf.Status.Tasks[0].Actions = slices.Clone(tc.actions)
+			clnt := fake.NewClientBuilder().
+				WithScheme(scheme).
+				WithObjects(wf).
+				WithStatusSubresource(&v1alpha1.Workflow{}).
+				Build()
+
+			s := &KubernetesBackedServer{
+				logger:     zapr.NewLogger(zap.NewNop()),
+				ClientFunc: func() client.Client { return clnt },
+				nowFunc:    TestTime.Now,
+			}
+
+			for _, r := range tc.reports {
+				_, err := s.ReportActionStatus(context.Background(), &proto.WorkflowActionStatus{
+					WorkflowId:   "default/workflow",
+					TaskName:     "provision",
+					ActionName:   r.action,
+					ActionStatus: r.state,
+					WorkerId:     "worker",
+				})
+				if code := status.Code(err); code != r.wantCode {
+					t.Fatalf("%v %v: expected code %v, got %v: %v", r.action, r.state, r.wantCode, code, err)
+				}
+
+				var wf v1alpha1.Workflow
+				if err := clnt.Get(context.Background(), client.ObjectKey{Namespace: "default", Name: "workflow"}, &wf); err != nil {
+					t.Fatal(err)
+				}
+				if wf.Status.State != r.wantStatus {
+					t.Fatalf("%v %v: expected workflow state %v, got %v", r.action, r.state, r.wantStatus, wf.Status.State)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,99 @@
+package server
+
+import (
+	"context"
+	"math"
+	"slices"
+	"time"
+
+	"github.com/tinkerbell/tink/api/v1alpha1"
+	"github.com/tinkerbell/tink/internal/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+const errHardwareNotFoundForWorker = "no hardware found for worker"
+
+// +kubebuilder:rbac:groups=tinkerbell.org,resources=workers;workers/status,verbs=get;list;watch;create;update;patch
+
+// WorkerHeartbeat records the worker identified by req.WorkerId as alive. Liveness is recorded in
+// the status of a Worker in the namespace of the Hardware whose MAC matches the worker ID.
+// Workers are owned by their Hardware so they're deleted with it.
+func (s *KubernetesBackedServer) WorkerHeartbeat(ctx context.Context, req *proto.WorkerHeartbeatRequest) (*proto.Empty, error) {
+	workerID := req.GetWorkerId()
+	if workerID == "" {
+		return nil, status.Errorf(codes.InvalidArgument, errInvalidWorkerID)
+	}
+
+	hw, err := s.getHardwareForWorker(ctx, workerID)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "get hardware: %v", err)
+	}
+	if hw == nil {
+		return nil, status.Errorf(codes.NotFound, errHardwareNotFoundForWorker)
+	}
+
+	key := client.ObjectKey{Namespace: hw.Namespace, Name: v1alpha1.WorkerName(workerID)}
+	retriable := func(err error) bool {
+		return errors.IsConflict(err) || errors.IsAlreadyExists(err)
+	}
+	err = retry.OnError(retry.DefaultRetry, retriable, func() error {
+		var worker v1alpha1.Worker
+		err := s.ClientFunc().Get(ctx, key, &worker)
+		switch {
+		case errors.IsNotFound(err):
+			worker = v1alpha1.Worker{ObjectMeta: metav1.ObjectMeta{Namespace: key.Namespace, Name: key.Name}}
+			if err := controllerutil.SetOwnerReference(hw, &worker, s.ClientFunc().Scheme()); err != nil {
+				return err
+			}
+			if err := s.ClientFunc().Create(ctx, &worker); err != nil {
+				return err
+			}
+		case err != nil:
+			return err
+		}
+
+		worker.Status = workerStatus(req, s.nowFunc())
+		return s.ClientFunc().Status().Update(ctx, &worker)
+	})
+	if err != nil {
+		s.logger.Error(err, "record worker heartbeat", "workerID", workerID)
+		return nil, status.Errorf(codes.Internal, "record heartbeat: %v", err)
+	}
+
+	return &proto.Empty{}, nil
+}
+
+// getHardwareForWorker retrieves the Hardware with a MAC matching workerID. It returns nil if no
+// such Hardware exists.
+func (s *KubernetesBackedServer) getHardwareForWorker(ctx context.Context, workerID string) (*v1alpha1.Hardware, error) {
+	var hardware v1alpha1.HardwareList
+	if err := s.ClientFunc().List(ctx, &hardware); err != nil {
+		return nil, err
+	}
+
+	for i := range hardware.Items {
+		if slices.Contains(hardware.Items[i].GetMACs(), workerID) {
+			return &hardware.Items[i], nil
+		}
+	}
+
+	return nil, nil
+}
+
+// workerStatus converts a heartbeat received at now to a WorkerStatus.
+func workerStatus(req *proto.WorkerHeartbeatRequest, now time.Time) v1alpha1.WorkerStatus {
+	return v1alpha1.WorkerStatus{
+		WorkerID:         req.GetWorkerId(),
+		Version:          req.GetVersion(),
+		Runtime:          req.GetRuntime(),
+		FreeDiskBytes:    int64(min(req.GetFreeDiskBytes(), math.MaxInt64)),
+		RunningWorkflows: req.GetRunningWorkflowIds(),
+		LastSeen:         metav1.NewTime(now),
+	}
+}
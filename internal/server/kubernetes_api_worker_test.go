@@ -0,0 +1,106 @@
+package server
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/google/go-cmp/cmp"
+	"github.com/tinkerbell/tink/api/v1alpha1"
+	"github.com/tinkerbell/tink/internal/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func newWorkerTestServer(t *testing.T) *KubernetesBackedServer {
+	t.Helper()
+
+	scheme := runtime.NewScheme()
+	if err := v1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+
+	hw := &v1alpha1.Hardware{
+		ObjectMeta: metav1.ObjectMeta{Name: "hardware", Namespace: "default"},
+		Spec: v1alpha1.HardwareSpec{
+			Interfaces: []v1alpha1.Interface{{DHCP: &v1alpha1.DHCP{MAC: "00:00:00:00:00:01"}}},
+		},
+	}
+	clnt := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(hw).
+		WithStatusSubresource(&v1alpha1.Worker{}).
+		Build()
+
+	return &KubernetesBackedServer{
+		logger:     logr.Discard(),
+		ClientFunc: func() client.Client { return clnt },
+		nowFunc:    TestTime.Now,
+	}
+}
+
+func TestWorkerHeartbeat(t *testing.T) {
+	server := newWorkerTestServer(t)
+
+	req := &proto.WorkerHeartbeatRequest{
+		WorkerId:           "00:00:00:00:00:01",
+		Version:            "v1.0.0",
+		Runtime:            "docker",
+		FreeDiskBytes:      1024,
+		RunningWorkflowIds: []string{"default/workflow"},
+	}
+
+	// Send twice to exercise both creating and updating the Worker.
+	for range 2 {
+		if _, err := server.WorkerHeartbeat(context.Background(), req); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var worker v1alpha1.Worker
+	key := client.ObjectKey{Namespace: "default", Name: "00-00-00-00-00-01"}
+	if err := server.ClientFunc().Get(context.Background(), key, &worker); err != nil {
+		t.Fatal(err)
+	}
+
+	expect := v1alpha1.WorkerStatus{
+		WorkerID:         "00:00:00:00:00:01",
+		Version:          "v1.0.0",
+		Runtime:          "docker",
+		FreeDiskBytes:    1024,
+		RunningWorkflows: []string{"default/workflow"},
+		LastSeen:         metav1.NewTime(TestTime.Now()),
+	}
+	if diff := cmp.Diff(expect, worker.Status); diff != "" {
+		t.Fatal(diff)
+	}
+
+	if refs := worker.GetOwnerReferences(); len(refs) != 1 || refs[0].Kind != "Hardware" || refs[0].Name != "hardware" {
+		t.Fatalf("Expected Worker to be owned by Hardware; received %+v", refs)
+	}
+}
+
+func TestWorkerHeartbeat_Errors(t *testing.T) {
+	server := newWorkerTestServer(t)
+
+	cases := []struct {
+		Name       string
+		WorkerID   string
+		ExpectCode codes.Code
+	}{
+		{Name: "MissingWorkerID", ExpectCode: codes.InvalidArgument},
+		{Name: "UnknownWorker", WorkerID: "00:00:00:00:00:02", ExpectCode: codes.NotFound},
+	}
+	for _, tc := range cases {
+		t.Run(tc.Name, func(t *testing.T) {
+			_, err := server.WorkerHeartbeat(context.Background(), &proto.WorkerHeartbeatRequest{WorkerId: tc.WorkerID})
+			if status.Code(err) != tc.ExpectCode {
+				t.Fatalf("Expected code %v; received %v", tc.ExpectCode, err)
+			}
+		})
+	}
+}
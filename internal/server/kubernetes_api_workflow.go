@@ -3,15 +3,21 @@ package server
 import (
 	"context"
 	"strings"
+	"time"
 
+	"github.com/go-logr/logr"
 	"github.com/pkg/errors"
 	"github.com/tinkerbell/tink/api/v1alpha1"
 	"github.com/tinkerbell/tink/internal/deprecated/workflow"
+	"github.com/tinkerbell/tink/internal/grpcserver"
 	"github.com/tinkerbell/tink/internal/proto"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	googleproto "google.golang.org/protobuf/proto"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -21,6 +27,8 @@ const (
 	errInvalidActionName     = "invalid action name"
 	errInvalidTaskReported   = "reported task name does not match the current action details"
 	errInvalidActionReported = "reported action name does not match the current action details"
+	errWorkflowNotOwned      = "workflow is not assigned to the caller"
+	errInvalidWorkerID       = "invalid worker id"
 )
 
 func getWorkflowContext(wf v1alpha1.Workflow) *proto.WorkflowContext {
@@ -72,7 +80,31 @@ func (s *KubernetesBackedServer) GetWorkflowContexts(req *proto.WorkflowContextR
 	if req.GetWorkerId() == "" {
 		return status.Errorf(codes.InvalidArgument, errInvalidWorkflowID)
 	}
-	wflows, err := s.getCurrentAssignedNonTerminalWorkflowsForWorker(stream.Context(), req.WorkerId)
+	if !req.GetWatch() || s.notifier == nil {
+		return s.sendWorkflowContexts(stream, req.GetWorkerId(), nil)
+	}
+
+	// Subscribe before sending the current contexts so no change is missed.
+	changed, unsubscribe := s.notifier.subscribe(req.GetWorkerId())
+	defer unsubscribe()
+
+	sent := map[string]*proto.WorkflowContext{}
+	for {
+		if err := s.sendWorkflowContexts(stream, req.GetWorkerId(), sent); err != nil {
+			return err
+		}
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case <-changed:
+		}
+	}
+}
+
+// sendWorkflowContexts sends the contexts of workflows assigned to workerID. When sent is
+// non-nil, contexts identical to those previously sent are skipped and sent is updated.
+func (s *KubernetesBackedServer) sendWorkflowContexts(stream proto.WorkflowService_GetWorkflowContextsServer, workerID string, sent map[string]*proto.WorkflowContext) error {
+	wflows, err := s.getCurrentAssignedNonTerminalWorkflowsForWorker(stream.Context(), workerID)
 	if err != nil {
 		return err
 	}
@@ -82,7 +114,14 @@ func (s *KubernetesBackedServer) GetWorkflowContexts(req *proto.WorkflowContextR
 		if wf.Spec.BootOptions.BootMode != "" && wf.Status.State == v1alpha1.WorkflowStatePreparing {
 			continue
 		}
-		if err := stream.Send(getWorkflowContext(wf)); err != nil {
+		wfContext := getWorkflowContext(wf)
+		if sent != nil {
+			if prev, ok := sent[wfContext.GetWorkflowId()]; ok && googleproto.Equal(prev, wfContext) {
+				continue
+			}
+			sent[wfContext.GetWorkflowId()] = wfContext
+		}
+		if err := stream.Send(wfContext); err != nil {
 			return err
 		}
 	}
@@ -98,11 +137,20 @@ func (s *KubernetesBackedServer) GetWorkflowActions(ctx context.Context, req *pr
 	if err != nil {
 		return nil, err
 	}
+	if err := authorizeWorkflowWorker(ctx, wf); err != nil {
+		return nil, err
+	}
 	return workflow.ActionListCRDToProto(wf), nil
 }
 
 // Modifies a workflow for a given workflowContext.
 func (s *KubernetesBackedServer) modifyWorkflowState(wf *v1alpha1.Workflow, wfContext *proto.WorkflowContext) error {
+	return applyWorkflowContext(wf, wfContext, s.nowFunc)
+}
+
+// applyWorkflowContext modifies wf according to the action state reported in wfContext. It is
+// shared by all backends so workflow state transitions are identical regardless of storage.
+func applyWorkflowContext(wf *v1alpha1.Workflow, wfContext *proto.WorkflowContext, nowFunc func() time.Time) error {
 	if wf == nil {
 		return errors.New("no workflow provided")
 	}
@@ -143,22 +191,33 @@ cont:
 		// Workflow is running, so set the start time to now
 		wf.Status.State = v1alpha1.WorkflowState(proto.State_name[int32(wfContext.CurrentActionState)])
 		wf.Status.Tasks[taskIndex].Actions[actionIndex].StartedAt = func() *metav1.Time {
-			t := metav1.NewTime(s.nowFunc())
+			t := metav1.NewTime(nowFunc())
 			return &t
 		}()
 	case proto.State_STATE_FAILED, proto.State_STATE_TIMEOUT:
-		// Handle terminal statuses by updating the workflow state and time
-		wf.Status.State = v1alpha1.WorkflowState(proto.State_name[int32(wfContext.CurrentActionState)])
+		// Handle terminal statuses by updating the workflow state and time. Actions grouped with
+		// the failed action continue so the workflow fails once they finish.
+		if state, failed := groupFailure(wf.Status.Tasks[taskIndex].Actions, actionIndex); failed {
+			wf.Status.State = state
+		}
 		if wf.Status.Tasks[taskIndex].Actions[actionIndex].StartedAt != nil {
-			wf.Status.Tasks[taskIndex].Actions[actionIndex].Seconds = int64(s.nowFunc().Sub(wf.Status.Tasks[taskIndex].Actions[actionIndex].StartedAt.Time).Seconds())
+			wf.Status.Tasks[taskIndex].Actions[actionIndex].Seconds = int64(nowFunc().Sub(wf.Status.Tasks[taskIndex].Actions[actionIndex].StartedAt.Time).Seconds())
 		}
-	case proto.State_STATE_SUCCESS:
-		// Handle a success by marking the task as complete
+	case proto.State_STATE_SUCCESS, proto.State_STATE_SKIPPED:
+		// Handle a success, or an action skipped because of its condition, by marking the task as
+		// complete
 		if wf.Status.Tasks[taskIndex].Actions[actionIndex].StartedAt != nil {
-			wf.Status.Tasks[taskIndex].Actions[actionIndex].Seconds = int64(s.nowFunc().Sub(wf.Status.Tasks[taskIndex].Actions[actionIndex].StartedAt.Time).Seconds())
+			wf.Status.Tasks[taskIndex].Actions[actionIndex].Seconds = int64(nowFunc().Sub(wf.Status.Tasks[taskIndex].Actions[actionIndex].StartedAt.Time).Seconds())
+		}
+		if state, failed := groupFailure(wf.Status.Tasks[taskIndex].Actions, actionIndex); failed {
+			wf.Status.State = state
+			break
 		}
-		// Mark success on last action success
-		if wfContext.CurrentActionIndex+1 == wfContext.TotalNumberOfActions {
+		// Mark success on last action success. Grouped actions may finish in any order so the
+		// workflow only succeeds once every action in it has.
+		lastAction := wfContext.CurrentActionIndex+1 == wfContext.TotalNumberOfActions &&
+			len(v1alpha1.ActionGroup(wf.Status.Tasks[taskIndex].Actions, actionIndex)) == 1
+		if lastAction || allTaskActionsSucceeded(wf) {
 			// Set the state to POST instead of Success to allow any post tasks to run.
 			wf.Status.State = v1alpha1.WorkflowStatePost
 		}
@@ -169,6 +228,119 @@ cont:
 	return nil
 }
 
+// groupFailure determines if the group containing actions[i] has finished and any of its actions
+// failed. It returns the state of the first failed action in the group.
+func groupFailure(actions []v1alpha1.Action, i int) (v1alpha1.WorkflowState, bool) {
+	var state v1alpha1.WorkflowState
+	for _, j := range v1alpha1.ActionGroup(actions, i) {
+		switch actions[j].Status { //nolint:exhaustive // Remaining states aren't used by actions.
+		case v1alpha1.WorkflowStatePending, v1alpha1.WorkflowStateRunning:
+			return "", false
+		case v1alpha1.WorkflowStateFailed, v1alpha1.WorkflowStateTimeout:
+			if state == "" {
+				state = actions[j].Status
+			}
+		}
+	}
+	return state, state != ""
+}
+
+// allTaskActionsSucceeded determines if every action in every task of wf has succeeded or was
+// skipped.
+func allTaskActionsSucceeded(wf *v1alpha1.Workflow) bool {
+	for _, task := range wf.Status.Tasks {
+		for _, action := range task.Actions {
+			if action.Status != v1alpha1.WorkflowStateSuccess && action.Status != v1alpha1.WorkflowStateSkipped {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// recordActionAttempt records the attempt described by req against the action it references.
+// A running status starts a new attempt, failing any attempt still in progress with the message
+// provided in req. Terminal statuses complete the latest attempt.
+func recordActionAttempt(wf *v1alpha1.Workflow, req *proto.WorkflowActionStatus, now time.Time) {
+	action := findAction(wf, req.GetTaskName(), req.GetActionName())
+	if action == nil {
+		return
+	}
+
+	var last *v1alpha1.ActionAttempt
+	if n := len(action.Attempts); n > 0 {
+		last = &action.Attempts[n-1]
+	}
+
+	running := v1alpha1.WorkflowState(proto.State_name[int32(proto.State_STATE_RUNNING)])
+	finish := func(a *v1alpha1.ActionAttempt, state v1alpha1.WorkflowState, message string) {
+		a.Status = state
+		a.Message = message
+		if a.StartedAt != nil {
+			a.Seconds = int64(now.Sub(a.StartedAt.Time).Seconds())
+		}
+	}
+
+	switch req.GetActionStatus() {
+	case proto.State_STATE_RUNNING:
+		attempt := req.GetAttempt()
+		if attempt == 0 {
+			attempt = int64(len(action.Attempts)) + 1
+		}
+		if last != nil && last.Status == running {
+			finish(last, v1alpha1.WorkflowState(proto.State_name[int32(proto.State_STATE_FAILED)]), req.GetMessage())
+		}
+		startedAt := metav1.NewTime(now)
+		action.Attempts = append(action.Attempts, v1alpha1.ActionAttempt{
+			Attempt:   attempt,
+			Status:    running,
+			StartedAt: &startedAt,
+		})
+	case proto.State_STATE_SUCCESS, proto.State_STATE_FAILED, proto.State_STATE_TIMEOUT:
+		if last != nil && last.Status == running {
+			finish(last, v1alpha1.WorkflowState(proto.State_name[int32(req.GetActionStatus())]), req.GetMessage())
+		}
+	}
+}
+
+// findAction retrieves the action identified by taskName and actionName from wf's status.
+func findAction(wf *v1alpha1.Workflow, taskName, actionName string) *v1alpha1.Action {
+	for ti := range wf.Status.Tasks {
+		if wf.Status.Tasks[ti].Name != taskName {
+			continue
+		}
+		for ai := range wf.Status.Tasks[ti].Actions {
+			if wf.Status.Tasks[ti].Actions[ai].Name == actionName {
+				return &wf.Status.Tasks[ti].Actions[ai]
+			}
+		}
+	}
+	return nil
+}
+
+// authorizeWorker ensures the authenticated caller, if any, is workerID.
+func authorizeWorker(ctx context.Context, workerID string) error {
+	identity, ok := grpcserver.IdentityFromContext(ctx)
+	if !ok || identity == workerID {
+		return nil
+	}
+	return status.Errorf(codes.PermissionDenied, errWorkflowNotOwned)
+}
+
+// authorizeWorkflowWorker ensures the authenticated caller, if any, is assigned a task in wf.
+func authorizeWorkflowWorker(ctx context.Context, wf *v1alpha1.Workflow) error {
+	identity, ok := grpcserver.IdentityFromContext(ctx)
+	if !ok {
+		return nil
+	}
+	for _, task := range wf.Status.Tasks {
+		if task.WorkerAddr == identity {
+			return nil
+		}
+	}
+	return status.Errorf(codes.PermissionDenied, errWorkflowNotOwned)
+}
+
 func validateActionStatusRequest(req *proto.WorkflowActionStatus) error {
 	if req.GetWorkflowId() == "" {
 		return status.Errorf(codes.InvalidArgument, errInvalidWorkflowID)
@@ -188,9 +360,45 @@ func getWorkflowContextForRequest(req *proto.WorkflowActionStatus, wf *v1alpha1.
 	wfContext.CurrentTask = req.GetTaskName()
 	wfContext.CurrentActionState = req.GetActionStatus()
 	wfContext.CurrentActionIndex = int64(wf.GetCurrentActionIndex())
+
+	// Grouped actions report concurrently so the reported action may not be the current action.
+	if req.GetActionName() != wfContext.GetCurrentAction() {
+		wfContext.CurrentActionIndex += int64(groupOffset(wf, req.GetActionName()))
+		wfContext.CurrentAction = req.GetActionName()
+	}
 	return wfContext
 }
 
+// inCurrentGroup determines if actionName is grouped with the current action of wf.
+func inCurrentGroup(wf *v1alpha1.Workflow, actionName string) bool {
+	actions := wf.Status.Tasks[wf.GetCurrentTaskIndex()].Actions
+	for _, i := range wf.GetCurrentActionGroup() {
+		if actions[i].Name == actionName {
+			return true
+		}
+	}
+	return false
+}
+
+// groupOffset returns the position of actionName relative to the current action of wf. actionName
+// must be grouped with the current action.
+func groupOffset(wf *v1alpha1.Workflow, actionName string) int {
+	actions := wf.Status.Tasks[wf.GetCurrentTaskIndex()].Actions
+	var current, reported int
+	for i, action := range actions {
+		switch action.Name {
+		case wf.GetCurrentAction():
+			current = i
+		case actionName:
+			reported = i
+		}
+	}
+	return reported - current
+}
+
+// ReportActionStatus applies the reported action status to the workflow. Updates that conflict
+// with concurrent modifications, such as those made by the controller, are retried against the
+// latest workflow.
 func (s *KubernetesBackedServer) ReportActionStatus(ctx context.Context, req *proto.WorkflowActionStatus) (*proto.Empty, error) {
 	err := validateActionStatusRequest(req)
 	if err != nil {
@@ -199,29 +407,78 @@ func (s *KubernetesBackedServer) ReportActionStatus(ctx context.Context, req *pr
 	wfID := req.GetWorkflowId()
 	l := s.logger.WithValues("actionName", req.GetActionName(), "status", req.GetActionStatus(), "workflowID", req.GetWorkflowId(), "taskName", req.GetTaskName(), "worker", req.WorkerId)
 
-	wf, err := s.getWorkflowByName(ctx, wfID)
+	err = retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		wf, err := s.getWorkflowByName(ctx, wfID)
+		if err != nil {
+			if k8serrors.IsNotFound(err) {
+				return status.Errorf(codes.NotFound, "workflow %v not found", wfID)
+			}
+			return status.Errorf(codes.Internal, "get workflow: %v", err)
+		}
+
+		if err := applyActionStatus(ctx, l, wf, req, s.nowFunc); err != nil {
+			return err
+		}
+
+		l.Info("updating workflow in Kubernetes")
+		return s.ClientFunc().Status().Update(ctx, wf)
+	})
 	if err != nil {
-		l.Error(err, "get workflow")
-		return nil, status.Errorf(codes.InvalidArgument, errInvalidWorkflowID)
+		if _, ok := status.FromError(err); ok {
+			return nil, err
+		}
+		l.Error(err, "applying update to workflow")
+		if k8serrors.IsConflict(err) {
+			return nil, status.Errorf(codes.Aborted, "update workflow: %v", err)
+		}
+		return nil, status.Errorf(codes.Internal, "update workflow: %v", err)
+	}
+	return &proto.Empty{}, nil
+}
+
+// applyActionStatus validates req against the current state of wf and applies it. Errors are
+// gRPC statuses: requests that are inconsistent with the workflow's state are FailedPrecondition.
+func applyActionStatus(ctx context.Context, l logr.Logger, wf *v1alpha1.Workflow, req *proto.WorkflowActionStatus, nowFunc func() time.Time) error {
+	if err := authorizeWorker(ctx, wf.GetCurrentWorker()); err != nil {
+		return err
 	}
 	if req.GetTaskName() != wf.GetCurrentTask() {
-		return nil, status.Errorf(codes.InvalidArgument, errInvalidTaskReported)
+		return status.Errorf(codes.FailedPrecondition, errInvalidTaskReported)
 	}
-	if req.GetActionName() != wf.GetCurrentAction() {
-		return nil, status.Errorf(codes.InvalidArgument, errInvalidActionReported)
+	if req.GetActionName() != wf.GetCurrentAction() && !inCurrentGroup(wf, req.GetActionName()) {
+		return status.Errorf(codes.FailedPrecondition, errInvalidActionReported)
 	}
 
 	wfContext := getWorkflowContextForRequest(req, wf)
-	err = s.modifyWorkflowState(wf, wfContext)
-	if err != nil {
+	if err := applyWorkflowContext(wf, wfContext, nowFunc); err != nil {
 		l.Error(err, "modify workflow state")
-		return nil, status.Errorf(codes.InvalidArgument, errInvalidWorkflowID)
+		return status.Errorf(codes.FailedPrecondition, "modify workflow state: %v", err)
 	}
-	l.Info("updating workflow in Kubernetes")
-	err = s.ClientFunc().Status().Update(ctx, wf)
-	if err != nil {
-		l.Error(err, "applying update to workflow")
-		return nil, status.Errorf(codes.InvalidArgument, errInvalidWorkflowID)
+	recordActionAttempt(wf, req, nowFunc())
+	recordActionOutputs(wf, req)
+	return nil
+}
+
+// recordActionOutputs records the outputs published by a successful action.
+func recordActionOutputs(wf *v1alpha1.Workflow, req *proto.WorkflowActionStatus) {
+	if req.GetActionStatus() != proto.State_STATE_SUCCESS {
+		return
 	}
-	return &proto.Empty{}, nil
+	if action := findAction(wf, req.GetTaskName(), req.GetActionName()); action != nil {
+		action.Outputs = req.GetOutputs()
+	}
+}
+
+// UploadActionLogs persists action logs uploaded by workers to s.ActionLogs.
+func (s *KubernetesBackedServer) UploadActionLogs(stream proto.WorkflowService_UploadActionLogsServer) error {
+	return receiveActionLogs(s.ActionLogs, stream, func(ctx context.Context, workflowID string) (*v1alpha1.Workflow, error) {
+		wf, err := s.getWorkflowByName(ctx, workflowID)
+		switch {
+		case k8serrors.IsNotFound(err):
+			return nil, status.Errorf(codes.NotFound, "workflow %v not found", workflowID)
+		case err != nil:
+			return nil, status.Errorf(codes.Internal, "get workflow: %v", err)
+		}
+		return wf, nil
+	})
 }
@@ -0,0 +1,601 @@
+package server
+
+import (
+	"context"
+	serrors "errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/tinkerbell/tink/api/v1alpha2"
+	"github.com/tinkerbell/tink/internal/grpcserver"
+	"github.com/tinkerbell/tink/internal/proto"
+	workflowproto "github.com/tinkerbell/tink/internal/proto/workflow/v2"
+	"github.com/tinkerbell/tink/internal/ptr"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// defaultWorkflowPollInterval is the interval at which GetWorkflows streams check for
+	// workflows to dispatch to, or cancel on, an agent.
+	defaultWorkflowPollInterval = 5 * time.Second
+
+	// defaultDispatchTimeout is the duration after which a Scheduled workflow the agent hasn't
+	// started is dispatched again.
+	defaultDispatchTimeout = time.Minute
+
+	// defaultRejectionBackoff is the duration after which a workflow rejected by its agent is
+	// dispatched again.
+	defaultRejectionBackoff = 30 * time.Second
+)
+
+const (
+	errInvalidAgentID = "invalid agent id"
+	errInvalidEvent   = "invalid event"
+	errActionNotFound = "action not found"
+)
+
+// The following APIs are used by the agent.
+
+// GetWorkflows streams workflows to the agent identified by req.AgentId. A workflow is dispatched
+// to an agent when it is Pending and its Hardware has a network interface whose MAC matches the
+// agent ID. Dispatched workflows transition to Scheduled and are dispatched again if the agent
+// doesn't start them. Workflows transitioning to Cancelling result in a StopWorkflow command.
+//
+// When the stream opens, workflows previously dispatched to the agent are reconciled with
+// req.RunningWorkflowIds, the workflows the agent is executing.
+func (s *KubernetesBackedServer) GetWorkflows(req *workflowproto.GetWorkflowsRequest, stream workflowproto.WorkflowService_GetWorkflowsServer) error {
+	agentID := req.GetAgentId()
+	if agentID == "" {
+		return status.Errorf(codes.InvalidArgument, errInvalidAgentID)
+	}
+
+	ctx := stream.Context()
+	log := s.logger.WithValues("agentID", agentID)
+
+	// stopped tracks workflows we've already sent a StopWorkflow command for on this stream.
+	stopped := map[string]bool{}
+
+	// running tracks workflows the agent reported it's executing when it connected. They're never
+	// dispatched again on this stream.
+	reconciled := false
+	running := map[string]bool{}
+	for _, id := range req.GetRunningWorkflowIds() {
+		running[id] = true
+	}
+	if len(running) > 0 {
+		log.Info("Agent reconnected with running workflows", "workflowIDs", req.GetRunningWorkflowIds())
+	}
+
+	interval := s.workflowPollInterval
+	if interval == 0 {
+		interval = defaultWorkflowPollInterval
+	}
+	dispatchTimeout := s.dispatchTimeout
+	if dispatchTimeout == 0 {
+		dispatchTimeout = defaultDispatchTimeout
+	}
+	rejectionBackoff := s.rejectionBackoff
+	if rejectionBackoff == 0 {
+		rejectionBackoff = defaultRejectionBackoff
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		wflws, err := s.getWorkflowsForAgent(ctx, agentID)
+		if err != nil {
+			log.Error(err, "list workflows for agent")
+			return status.Errorf(codes.Internal, "list workflows: %v", err)
+		}
+
+		if !reconciled {
+			if err := s.reconcileAgentWorkflows(ctx, wflws, running); err != nil {
+				log.Error(err, "reconcile workflows with agent")
+				return status.Errorf(codes.Internal, "reconcile workflows: %v", err)
+			}
+			reconciled = true
+		}
+
+		for i := range wflws {
+			wflw := &wflws[i]
+			id := workflowID(wflw)
+
+			switch wflw.Status.State {
+			case v1alpha2.WorkflowStatePending, v1alpha2.WorkflowStateScheduled:
+				if running[id] || !s.dispatchable(wflw, dispatchTimeout, rejectionBackoff) {
+					continue
+				}
+				redispatch := wflw.Status.State == v1alpha2.WorkflowStateScheduled
+				if err := s.startWorkflow(ctx, wflw, stream); err != nil {
+					if errors.IsConflict(err) {
+						// Someone else modified the workflow, try again on the next iteration.
+						continue
+					}
+					return err
+				}
+				log.Info("Dispatched workflow", "workflowID", id, "redispatch", redispatch)
+
+			case v1alpha2.WorkflowStateCancelling:
+				if stopped[id] {
+					continue
+				}
+				err := stream.Send(&workflowproto.GetWorkflowsResponse{
+					Cmd: &workflowproto.GetWorkflowsResponse_StopWorkflow_{
+						StopWorkflow: &workflowproto.GetWorkflowsResponse_StopWorkflow{
+							WorkflowId: id,
+						},
+					},
+				})
+				if err != nil {
+					return err
+				}
+				stopped[id] = true
+				log.Info("Requested workflow stop", "workflowID", id)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// reconcileAgentWorkflows reconciles workflows dispatched to an agent with running, the workflows
+// the agent reported executing when it connected. Scheduled workflows the agent is executing
+// transition to Running. Scheduled workflows the agent doesn't have return to Pending so they're
+// dispatched again while Running workflows it doesn't have were lost, typically because the agent
+// restarted, and fail. wflws are updated in place. Workflows modified concurrently are left for the
+// controller and later dispatch checks.
+func (s *KubernetesBackedServer) reconcileAgentWorkflows(ctx context.Context, wflws []v1alpha2.Workflow, running map[string]bool) error {
+	now := metav1.NewTime(s.nowFunc())
+	for i := range wflws {
+		wflw := &wflws[i]
+		id := workflowID(wflw)
+
+		switch {
+		case wflw.Status.State == v1alpha2.WorkflowStateScheduled && running[id]:
+			wflw.Status.State = v1alpha2.WorkflowStateRunning
+		case wflw.Status.State == v1alpha2.WorkflowStateScheduled:
+			wflw.Status.State = v1alpha2.WorkflowStatePending
+		case wflw.Status.State == v1alpha2.WorkflowStateRunning && !running[id]:
+			failLostWorkflow(wflw, now)
+		default:
+			continue
+		}
+		wflw.Status.LastTransition = now
+
+		if err := s.ClientFunc().Status().Update(ctx, wflw); err != nil {
+			if errors.IsConflict(err) {
+				continue
+			}
+			return err
+		}
+		s.logger.Info("Reconciled workflow with agent", "workflowID", id, "state", wflw.Status.State)
+	}
+	return nil
+}
+
+// failLostWorkflow fails wflw and its running actions because the agent no longer has it.
+func failLostWorkflow(wflw *v1alpha2.Workflow, now metav1.Time) {
+	const message = "agent reconnected without the workflow"
+	for i := range wflw.Status.Actions {
+		action := &wflw.Status.Actions[i]
+		if action.State == v1alpha2.ActionStateRunning {
+			finishAttempt(action, now, v1alpha2.WorkflowReasonWorkflowLost, message)
+			action.State = v1alpha2.ActionStateFailed
+			action.LastTransition = &now
+			action.FailureReason = v1alpha2.WorkflowReasonWorkflowLost
+			action.FailureMessage = message
+		}
+	}
+	wflw.Status.State = v1alpha2.WorkflowStateFailed
+}
+
+// dispatchable determines if wflw should be sent to its agent. Pending workflows are dispatched
+// once rendered, or once backoff has passed if the agent rejected them. Agents start dispatched
+// workflows immediately so a workflow that remains Scheduled beyond timeout was lost, typically
+// because the agent disconnected before starting it, and is dispatched again.
+func (s *KubernetesBackedServer) dispatchable(wflw *v1alpha2.Workflow, timeout, backoff time.Duration) bool {
+	switch wflw.Status.State { //nolint:exhaustive // Workflows in other states are never dispatched.
+	case v1alpha2.WorkflowStatePending:
+		if rejected := wflw.Status.Conditions.Get(v1alpha2.WorkflowConditionRejected); rejected != nil && rejected.Status == v1alpha2.ConditionStatusTrue {
+			if s.nowFunc().Sub(wflw.Status.LastTransition.Time) < backoff {
+				return false
+			}
+		}
+		return len(wflw.Status.Actions) > 0
+	case v1alpha2.WorkflowStateScheduled:
+		return s.nowFunc().Sub(wflw.Status.LastTransition.Time) >= timeout
+	}
+	return false
+}
+
+// startWorkflow transitions wflw to Scheduled and sends it to the agent. The workflow timeout is
+// measured from the first time the workflow is scheduled. If sending fails the workflow is
+// returned to Pending so it can be dispatched again.
+func (s *KubernetesBackedServer) startWorkflow(ctx context.Context, wflw *v1alpha2.Workflow, stream workflowproto.WorkflowService_GetWorkflowsServer) error {
+	now := metav1.NewTime(s.nowFunc())
+	startedAt := wflw.Status.StartedAt
+	wflw.Status.State = v1alpha2.WorkflowStateScheduled
+	wflw.Status.LastTransition = now
+	if wflw.Status.StartedAt == nil {
+		wflw.Status.StartedAt = &now
+	}
+	if err := s.ClientFunc().Status().Update(ctx, wflw); err != nil {
+		return err
+	}
+
+	err := stream.Send(&workflowproto.GetWorkflowsResponse{
+		Cmd: &workflowproto.GetWorkflowsResponse_StartWorkflow_{
+			StartWorkflow: &workflowproto.GetWorkflowsResponse_StartWorkflow{
+				Workflow: toWorkflowProto(wflw),
+			},
+		},
+	})
+	if err != nil {
+		wflw.Status.State = v1alpha2.WorkflowStatePending
+		wflw.Status.StartedAt = startedAt
+		if uerr := s.ClientFunc().Status().Update(context.WithoutCancel(ctx), wflw); uerr != nil {
+			s.logger.Error(uerr, "revert workflow to pending", "workflowID", workflowID(wflw))
+		}
+		return err
+	}
+
+	return nil
+}
+
+// getWorkflowsForAgent retrieves the non-terminal workflows whose Hardware is identified by
+// agentID.
+func (s *KubernetesBackedServer) getWorkflowsForAgent(ctx context.Context, agentID string) ([]v1alpha2.Workflow, error) {
+	var hardware v1alpha2.HardwareList
+	if err := s.ClientFunc().List(ctx, &hardware, client.MatchingFields{hardwareByMAC: strings.ToLower(agentID)}); err != nil {
+		return nil, err
+	}
+
+	var wflws []v1alpha2.Workflow
+	for _, hw := range hardware.Items {
+		var stored v1alpha2.WorkflowList
+		err := s.ClientFunc().List(ctx, &stored, client.InNamespace(hw.Namespace), client.MatchingFields{
+			workflowByNonTerminalHardware: hardwareKey(hw.Namespace, hw.Name),
+		})
+		if err != nil {
+			return nil, err
+		}
+		wflws = append(wflws, stored.Items...)
+	}
+
+	return wflws, nil
+}
+
+// authorizeAgent ensures the authenticated caller, if any, is an agent running on wflw's Hardware.
+func (s *KubernetesBackedServer) authorizeAgent(ctx context.Context, wflw *v1alpha2.Workflow) error {
+	identity, ok := grpcserver.IdentityFromContext(ctx)
+	if !ok {
+		return nil
+	}
+
+	var hw v1alpha2.Hardware
+	err := s.ClientFunc().Get(ctx, client.ObjectKey{Namespace: wflw.Namespace, Name: wflw.Spec.HardwareRef.Name}, &hw)
+	if err != nil && !errors.IsNotFound(err) {
+		return status.Errorf(codes.Internal, "get hardware: %v", err)
+	}
+	if err != nil || !hasMAC(hw.GetMACs(), identity) {
+		return status.Errorf(codes.PermissionDenied, errWorkflowNotOwned)
+	}
+	return nil
+}
+
+// PublishEvent applies the event to the status of the workflow it references.
+func (s *KubernetesBackedServer) PublishEvent(ctx context.Context, req *workflowproto.PublishEventRequest) (*workflowproto.PublishEventResponse, error) {
+	evnt := req.GetEvent()
+	if evnt == nil || evnt.GetEvent() == nil {
+		return nil, status.Errorf(codes.InvalidArgument, errInvalidEvent)
+	}
+
+	id := evnt.GetWorkflowId()
+	namespace, name, ok := strings.Cut(id, "/")
+	if !ok || namespace == "" || name == "" {
+		return nil, status.Errorf(codes.InvalidArgument, errInvalidWorkflowID)
+	}
+
+	l := s.logger.WithValues("workflowID", id)
+
+	var wflw v1alpha2.Workflow
+	if err := s.ClientFunc().Get(ctx, client.ObjectKey{Namespace: namespace, Name: name}, &wflw); err != nil {
+		if errors.IsNotFound(err) {
+			return nil, status.Errorf(codes.NotFound, "workflow %v not found", id)
+		}
+		l.Error(err, "get workflow")
+		return nil, status.Errorf(codes.Internal, "get workflow: %v", err)
+	}
+
+	if err := s.authorizeAgent(ctx, &wflw); err != nil {
+		return nil, err
+	}
+
+	// Action output doesn't modify the workflow and may arrive after the workflow completes.
+	if v, ok := evnt.GetEvent().(*workflowproto.Event_ActionLog_); ok {
+		if err := s.appendActionLog(&wflw, v.ActionLog); err != nil {
+			return nil, err
+		}
+		return &workflowproto.PublishEventResponse{}, nil
+	}
+
+	if wflw.Status.State.IsTerminal() {
+		return nil, status.Errorf(codes.FailedPrecondition, "workflow %v is %v", id, wflw.Status.State)
+	}
+
+	if err := s.applyEvent(&wflw, evnt); err != nil {
+		return nil, err
+	}
+
+	if err := s.ClientFunc().Status().Update(ctx, &wflw); err != nil {
+		if errors.IsConflict(err) {
+			return nil, status.Errorf(codes.Aborted, "update workflow: %v", err)
+		}
+		l.Error(err, "update workflow status")
+		return nil, status.Errorf(codes.Internal, "update workflow: %v", err)
+	}
+
+	return &workflowproto.PublishEventResponse{}, nil
+}
+
+// appendActionLog persists action output to s.ActionLogs. Output is discarded when log storage
+// isn't configured so agents needn't be configured to match the server.
+func (s *KubernetesBackedServer) appendActionLog(wflw *v1alpha2.Workflow, evnt *workflowproto.Event_ActionLog) error {
+	action := findActionStatus(wflw, evnt.GetActionId())
+	if action == nil {
+		return status.Errorf(codes.NotFound, "%v: %v", errActionNotFound, evnt.GetActionId())
+	}
+	if s.ActionLogs == nil {
+		return nil
+	}
+
+	// v2 workflows have no tasks so actions are identified by ID and name to keep them unique.
+	err := s.ActionLogs.Append(&proto.ActionLogChunk{
+		WorkflowId: workflowID(wflw),
+		TaskName:   action.ID,
+		ActionName: action.Rendered.Name,
+		Data:       evnt.GetData(),
+	})
+	switch {
+	case serrors.Is(err, ErrActionLogTooLarge):
+		return status.Errorf(codes.ResourceExhausted, "store action log: %v", err)
+	case err != nil:
+		return status.Errorf(codes.Internal, "store action log: %v", err)
+	}
+	return nil
+}
+
+// applyEvent modifies the status of wflw according to evnt.
+func (s *KubernetesBackedServer) applyEvent(wflw *v1alpha2.Workflow, evnt *workflowproto.Event) error {
+	now := metav1.NewTime(s.nowFunc())
+
+	switch v := evnt.GetEvent().(type) {
+	case *workflowproto.Event_ActionStarted_:
+		action := findActionStatus(wflw, v.ActionStarted.GetActionId())
+		if action == nil {
+			return status.Errorf(codes.NotFound, "%v: %v", errActionNotFound, v.ActionStarted.GetActionId())
+		}
+		action.State = v1alpha2.ActionStateRunning
+		if action.StartedAt == nil {
+			action.StartedAt = &now
+		}
+		action.LastTransition = &now
+		action.Attempts = append(action.Attempts, v1alpha2.ActionAttempt{
+			Attempt:   attemptNumber(action, v.ActionStarted.GetAttempt()),
+			StartedAt: now,
+		})
+
+		if wflw.Status.StartedAt == nil {
+			wflw.Status.StartedAt = &now
+		}
+		// A cancelling workflow remains cancelling until the agent confirms it has stopped.
+		if wflw.Status.State != v1alpha2.WorkflowStateRunning && wflw.Status.State != v1alpha2.WorkflowStateCancelling {
+			wflw.Status.State = v1alpha2.WorkflowStateRunning
+			wflw.Status.LastTransition = now
+		}
+		// The agent accepted the workflow so earlier rejections no longer apply.
+		if wflw.Status.Conditions.Get(v1alpha2.WorkflowConditionRejected) != nil {
+			wflw.Status.Conditions.Set(v1alpha2.Condition{
+				Type:           v1alpha2.WorkflowConditionRejected,
+				Status:         v1alpha2.ConditionStatusFalse,
+				LastTransition: now,
+			})
+		}
+
+	case *workflowproto.Event_ActionSucceeded_:
+		action := findActionStatus(wflw, v.ActionSucceeded.GetActionId())
+		if action == nil {
+			return status.Errorf(codes.NotFound, "%v: %v", errActionNotFound, v.ActionSucceeded.GetActionId())
+		}
+		action.State = v1alpha2.ActionStateSucceeded
+		action.LastTransition = &now
+		action.Outputs = v.ActionSucceeded.GetOutputs()
+		finishAttempt(action, now, "", "")
+		finishWorkflow(wflw, now)
+
+	case *workflowproto.Event_ActionSkipped_:
+		action := findActionStatus(wflw, v.ActionSkipped.GetActionId())
+		if action == nil {
+			return status.Errorf(codes.NotFound, "%v: %v", errActionNotFound, v.ActionSkipped.GetActionId())
+		}
+		action.State = v1alpha2.ActionStateSkipped
+		action.LastTransition = &now
+		finishWorkflow(wflw, now)
+
+	case *workflowproto.Event_ActionFailed_:
+		action := findActionStatus(wflw, v.ActionFailed.GetActionId())
+		if action == nil {
+			return status.Errorf(codes.NotFound, "%v: %v", errActionNotFound, v.ActionFailed.GetActionId())
+		}
+		finishAttempt(action, now, v.ActionFailed.GetFailureReason(), v.ActionFailed.GetFailureMessage())
+
+		// The agent will start another attempt so the action remains running.
+		if v.ActionFailed.GetRetrying() {
+			break
+		}
+
+		action.State = v1alpha2.ActionStateFailed
+		action.LastTransition = &now
+		action.FailureReason = v.ActionFailed.GetFailureReason()
+		action.FailureMessage = v.ActionFailed.GetFailureMessage()
+
+		// Actions grouped with the failed action continue until they finish.
+		if wflw.GroupFailed() {
+			wflw.Status.State = v1alpha2.WorkflowStateFailed
+			wflw.Status.LastTransition = now
+		}
+
+	case *workflowproto.Event_WorkflowRejected_:
+		s.logger.Info("Workflow rejected", "workflowID", evnt.GetWorkflowId(), "message", v.WorkflowRejected.GetMessage())
+
+		// Agents reject workflows dispatched again while they're running them. Only a workflow
+		// the agent hasn't started can be returned to Pending.
+		if wflw.Status.State != v1alpha2.WorkflowStateScheduled {
+			break
+		}
+
+		// The agent couldn't accept the workflow, typically because its busy. Return the workflow
+		// to Pending so it can be dispatched again once the rejection backoff has passed. The
+		// workflow didn't start so its timeout starts again when it's next scheduled.
+		wflw.Status.State = v1alpha2.WorkflowStatePending
+		wflw.Status.LastTransition = now
+		wflw.Status.StartedAt = nil
+		wflw.Status.Conditions.Set(v1alpha2.Condition{
+			Type:           v1alpha2.WorkflowConditionRejected,
+			Status:         v1alpha2.ConditionStatusTrue,
+			LastTransition: now,
+			Reason:         ptr.String(v1alpha2.WorkflowReasonAgentRejected),
+			Message:        ptr.String(v.WorkflowRejected.GetMessage()),
+		})
+
+	case *workflowproto.Event_WorkflowCanceled_:
+		wflw.Status.State = v1alpha2.WorkflowStateCanceled
+		wflw.Status.LastTransition = now
+
+	default:
+		return status.Errorf(codes.InvalidArgument, "%v: unknown type %T", errInvalidEvent, v)
+	}
+
+	return nil
+}
+
+func findActionStatus(wflw *v1alpha2.Workflow, actionID string) *v1alpha2.ActionStatus {
+	for i := range wflw.Status.Actions {
+		if wflw.Status.Actions[i].ID == actionID {
+			return &wflw.Status.Actions[i]
+		}
+	}
+	return nil
+}
+
+// attemptNumber returns the attempt number reported by the agent, or the next attempt number for
+// action if the agent didn't report one.
+func attemptNumber(action *v1alpha2.ActionStatus, reported int32) int {
+	if reported > 0 {
+		return int(reported)
+	}
+	return len(action.Attempts) + 1
+}
+
+// finishAttempt completes the latest in-progress attempt for action.
+func finishAttempt(action *v1alpha2.ActionStatus, now metav1.Time, reason, message string) {
+	n := len(action.Attempts)
+	if n == 0 || action.Attempts[n-1].FinishedAt != nil {
+		return
+	}
+	attempt := &action.Attempts[n-1]
+	attempt.FinishedAt = &now
+	attempt.FailureReason = reason
+	attempt.FailureMessage = message
+}
+
+// finishWorkflow transitions wflw to its final state once all actions have succeeded or an action
+// group has failed.
+func finishWorkflow(wflw *v1alpha2.Workflow, now metav1.Time) {
+	switch {
+	case allActionsSucceeded(wflw):
+		wflw.Status.State = v1alpha2.WorkflowStateSucceeded
+		wflw.Status.LastTransition = now
+	case wflw.GroupFailed():
+		wflw.Status.State = v1alpha2.WorkflowStateFailed
+		wflw.Status.LastTransition = now
+	}
+}
+
+// allActionsSucceeded returns true if every action succeeded or was skipped.
+func allActionsSucceeded(wflw *v1alpha2.Workflow) bool {
+	for _, action := range wflw.Status.Actions {
+		if action.State != v1alpha2.ActionStateSucceeded && action.State != v1alpha2.ActionStateSkipped {
+			return false
+		}
+	}
+	return true
+}
+
+func workflowID(wflw *v1alpha2.Workflow) string {
+	return fmt.Sprintf("%v/%v", wflw.Namespace, wflw.Name)
+}
+
+// toPIDNamespace converts a PID namespace, identified by a process in the namespace, to its
+// runtime representation. Process 1 identifies the host's PID namespace, the only PID namespace
+// runtimes support joining. Other namespaces are rejected when the template is rendered.
+func toPIDNamespace(pid *int) *string {
+	if pid == nil || *pid != 1 {
+		return nil
+	}
+	return ptr.String("host")
+}
+
+func toWorkflowProto(wflw *v1alpha2.Workflow) *workflowproto.Workflow {
+	var actions []*workflowproto.Workflow_Action
+	for _, action := range wflw.Status.Actions {
+		rendered := action.Rendered
+
+		var volumes []string
+		for _, v := range rendered.Volumes {
+			volumes = append(volumes, string(v))
+		}
+
+		var netns, pidns *string
+		if rendered.Namespace != nil {
+			netns = rendered.Namespace.Network
+			pidns = toPIDNamespace(rendered.Namespace.PID)
+		}
+
+		var backoff int64
+		if rendered.Backoff != nil {
+			backoff = int64(rendered.Backoff.Seconds())
+		}
+
+		actions = append(actions, &workflowproto.Workflow_Action{
+			Id:               action.ID,
+			Name:             rendered.Name,
+			Image:            rendered.Image,
+			Cmd:              rendered.Cmd,
+			Args:             rendered.Args,
+			Env:              rendered.Env,
+			Volumes:          volumes,
+			NetworkNamespace: netns,
+			PidNamespace:     pidns,
+			Retries:          int32(rendered.Retries),
+			BackoffSeconds:   backoff,
+			RetryOn:          rendered.RetryOn,
+			Group:            rendered.Group,
+			When:             rendered.When,
+		})
+	}
+
+	return &workflowproto.Workflow{
+		WorkflowId: workflowID(wflw),
+		Actions:    actions,
+	}
+}
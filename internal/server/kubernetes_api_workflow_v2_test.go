@@ -0,0 +1,827 @@
+package server
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/google/go-cmp/cmp"
+	"github.com/tinkerbell/tink/api/v1alpha2"
+	workflowproto "github.com/tinkerbell/tink/internal/proto/workflow/v2"
+	"github.com/tinkerbell/tink/internal/ptr"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/testing/protocmp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// getWorkflowsStream is a fake WorkflowService_GetWorkflowsServer that records sent responses.
+type getWorkflowsStream struct {
+	grpc.ServerStream
+	ctx    context.Context
+	onSend func(*workflowproto.GetWorkflowsResponse)
+}
+
+func (s *getWorkflowsStream) Context() context.Context { return s.ctx }
+
+func (s *getWorkflowsStream) Send(r *workflowproto.GetWorkflowsResponse) error {
+	s.onSend(r)
+	return nil
+}
+
+func newV2TestServer(t *testing.T, objs ...client.Object) *KubernetesBackedServer {
+	t.Helper()
+
+	scheme := runtime.NewScheme()
+	if err := v1alpha2.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+
+	clnt := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(objs...).
+		WithStatusSubresource(&v1alpha2.Workflow{}, &v1alpha2.Agent{}).
+		WithIndex(&v1alpha2.Hardware{}, hardwareByMAC, hardwareByMACFunc).
+		WithIndex(&v1alpha2.Workflow{}, workflowByNonTerminalHardware, workflowByNonTerminalHardwareFunc).
+		Build()
+
+	return &KubernetesBackedServer{
+		logger:               logr.Discard(),
+		ClientFunc:           func() client.Client { return clnt },
+		nowFunc:              TestTime.Now,
+		workflowPollInterval: 10 * time.Millisecond,
+	}
+}
+
+func newV2Hardware() *v1alpha2.Hardware {
+	return &v1alpha2.Hardware{
+		ObjectMeta: metav1.ObjectMeta{Name: "hardware", Namespace: "default"},
+		Spec: v1alpha2.HardwareSpec{
+			NetworkInterfaces: v1alpha2.NetworkInterfaces{
+				"00:00:00:00:00:01": v1alpha2.NetworkInterface{},
+			},
+		},
+	}
+}
+
+func newV2Workflow(state v1alpha2.WorkflowState) *v1alpha2.Workflow {
+	return &v1alpha2.Workflow{
+		ObjectMeta: metav1.ObjectMeta{Name: "workflow", Namespace: "default"},
+		Spec: v1alpha2.WorkflowSpec{
+			HardwareRef: corev1.LocalObjectReference{Name: "hardware"},
+		},
+		Status: v1alpha2.WorkflowStatus{
+			State: state,
+			Actions: []v1alpha2.ActionStatus{
+				{
+					ID:    "1",
+					State: v1alpha2.ActionStatePending,
+					Rendered: v1alpha2.Action{
+						Name:    "action1",
+						Image:   "image1",
+						Cmd:     ptr.String("cmd"),
+						Args:    []string{"arg"},
+						Env:     map[string]string{"foo": "bar"},
+						Volumes: []v1alpha2.Volume{"/foo:/bar"},
+						Namespace: &v1alpha2.Namespace{
+							Network: ptr.String("host"),
+							PID:     ptr.Int(1),
+						},
+					},
+				},
+				{
+					ID:       "2",
+					State:    v1alpha2.ActionStatePending,
+					Rendered: v1alpha2.Action{Name: "action2", Image: "image2"},
+				},
+			},
+		},
+	}
+}
+
+func TestGetWorkflows_StartWorkflow(t *testing.T) {
+	server := newV2TestServer(t, newV2Hardware(), newV2Workflow(v1alpha2.WorkflowStatePending))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var received []*workflowproto.GetWorkflowsResponse
+	stream := &getWorkflowsStream{
+		ctx: ctx,
+		onSend: func(r *workflowproto.GetWorkflowsResponse) {
+			received = append(received, r)
+			cancel()
+		},
+	}
+
+	err := server.GetWorkflows(&workflowproto.GetWorkflowsRequest{AgentId: "00:00:00:00:00:01"}, stream)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expect := []*workflowproto.GetWorkflowsResponse{
+		{
+			Cmd: &workflowproto.GetWorkflowsResponse_StartWorkflow_{
+				StartWorkflow: &workflowproto.GetWorkflowsResponse_StartWorkflow{
+					Workflow: &workflowproto.Workflow{
+						WorkflowId: "default/workflow",
+						Actions: []*workflowproto.Workflow_Action{
+							{
+								Id:               "1",
+								Name:             "action1",
+								Image:            "image1",
+								Cmd:              ptr.String("cmd"),
+								Args:             []string{"arg"},
+								Env:              map[string]string{"foo": "bar"},
+								Volumes:          []string{"/foo:/bar"},
+								NetworkNamespace: ptr.String("host"),
+								PidNamespace:     ptr.String("host"),
+							},
+							{
+								Id:    "2",
+								Name:  "action2",
+								Image: "image2",
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	if diff := cmp.Diff(expect, received, protocmp.Transform()); diff != "" {
+		t.Fatal(diff)
+	}
+
+	var wflw v1alpha2.Workflow
+	if err := server.ClientFunc().Get(context.Background(), client.ObjectKey{Namespace: "default", Name: "workflow"}, &wflw); err != nil {
+		t.Fatal(err)
+	}
+	if wflw.Status.State != v1alpha2.WorkflowStateScheduled {
+		t.Fatalf("Expected workflow state %v; received %v", v1alpha2.WorkflowStateScheduled, wflw.Status.State)
+	}
+	if wflw.Status.StartedAt == nil {
+		t.Fatal("Expected workflow to be started when scheduled")
+	}
+}
+
+func TestDispatchable(t *testing.T) {
+	cases := []struct {
+		Name         string
+		State        v1alpha2.WorkflowState
+		NoActions    bool
+		Rejected     bool
+		ScheduledFor time.Duration
+		Expect       bool
+	}{
+		{Name: "Pending", State: v1alpha2.WorkflowStatePending, Expect: true},
+		{Name: "PendingNotRendered", State: v1alpha2.WorkflowStatePending, NoActions: true},
+		{Name: "RejectedRecently", State: v1alpha2.WorkflowStatePending, Rejected: true, ScheduledFor: defaultRejectionBackoff / 2},
+		{Name: "RejectedBackoffPassed", State: v1alpha2.WorkflowStatePending, Rejected: true, ScheduledFor: defaultRejectionBackoff, Expect: true},
+		{Name: "ScheduledStale", State: v1alpha2.WorkflowStateScheduled, ScheduledFor: defaultDispatchTimeout, Expect: true},
+		{Name: "ScheduledRecently", State: v1alpha2.WorkflowStateScheduled, ScheduledFor: defaultDispatchTimeout / 2},
+		{Name: "Running", State: v1alpha2.WorkflowStateRunning, ScheduledFor: defaultDispatchTimeout},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.Name, func(t *testing.T) {
+			wflw := newV2Workflow(tc.State)
+			wflw.Status.LastTransition = metav1.NewTime(TestTime.Now().Add(-tc.ScheduledFor))
+			if tc.NoActions {
+				wflw.Status.Actions = nil
+			}
+			if tc.Rejected {
+				wflw.Status.Conditions.Set(v1alpha2.Condition{
+					Type:           v1alpha2.WorkflowConditionRejected,
+					Status:         v1alpha2.ConditionStatusTrue,
+					LastTransition: wflw.Status.LastTransition,
+				})
+			}
+			server := newV2TestServer(t)
+
+			if got := server.dispatchable(wflw, defaultDispatchTimeout, defaultRejectionBackoff); got != tc.Expect {
+				t.Fatalf("Expected: %v; Received: %v", tc.Expect, got)
+			}
+		})
+	}
+}
+
+func TestGetWorkflows_ReconcileAgentWorkflows(t *testing.T) {
+	cases := []struct {
+		Name             string
+		State            v1alpha2.WorkflowState
+		Running          []string
+		ExpectState      v1alpha2.WorkflowState
+		ExpectDispatched bool
+	}{
+		{
+			Name:        "ScheduledRunningOnAgent",
+			State:       v1alpha2.WorkflowStateScheduled,
+			Running:     []string{"default/workflow"},
+			ExpectState: v1alpha2.WorkflowStateRunning,
+		},
+		{
+			Name:             "ScheduledMissingOnAgent",
+			State:            v1alpha2.WorkflowStateScheduled,
+			ExpectState:      v1alpha2.WorkflowStateScheduled,
+			ExpectDispatched: true,
+		},
+		{
+			Name:        "RunningOnAgent",
+			State:       v1alpha2.WorkflowStateRunning,
+			Running:     []string{"default/workflow"},
+			ExpectState: v1alpha2.WorkflowStateRunning,
+		},
+		{
+			Name:        "RunningMissingOnAgent",
+			State:       v1alpha2.WorkflowStateRunning,
+			ExpectState: v1alpha2.WorkflowStateFailed,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.Name, func(t *testing.T) {
+			wflw := newV2Workflow(tc.State)
+			wflw.Status.LastTransition = metav1.NewTime(TestTime.Now())
+			if tc.State == v1alpha2.WorkflowStateRunning {
+				wflw.Status.Actions[0].State = v1alpha2.ActionStateRunning
+			}
+			server := newV2TestServer(t, newV2Hardware(), wflw)
+
+			ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+			defer cancel()
+
+			var dispatched bool
+			stream := &getWorkflowsStream{
+				ctx: ctx,
+				onSend: func(r *workflowproto.GetWorkflowsResponse) {
+					if r.GetStartWorkflow() != nil {
+						dispatched = true
+					}
+					cancel()
+				},
+			}
+
+			err := server.GetWorkflows(&workflowproto.GetWorkflowsRequest{
+				AgentId:            "00:00:00:00:00:01",
+				RunningWorkflowIds: tc.Running,
+			}, stream)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if dispatched != tc.ExpectDispatched {
+				t.Fatalf("Expected dispatched: %v; received: %v", tc.ExpectDispatched, dispatched)
+			}
+
+			var got v1alpha2.Workflow
+			if err := server.ClientFunc().Get(context.Background(), client.ObjectKeyFromObject(wflw), &got); err != nil {
+				t.Fatal(err)
+			}
+			if got.Status.State != tc.ExpectState {
+				t.Fatalf("Expected workflow state %v; received %v", tc.ExpectState, got.Status.State)
+			}
+			if tc.ExpectState == v1alpha2.WorkflowStateFailed {
+				if reason := got.Status.Actions[0].FailureReason; reason != v1alpha2.WorkflowReasonWorkflowLost {
+					t.Fatalf("Expected failure reason %v; received %v", v1alpha2.WorkflowReasonWorkflowLost, reason)
+				}
+			}
+		})
+	}
+}
+
+func TestGetWorkflows_StopWorkflow(t *testing.T) {
+	server := newV2TestServer(t, newV2Hardware(), newV2Workflow(v1alpha2.WorkflowStateCancelling))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// Let the stream poll several times so we can verify StopWorkflow is only sent once.
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		cancel()
+	}()
+
+	var received []*workflowproto.GetWorkflowsResponse
+	stream := &getWorkflowsStream{
+		ctx: ctx,
+		onSend: func(r *workflowproto.GetWorkflowsResponse) {
+			received = append(received, r)
+		},
+	}
+
+	err := server.GetWorkflows(&workflowproto.GetWorkflowsRequest{AgentId: "00:00:00:00:00:01"}, stream)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expect := []*workflowproto.GetWorkflowsResponse{
+		{
+			Cmd: &workflowproto.GetWorkflowsResponse_StopWorkflow_{
+				StopWorkflow: &workflowproto.GetWorkflowsResponse_StopWorkflow{
+					WorkflowId: "default/workflow",
+				},
+			},
+		},
+	}
+	if diff := cmp.Diff(expect, received, protocmp.Transform()); diff != "" {
+		t.Fatal(diff)
+	}
+}
+
+func TestGetWorkflows_OtherAgent(t *testing.T) {
+	server := newV2TestServer(t, newV2Hardware(), newV2Workflow(v1alpha2.WorkflowStatePending))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	stream := &getWorkflowsStream{
+		ctx: ctx,
+		onSend: func(r *workflowproto.GetWorkflowsResponse) {
+			t.Fatalf("Unexpected response: %v", r)
+		},
+	}
+
+	err := server.GetWorkflows(&workflowproto.GetWorkflowsRequest{AgentId: "00:00:00:00:00:02"}, stream)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestGetWorkflows_AgentIDCase(t *testing.T) {
+	hw := newV2Hardware()
+	hw.Spec.NetworkInterfaces = v1alpha2.NetworkInterfaces{"AA:BB:CC:DD:EE:FF": v1alpha2.NetworkInterface{}}
+	server := newV2TestServer(t, hw, newV2Workflow(v1alpha2.WorkflowStatePending))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var dispatched bool
+	stream := &getWorkflowsStream{
+		ctx: ctx,
+		onSend: func(r *workflowproto.GetWorkflowsResponse) {
+			dispatched = r.GetStartWorkflow() != nil
+			cancel()
+		},
+	}
+
+	err := server.GetWorkflows(&workflowproto.GetWorkflowsRequest{AgentId: "aa:bb:cc:dd:ee:ff"}, stream)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !dispatched {
+		t.Fatal("Expected workflow to be dispatched to agent with differently cased ID")
+	}
+}
+
+func TestGetWorkflows_RunningWorkflow(t *testing.T) {
+	server := newV2TestServer(t, newV2Hardware(), newV2Workflow(v1alpha2.WorkflowStatePending))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	stream := &getWorkflowsStream{
+		ctx: ctx,
+		onSend: func(r *workflowproto.GetWorkflowsResponse) {
+			t.Fatalf("Unexpected response: %v", r)
+		},
+	}
+
+	err := server.GetWorkflows(&workflowproto.GetWorkflowsRequest{
+		AgentId:            "00:00:00:00:00:01",
+		RunningWorkflowIds: []string{"default/workflow"},
+	}, stream)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestGetWorkflows_RejectedWorkflow(t *testing.T) {
+	wflw := newV2Workflow(v1alpha2.WorkflowStatePending)
+	wflw.Status.LastTransition = metav1.NewTime(TestTime.Now())
+	wflw.Status.Conditions.Set(v1alpha2.Condition{
+		Type:           v1alpha2.WorkflowConditionRejected,
+		Status:         v1alpha2.ConditionStatusTrue,
+		LastTransition: wflw.Status.LastTransition,
+	})
+	server := newV2TestServer(t, newV2Hardware(), wflw)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	stream := &getWorkflowsStream{
+		ctx: ctx,
+		onSend: func(r *workflowproto.GetWorkflowsResponse) {
+			t.Fatalf("Unexpected response: %v", r)
+		},
+	}
+
+	err := server.GetWorkflows(&workflowproto.GetWorkflowsRequest{AgentId: "00:00:00:00:00:01"}, stream)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestGetWorkflows_MissingAgentID(t *testing.T) {
+	server := newV2TestServer(t)
+	err := server.GetWorkflows(&workflowproto.GetWorkflowsRequest{}, &getWorkflowsStream{ctx: context.Background()})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("Expected code %v; received %v", codes.InvalidArgument, err)
+	}
+}
+
+func TestPublishEvent(t *testing.T) {
+	now := metav1.NewTime(TestTime.Now())
+
+	cases := []struct {
+		Name        string
+		State       v1alpha2.WorkflowState
+		Event       *workflowproto.Event
+		ExpectCode  codes.Code
+		ExpectState v1alpha2.WorkflowState
+		Setup       func(*v1alpha2.Workflow)
+		Mutate      func(*v1alpha2.ActionStatus, *v1alpha2.ActionStatus)
+	}{
+		{
+			Name:  "ActionStarted",
+			State: v1alpha2.WorkflowStateScheduled,
+			Event: &workflowproto.Event{
+				WorkflowId: "default/workflow",
+				Event: &workflowproto.Event_ActionStarted_{
+					ActionStarted: &workflowproto.Event_ActionStarted{ActionId: "1"},
+				},
+			},
+			ExpectState: v1alpha2.WorkflowStateRunning,
+			Mutate: func(a1, _ *v1alpha2.ActionStatus) {
+				a1.State = v1alpha2.ActionStateRunning
+				a1.StartedAt = &now
+				a1.LastTransition = &now
+				a1.Attempts = []v1alpha2.ActionAttempt{{Attempt: 1, StartedAt: now}}
+			},
+		},
+		{
+			Name:  "ActionRetryStarted",
+			State: v1alpha2.WorkflowStateRunning,
+			Event: &workflowproto.Event{
+				WorkflowId: "default/workflow",
+				Event: &workflowproto.Event_ActionStarted_{
+					ActionStarted: &workflowproto.Event_ActionStarted{ActionId: "1", Attempt: 2},
+				},
+			},
+			ExpectState: v1alpha2.WorkflowStateRunning,
+			Setup: func(w *v1alpha2.Workflow) {
+				w.Status.Actions[0].State = v1alpha2.ActionStateRunning
+				w.Status.Actions[0].Attempts = []v1alpha2.ActionAttempt{
+					{Attempt: 1, StartedAt: now, FinishedAt: &now, FailureReason: "Reason"},
+				}
+			},
+			Mutate: func(a1, _ *v1alpha2.ActionStatus) {
+				a1.StartedAt = &now
+				a1.LastTransition = &now
+				a1.Attempts = append(a1.Attempts, v1alpha2.ActionAttempt{Attempt: 2, StartedAt: now})
+			},
+		},
+		{
+			Name:  "LastActionSucceeded",
+			State: v1alpha2.WorkflowStateRunning,
+			Event: &workflowproto.Event{
+				WorkflowId: "default/workflow",
+				Event: &workflowproto.Event_ActionSucceeded_{
+					ActionSucceeded: &workflowproto.Event_ActionSucceeded{ActionId: "2"},
+				},
+			},
+			ExpectState: v1alpha2.WorkflowStateSucceeded,
+			Setup: func(w *v1alpha2.Workflow) {
+				w.Status.Actions[0].State = v1alpha2.ActionStateSucceeded
+			},
+			Mutate: func(_, a2 *v1alpha2.ActionStatus) {
+				a2.State = v1alpha2.ActionStateSucceeded
+				a2.LastTransition = &now
+			},
+		},
+		{
+			Name:  "ActionSucceededWithOutputs",
+			State: v1alpha2.WorkflowStateRunning,
+			Event: &workflowproto.Event{
+				WorkflowId: "default/workflow",
+				Event: &workflowproto.Event_ActionSucceeded_{
+					ActionSucceeded: &workflowproto.Event_ActionSucceeded{
+						ActionId: "1",
+						Outputs:  map[string]string{"count": "2"},
+					},
+				},
+			},
+			ExpectState: v1alpha2.WorkflowStateRunning,
+			Mutate: func(a1, _ *v1alpha2.ActionStatus) {
+				a1.State = v1alpha2.ActionStateSucceeded
+				a1.LastTransition = &now
+				a1.Outputs = map[string]string{"count": "2"}
+			},
+		},
+		{
+			Name:  "ActionFailed",
+			State: v1alpha2.WorkflowStateRunning,
+			Event: &workflowproto.Event{
+				WorkflowId: "default/workflow",
+				Event: &workflowproto.Event_ActionFailed_{
+					ActionFailed: &workflowproto.Event_ActionFailed{
+						ActionId:       "1",
+						FailureReason:  ptr.String("Reason"),
+						FailureMessage: ptr.String("message"),
+					},
+				},
+			},
+			ExpectState: v1alpha2.WorkflowStateFailed,
+			Mutate: func(a1, _ *v1alpha2.ActionStatus) {
+				a1.State = v1alpha2.ActionStateFailed
+				a1.LastTransition = &now
+				a1.FailureReason = "Reason"
+				a1.FailureMessage = "message"
+			},
+		},
+		{
+			Name:  "GroupedActionFailed",
+			State: v1alpha2.WorkflowStateRunning,
+			Event: &workflowproto.Event{
+				WorkflowId: "default/workflow",
+				Event: &workflowproto.Event_ActionFailed_{
+					ActionFailed: &workflowproto.Event_ActionFailed{
+						ActionId:       "1",
+						FailureReason:  ptr.String("Reason"),
+						FailureMessage: ptr.String("message"),
+					},
+				},
+			},
+			// The workflow fails once the remaining action in the group finishes.
+			ExpectState: v1alpha2.WorkflowStateRunning,
+			Setup: func(w *v1alpha2.Workflow) {
+				w.Status.Actions[0].State = v1alpha2.ActionStateRunning
+				w.Status.Actions[0].Rendered.Group = "group"
+				w.Status.Actions[1].State = v1alpha2.ActionStateRunning
+				w.Status.Actions[1].Rendered.Group = "group"
+			},
+			Mutate: func(a1, _ *v1alpha2.ActionStatus) {
+				a1.State = v1alpha2.ActionStateFailed
+				a1.LastTransition = &now
+				a1.FailureReason = "Reason"
+				a1.FailureMessage = "message"
+			},
+		},
+		{
+			Name:  "GroupedActionFailedBeforeSiblingStarted",
+			State: v1alpha2.WorkflowStateRunning,
+			Event: &workflowproto.Event{
+				WorkflowId: "default/workflow",
+				Event: &workflowproto.Event_ActionFailed_{
+					ActionFailed: &workflowproto.Event_ActionFailed{
+						ActionId:       "1",
+						FailureReason:  ptr.String("Reason"),
+						FailureMessage: ptr.String("message"),
+					},
+				},
+			},
+			// The sibling's ActionStarted event hasn't arrived so the group hasn't finished.
+			ExpectState: v1alpha2.WorkflowStateRunning,
+			Setup: func(w *v1alpha2.Workflow) {
+				w.Status.Actions[0].State = v1alpha2.ActionStateRunning
+				w.Status.Actions[0].Rendered.Group = "group"
+				w.Status.Actions[1].Rendered.Group = "group"
+			},
+			Mutate: func(a1, _ *v1alpha2.ActionStatus) {
+				a1.State = v1alpha2.ActionStateFailed
+				a1.LastTransition = &now
+				a1.FailureReason = "Reason"
+				a1.FailureMessage = "message"
+			},
+		},
+		{
+			Name:  "GroupedActionSucceededAfterFailure",
+			State: v1alpha2.WorkflowStateRunning,
+			Event: &workflowproto.Event{
+				WorkflowId: "default/workflow",
+				Event: &workflowproto.Event_ActionSucceeded_{
+					ActionSucceeded: &workflowproto.Event_ActionSucceeded{ActionId: "2"},
+				},
+			},
+			ExpectState: v1alpha2.WorkflowStateFailed,
+			Setup: func(w *v1alpha2.Workflow) {
+				w.Status.Actions[0].State = v1alpha2.ActionStateFailed
+				w.Status.Actions[0].Rendered.Group = "group"
+				w.Status.Actions[1].State = v1alpha2.ActionStateRunning
+				w.Status.Actions[1].Rendered.Group = "group"
+			},
+			Mutate: func(_, a2 *v1alpha2.ActionStatus) {
+				a2.State = v1alpha2.ActionStateSucceeded
+				a2.LastTransition = &now
+			},
+		},
+		{
+			Name:  "ActionSkipped",
+			State: v1alpha2.WorkflowStateRunning,
+			Event: &workflowproto.Event{
+				WorkflowId: "default/workflow",
+				Event: &workflowproto.Event_ActionSkipped_{
+					ActionSkipped: &workflowproto.Event_ActionSkipped{ActionId: "2"},
+				},
+			},
+			ExpectState: v1alpha2.WorkflowStateSucceeded,
+			Setup: func(w *v1alpha2.Workflow) {
+				w.Status.Actions[0].State = v1alpha2.ActionStateSucceeded
+			},
+			Mutate: func(_, a2 *v1alpha2.ActionStatus) {
+				a2.State = v1alpha2.ActionStateSkipped
+				a2.LastTransition = &now
+			},
+		},
+		{
+			Name:  "ActionFailedRetrying",
+			State: v1alpha2.WorkflowStateRunning,
+			Event: &workflowproto.Event{
+				WorkflowId: "default/workflow",
+				Event: &workflowproto.Event_ActionFailed_{
+					ActionFailed: &workflowproto.Event_ActionFailed{
+						ActionId:       "1",
+						FailureReason:  ptr.String("Reason"),
+						FailureMessage: ptr.String("message"),
+						Attempt:        1,
+						Retrying:       true,
+					},
+				},
+			},
+			ExpectState: v1alpha2.WorkflowStateRunning,
+			Setup: func(w *v1alpha2.Workflow) {
+				w.Status.Actions[0].State = v1alpha2.ActionStateRunning
+				w.Status.Actions[0].Attempts = []v1alpha2.ActionAttempt{{Attempt: 1, StartedAt: now}}
+			},
+			Mutate: func(a1, _ *v1alpha2.ActionStatus) {
+				a1.Attempts[0].FinishedAt = &now
+				a1.Attempts[0].FailureReason = "Reason"
+				a1.Attempts[0].FailureMessage = "message"
+			},
+		},
+		{
+			Name:  "WorkflowRejected",
+			State: v1alpha2.WorkflowStateScheduled,
+			Event: &workflowproto.Event{
+				WorkflowId: "default/workflow",
+				Event: &workflowproto.Event_WorkflowRejected_{
+					WorkflowRejected: &workflowproto.Event_WorkflowRejected{Message: "busy"},
+				},
+			},
+			ExpectState: v1alpha2.WorkflowStatePending,
+		},
+		{
+			// Agents reject workflows dispatched again while they're running them.
+			Name:  "WorkflowRejectedWhileRunning",
+			State: v1alpha2.WorkflowStateRunning,
+			Event: &workflowproto.Event{
+				WorkflowId: "default/workflow",
+				Event: &workflowproto.Event_WorkflowRejected_{
+					WorkflowRejected: &workflowproto.Event_WorkflowRejected{Message: "workflow already in progress"},
+				},
+			},
+			ExpectState: v1alpha2.WorkflowStateRunning,
+		},
+		{
+			Name:  "WorkflowCanceled",
+			State: v1alpha2.WorkflowStateCancelling,
+			Event: &workflowproto.Event{
+				WorkflowId: "default/workflow",
+				Event: &workflowproto.Event_WorkflowCanceled_{
+					WorkflowCanceled: &workflowproto.Event_WorkflowCanceled{},
+				},
+			},
+			ExpectState: v1alpha2.WorkflowStateCanceled,
+		},
+		{
+			Name:  "UnknownAction",
+			State: v1alpha2.WorkflowStateRunning,
+			Event: &workflowproto.Event{
+				WorkflowId: "default/workflow",
+				Event: &workflowproto.Event_ActionStarted_{
+					ActionStarted: &workflowproto.Event_ActionStarted{ActionId: "unknown"},
+				},
+			},
+			ExpectCode:  codes.NotFound,
+			ExpectState: v1alpha2.WorkflowStateRunning,
+		},
+		{
+			Name:  "UnknownWorkflow",
+			State: v1alpha2.WorkflowStateRunning,
+			Event: &workflowproto.Event{
+				WorkflowId: "default/unknown",
+				Event: &workflowproto.Event_ActionStarted_{
+					ActionStarted: &workflowproto.Event_ActionStarted{ActionId: "1"},
+				},
+			},
+			ExpectCode:  codes.NotFound,
+			ExpectState: v1alpha2.WorkflowStateRunning,
+		},
+		{
+			Name:  "TerminalWorkflow",
+			State: v1alpha2.WorkflowStateFailed,
+			Event: &workflowproto.Event{
+				WorkflowId: "default/workflow",
+				Event: &workflowproto.Event_ActionStarted_{
+					ActionStarted: &workflowproto.Event_ActionStarted{ActionId: "2"},
+				},
+			},
+			ExpectCode:  codes.FailedPrecondition,
+			ExpectState: v1alpha2.WorkflowStateFailed,
+		},
+		{
+			Name:        "MissingEvent",
+			State:       v1alpha2.WorkflowStateRunning,
+			Event:       &workflowproto.Event{WorkflowId: "default/workflow"},
+			ExpectCode:  codes.InvalidArgument,
+			ExpectState: v1alpha2.WorkflowStateRunning,
+		},
+		{
+			Name:  "InvalidWorkflowID",
+			State: v1alpha2.WorkflowStateRunning,
+			Event: &workflowproto.Event{
+				WorkflowId: "workflow",
+				Event: &workflowproto.Event_ActionStarted_{
+					ActionStarted: &workflowproto.Event_ActionStarted{ActionId: "1"},
+				},
+			},
+			ExpectCode:  codes.InvalidArgument,
+			ExpectState: v1alpha2.WorkflowStateRunning,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.Name, func(t *testing.T) {
+			wflw := newV2Workflow(tc.State)
+			if tc.Setup != nil {
+				tc.Setup(wflw)
+			}
+			server := newV2TestServer(t, newV2Hardware(), wflw)
+
+			_, err := server.PublishEvent(context.Background(), &workflowproto.PublishEventRequest{Event: tc.Event})
+			if status.Code(err) != tc.ExpectCode {
+				t.Fatalf("Expected code %v; received %v", tc.ExpectCode, err)
+			}
+
+			var got v1alpha2.Workflow
+			if err := server.ClientFunc().Get(context.Background(), client.ObjectKeyFromObject(wflw), &got); err != nil {
+				t.Fatal(err)
+			}
+
+			if got.Status.State != tc.ExpectState {
+				t.Fatalf("Expected workflow state %v; received %v", tc.ExpectState, got.Status.State)
+			}
+
+			expect := wflw.DeepCopy().Status.Actions
+			if tc.Mutate != nil {
+				tc.Mutate(&expect[0], &expect[1])
+			}
+			if diff := cmp.Diff(expect, got.Status.Actions); diff != "" {
+				t.Fatal(diff)
+			}
+		})
+	}
+}
+
+func TestPublishEvent_ActionLog(t *testing.T) {
+	// Output may arrive after the action's terminal event so logs must be accepted for
+	// completed workflows.
+	wflw := newV2Workflow(v1alpha2.WorkflowStateSucceeded)
+	server := newV2TestServer(t, newV2Hardware(), wflw)
+
+	dir := t.TempDir()
+	server.ActionLogs = NewFileActionLogStore(dir)
+
+	publish := func(actionID string) error {
+		_, err := server.PublishEvent(context.Background(), &workflowproto.PublishEventRequest{
+			Event: &workflowproto.Event{
+				WorkflowId: "default/workflow",
+				Event: &workflowproto.Event_ActionLog_{
+					ActionLog: &workflowproto.Event_ActionLog{ActionId: actionID, Attempt: 1, Data: []byte("hello\n")},
+				},
+			},
+		})
+		return err
+	}
+
+	if err := publish("1"); err != nil {
+		t.Fatal(err)
+	}
+	if err := publish("unknown"); status.Code(err) != codes.NotFound {
+		t.Fatalf("Expected code %v; received %v", codes.NotFound, err)
+	}
+
+	got, err := os.ReadFile(filepath.Join(dir, "default", "workflow", "1", "action1.log"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != "hello\n" {
+		t.Fatalf("Expected: %q; Received: %q", "hello\n", got)
+	}
+}
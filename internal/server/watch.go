@@ -0,0 +1,66 @@
+package server
+
+import (
+	"sync"
+
+	"github.com/tinkerbell/tink/api/v1alpha1"
+	toolscache "k8s.io/client-go/tools/cache"
+)
+
+// workflowNotifier notifies subscribed workers when a workflow assigned to them changes.
+// Notifications carry no data; subscribers re-read workflows when notified so notifications can
+// be coalesced without losing changes.
+type workflowNotifier struct {
+	mu          sync.Mutex
+	subscribers map[string]map[chan struct{}]struct{}
+}
+
+func newWorkflowNotifier() *workflowNotifier {
+	return &workflowNotifier{subscribers: map[string]map[chan struct{}]struct{}{}}
+}
+
+// subscribe registers interest in workflows assigned to workerID. The returned function must be
+// called to unsubscribe.
+func (n *workflowNotifier) subscribe(workerID string) (<-chan struct{}, func()) {
+	ch := make(chan struct{}, 1)
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if n.subscribers[workerID] == nil {
+		n.subscribers[workerID] = map[chan struct{}]struct{}{}
+	}
+	n.subscribers[workerID][ch] = struct{}{}
+
+	return ch, func() {
+		n.mu.Lock()
+		defer n.mu.Unlock()
+		delete(n.subscribers[workerID], ch)
+		if len(n.subscribers[workerID]) == 0 {
+			delete(n.subscribers, workerID)
+		}
+	}
+}
+
+// notify notifies subscribers for every worker assigned a task in obj. Tombstones for deletes the
+// informer missed are unwrapped. Objects that aren't workflows are ignored.
+func (n *workflowNotifier) notify(obj any) {
+	if tombstone, ok := obj.(toolscache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+	wf, ok := obj.(*v1alpha1.Workflow)
+	if !ok {
+		return
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	for _, task := range wf.Status.Tasks {
+		for ch := range n.subscribers[task.WorkerAddr] {
+			// A pending notification already covers this change.
+			select {
+			case ch <- struct{}{}:
+			default:
+			}
+		}
+	}
+}
@@ -0,0 +1,185 @@
+package server
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/google/go-cmp/cmp"
+	"github.com/tinkerbell/tink/api/v1alpha1"
+	"github.com/tinkerbell/tink/internal/proto"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/testing/protocmp"
+	"k8s.io/apimachinery/pkg/runtime"
+	toolscache "k8s.io/client-go/tools/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// watchContextsStream is a fake WorkflowService_GetWorkflowContextsServer that forwards sent
+// contexts to a channel.
+type watchContextsStream struct {
+	grpc.ServerStream
+	ctx  context.Context
+	sent chan *proto.WorkflowContext
+}
+
+func (s *watchContextsStream) Context() context.Context { return s.ctx }
+
+func (s *watchContextsStream) Send(c *proto.WorkflowContext) error {
+	s.sent <- c
+	return nil
+}
+
+func TestGetWorkflowContexts_Watch(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := v1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	wf := newTestWorkflow()
+	clnt := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(wf).
+		WithStatusSubresource(&v1alpha1.Workflow{}).
+		WithIndex(&v1alpha1.Workflow{}, workflowByNonTerminalState, workflowByNonTerminalStateFunc).
+		Build()
+
+	s := &KubernetesBackedServer{
+		logger:     logr.Discard(),
+		ClientFunc: func() client.Client { return clnt },
+		nowFunc:    TestTime.Now,
+		notifier:   newWorkflowNotifier(),
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	stream := &watchContextsStream{ctx: ctx, sent: make(chan *proto.WorkflowContext, 10)}
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- s.GetWorkflowContexts(&proto.WorkflowContextRequest{WorkerId: "worker", Watch: true}, stream)
+	}()
+
+	receive := func() *proto.WorkflowContext {
+		t.Helper()
+		select {
+		case c := <-stream.sent:
+			return c
+		case <-time.After(5 * time.Second):
+			t.Fatal("timed out waiting for workflow context")
+			return nil
+		}
+	}
+
+	want := &proto.WorkflowContext{
+		WorkflowId:           "default/workflow",
+		CurrentWorker:        "worker",
+		CurrentTask:          "provision",
+		CurrentAction:        "stream",
+		CurrentActionState:   proto.State_STATE_PENDING,
+		TotalNumberOfActions: 2,
+	}
+	if diff := cmp.Diff(want, receive(), protocmp.Transform()); diff != "" {
+		t.Fatalf("unexpected initial context (-want +got):\n%s", diff)
+	}
+
+	// Notifications without a change to the workflow shouldn't resend the context.
+	s.notifier.notify(wf)
+
+	if err := clnt.Get(ctx, client.ObjectKeyFromObject(wf), wf); err != nil {
+		t.Fatal(err)
+	}
+	wf.Status.State = v1alpha1.WorkflowStateRunning
+	wf.Status.Tasks[0].Actions[0].Status = v1alpha1.WorkflowStateRunning
+	if err := clnt.Status().Update(ctx, wf); err != nil {
+		t.Fatal(err)
+	}
+	s.notifier.notify(wf)
+
+	want.CurrentActionState = proto.State_STATE_RUNNING
+	if diff := cmp.Diff(want, receive(), protocmp.Transform()); diff != "" {
+		t.Fatalf("unexpected pushed context (-want +got):\n%s", diff)
+	}
+
+	cancel()
+	if err := <-errCh; err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case c := <-stream.sent:
+		t.Fatalf("unexpected context: %v", c)
+	default:
+	}
+}
+
+func TestGetWorkflowContexts_WatchUnsupported(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := v1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	clnt := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(newTestWorkflow()).
+		WithIndex(&v1alpha1.Workflow{}, workflowByNonTerminalState, workflowByNonTerminalStateFunc).
+		Build()
+
+	// Without a notifier the server falls back to answering a single poll.
+	s := &KubernetesBackedServer{
+		logger:     logr.Discard(),
+		ClientFunc: func() client.Client { return clnt },
+		nowFunc:    TestTime.Now,
+	}
+
+	stream := &watchContextsStream{ctx: context.Background(), sent: make(chan *proto.WorkflowContext, 10)}
+	if err := s.GetWorkflowContexts(&proto.WorkflowContextRequest{WorkerId: "worker", Watch: true}, stream); err != nil {
+		t.Fatal(err)
+	}
+	if len(stream.sent) != 1 {
+		t.Fatalf("expected 1 context, got %d", len(stream.sent))
+	}
+}
+
+func TestWorkflowNotifier(t *testing.T) {
+	n := newWorkflowNotifier()
+	worker, unsubscribe := n.subscribe("worker")
+	other, unsubscribeOther := n.subscribe("other")
+	defer unsubscribeOther()
+
+	wf := newTestWorkflow()
+	n.notify(wf)
+	n.notify(wf)
+	n.notify("not a workflow")
+
+	select {
+	case <-worker:
+	default:
+		t.Fatal("expected worker to be notified")
+	}
+	select {
+	case <-worker:
+		t.Fatal("expected notifications to be coalesced")
+	default:
+	}
+	select {
+	case <-other:
+		t.Fatal("expected other worker not to be notified")
+	default:
+	}
+
+	// Deletes the informer missed are delivered as tombstones.
+	n.notify(toolscache.DeletedFinalStateUnknown{Key: "default/test", Obj: wf})
+	select {
+	case <-worker:
+	default:
+		t.Fatal("expected worker to be notified of tombstone")
+	}
+
+	unsubscribe()
+	n.notify(wf)
+	select {
+	case <-worker:
+		t.Fatal("expected no notification after unsubscribing")
+	default:
+	}
+}
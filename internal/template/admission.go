@@ -0,0 +1,108 @@
+package template
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/tinkerbell/tink/api/v1alpha1"
+	"github.com/tinkerbell/tink/api/v1alpha2"
+	"github.com/tinkerbell/tink/internal/deprecated/workflow"
+	v1alpha2workflow "github.com/tinkerbell/tink/internal/workflow"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+const (
+	// v1alpha1AdmissionWebhookEndpoint is the endpoint serving the Admission handler for
+	// v1alpha1 Templates.
+	v1alpha1AdmissionWebhookEndpoint = "/validate-tinkerbell-org-v1alpha1-template"
+
+	// v1alpha2AdmissionWebhookEndpoint is the endpoint serving the Admission handler for
+	// v1alpha2 Templates.
+	v1alpha2AdmissionWebhookEndpoint = "/validate-tinkerbell-org-v1alpha2-template"
+)
+
+// +kubebuilder:webhook:path=/validate-tinkerbell-org-v1alpha1-template,mutating=false,failurePolicy=fail,groups=tinkerbell.org,resources=templates,verbs=create;update,versions=v1alpha1,name=v1alpha1.template.tinkerbell.org
+// +kubebuilder:webhook:path=/validate-tinkerbell-org-v1alpha2-template,mutating=false,failurePolicy=fail,groups=tinkerbell.org,resources=templates,verbs=create;update,versions=v1alpha2,name=v1alpha2.template.tinkerbell.org
+
+// Admission handles validation for admitting a Template object to the cluster. Templates are
+// validated without Hardware data so errors that would otherwise surface when a Workflow is
+// rendered are reported when the Template is created or updated.
+type Admission struct {
+	decoder admission.Decoder
+}
+
+// Handle satisfies controller-runtime/pkg/webhook/admission#Handler. It is responsible for deciding
+// if the given req is valid and should be admitted to the cluster.
+func (a *Admission) Handle(_ context.Context, req admission.Request) admission.Response {
+	if a.decoder == nil {
+		return admission.Errored(http.StatusInternalServerError, errors.New("misconfigured decoder"))
+	}
+
+	switch req.Kind.Version {
+	case v1alpha1.GroupVersion.Version:
+		return a.validateV1alpha1(req)
+	case v1alpha2.GroupVersion.Version:
+		return a.validateV1alpha2(req)
+	}
+
+	return admission.Errored(http.StatusBadRequest, fmt.Errorf(
+		"unsupported Template version: %v",
+		req.Kind.Version,
+	))
+}
+
+func (a *Admission) validateV1alpha1(req admission.Request) admission.Response {
+	var tpl v1alpha1.Template
+	if err := a.decoder.Decode(req, &tpl); err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+
+	// Templates without data are permitted by the schema and fail when a Workflow is rendered.
+	if tpl.Spec.Data == nil {
+		return admission.Allowed("")
+	}
+
+	if err := workflow.ValidateTemplateData(*tpl.Spec.Data); err != nil {
+		return admission.Errored(http.StatusBadRequest, fmt.Errorf("invalid Template: %w", err))
+	}
+
+	return admission.Allowed("")
+}
+
+func (a *Admission) validateV1alpha2(req admission.Request) admission.Response {
+	var tpl v1alpha2.Template
+	if err := a.decoder.Decode(req, &tpl); err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+
+	if err := v1alpha2workflow.ValidateTemplate(&tpl); err != nil {
+		return admission.Errored(http.StatusBadRequest, fmt.Errorf("invalid Template: %w", err))
+	}
+
+	return admission.Allowed("")
+}
+
+// InjectDecoder satisfies controller-runtime/pkg/webhook/admission#DecoderInjector. It is used
+// when registering the webhook to inject the decoder used by the controller manager.
+func (a *Admission) InjectDecoder(d admission.Decoder) error {
+	a.decoder = d
+	return nil
+}
+
+// SetupWithManager registers a with mgr as a webhook served from the v1alpha1 and v1alpha2
+// Template admission endpoints.
+func (a *Admission) SetupWithManager(mgr ctrl.Manager) error {
+	if a.decoder == nil {
+		a.decoder = admission.NewDecoder(mgr.GetScheme())
+	}
+
+	srv := mgr.GetWebhookServer()
+	srv.Register(v1alpha1AdmissionWebhookEndpoint, &webhook.Admission{Handler: a})
+	srv.Register(v1alpha2AdmissionWebhookEndpoint, &webhook.Admission{Handler: a})
+
+	return nil
+}
@@ -0,0 +1,212 @@
+package template_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/tinkerbell/tink/api/v1alpha1"
+	"github.com/tinkerbell/tink/api/v1alpha2"
+	"github.com/tinkerbell/tink/internal/ptr"
+	"github.com/tinkerbell/tink/internal/template"
+	admissionv1 "k8s.io/api/admission/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/json"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+const v1alpha1Data = `
+version: "0.1"
+name: debian
+global_timeout: 1800
+tasks:
+  - name: "os-installation"
+    worker: "{{.device_1}}"
+    actions:
+      - name: "stream-image"
+        image: quay.io/tinkerbell-actions/image2disk:v1.0.0
+        timeout: 600
+        environment:
+          DEST_DISK: {{ index .Hardware.Disks 0 }}
+`
+
+func TestAdmissionHandler(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = v1alpha1.AddToScheme(scheme)
+	_ = v1alpha2.AddToScheme(scheme)
+
+	// Build the Admission object.
+	adm := &template.Admission{}
+	_ = adm.InjectDecoder(admission.NewDecoder(scheme))
+
+	tests := []struct {
+		Name             string
+		Submission       runtime.Object
+		DisallowContains []string
+	}{
+		// v1alpha1
+		{
+			Name:       "V1alpha1Valid",
+			Submission: v1alpha1Template(v1alpha1Data),
+		},
+		{
+			Name:       "V1alpha1NoData",
+			Submission: &v1alpha1.Template{TypeMeta: typeMeta(v1alpha1.GroupVersion.String())},
+		},
+		{
+			Name:             "V1alpha1InvalidGoTemplate",
+			Submission:       v1alpha1Template(strings.Replace(v1alpha1Data, "{{.device_1}}", "{{.device_1", 1)),
+			DisallowContains: []string{"parsing template"},
+		},
+		{
+			Name:             "V1alpha1InvalidImage",
+			Submission:       v1alpha1Template(strings.Replace(v1alpha1Data, "image2disk", "Image2Disk", 1)),
+			DisallowContains: []string{"invalid action image", "Image2Disk"},
+		},
+		{
+			Name:             "V1alpha1NameTooLong",
+			Submission:       v1alpha1Template(strings.Replace(v1alpha1Data, "stream-image", strings.Repeat("a", 200), 1)),
+			DisallowContains: []string{"name cannot be empty or have more than 200 characters"},
+		},
+		{
+			Name: "V1alpha1DuplicateTaskNames",
+			Submission: v1alpha1Template(v1alpha1Data + `
+  - name: "os-installation"
+    worker: "{{.device_1}}"
+    actions:
+      - name: "reboot"
+        image: quay.io/tinkerbell-actions/reboot:v1.0.0
+`),
+			DisallowContains: []string{"two tasks in a template cannot have same name", "os-installation"},
+		},
+
+		// v1alpha2
+		{
+			Name: "V1alpha2Valid",
+			Submission: v1alpha2Template(
+				v1alpha2.Action{Name: "stream-image", Image: "quay.io/tinkerbell-actions/image2disk:v1.0.0"},
+				v1alpha2.Action{Name: "raid", Image: "{{ .Param.raidImage }}", When: "gt {{ len .Hardware.StorageDevices }} 1"},
+			),
+		},
+		{
+			Name: "V1alpha2InvalidGoTemplate",
+			Submission: v1alpha2Template(
+				v1alpha2.Action{Name: "stream-image", Image: "{{ .Param.image "},
+			),
+			DisallowContains: []string{"parse template"},
+		},
+		{
+			Name: "V1alpha2InvalidImage",
+			Submission: v1alpha2Template(
+				v1alpha2.Action{Name: "stream-image", Image: "quay.io/Image2Disk"},
+			),
+			DisallowContains: []string{"invalid image", "quay.io/Image2Disk"},
+		},
+		{
+			Name: "V1alpha2InvalidCondition",
+			Submission: v1alpha2Template(
+				v1alpha2.Action{Name: "stream-image", Image: "image2disk", When: "eq ("},
+			),
+			DisallowContains: []string{"stream-image", "parse condition"},
+		},
+		{
+			Name: "V1alpha2EmptyName",
+			Submission: v1alpha2Template(
+				v1alpha2.Action{Image: "image2disk"},
+			),
+			DisallowContains: []string{"action name cannot be empty"},
+		},
+		{
+			Name: "V1alpha2DuplicateNames",
+			Submission: v1alpha2Template(
+				v1alpha2.Action{Name: "stream-image", Image: "image2disk"},
+				v1alpha2.Action{Name: "stream-image", Image: "image2disk"},
+			),
+			DisallowContains: []string{"action names must be unique", "stream-image"},
+		},
+		{
+			Name:             "V1alpha2NoActions",
+			Submission:       v1alpha2Template(),
+			DisallowContains: []string{"at least one action"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.Name, func(t *testing.T) {
+			// We're assuming the json marshaller works with the controller runtime decoder.
+			buf, err := json.Marshal(tc.Submission)
+			if err != nil {
+				t.Fatalf("encoding test object: %v", err)
+			}
+
+			gvk := tc.Submission.GetObjectKind().GroupVersionKind()
+			req := admission.Request{
+				AdmissionRequest: admissionv1.AdmissionRequest{
+					Kind: metav1.GroupVersionKind{Group: gvk.Group, Version: gvk.Version, Kind: gvk.Kind},
+					Object: runtime.RawExtension{
+						Raw: buf,
+					},
+				},
+			}
+
+			// Run the object through the handler.
+			resp := adm.Handle(context.Background(), req)
+
+			if len(tc.DisallowContains) == 0 {
+				if !resp.Allowed {
+					t.Fatalf("disallowed: %v", resp.Result.Message)
+				}
+			} else {
+				if resp.Allowed {
+					t.Fatalf("expected object to be disallowed but was allowed")
+				}
+
+				for _, substr := range tc.DisallowContains {
+					if !strings.Contains(resp.Result.Message, substr) {
+						t.Fatalf(
+							"expected reason to contain '%v' but got '%v'",
+							substr,
+							resp.Result.Message,
+						)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestAdmissionHandler_UnsupportedVersion(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = v1alpha2.AddToScheme(scheme)
+
+	adm := &template.Admission{}
+	_ = adm.InjectDecoder(admission.NewDecoder(scheme))
+
+	resp := adm.Handle(context.Background(), admission.Request{
+		AdmissionRequest: admissionv1.AdmissionRequest{
+			Kind: metav1.GroupVersionKind{Group: "tinkerbell.org", Version: "v1beta1", Kind: "Template"},
+		},
+	})
+	if resp.Allowed {
+		t.Fatal("expected object to be disallowed but was allowed")
+	}
+}
+
+func typeMeta(apiVersion string) metav1.TypeMeta {
+	return metav1.TypeMeta{APIVersion: apiVersion, Kind: "Template"}
+}
+
+func v1alpha1Template(data string) *v1alpha1.Template {
+	return &v1alpha1.Template{
+		TypeMeta: typeMeta(v1alpha1.GroupVersion.String()),
+		Spec:     v1alpha1.TemplateSpec{Data: ptr.String(data)},
+	}
+}
+
+func v1alpha2Template(actions ...v1alpha2.Action) *v1alpha2.Template {
+	return &v1alpha2.Template{
+		TypeMeta: typeMeta(v1alpha2.GroupVersion.String()),
+		Spec:     v1alpha2.TemplateSpec{Actions: actions},
+	}
+}
@@ -3,14 +3,18 @@ package internal
 import (
 	"bytes"
 	"context"
+	"fmt"
 	"text/template"
 	"time"
 
 	"github.com/go-logr/logr"
 	"github.com/google/uuid"
 	tinkv1 "github.com/tinkerbell/tink/api/v1alpha2"
+	"github.com/tinkerbell/tink/internal/agent/condition"
+	"github.com/tinkerbell/tink/internal/ptr"
 	"gopkg.in/yaml.v3"
 	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 )
@@ -23,12 +27,57 @@ type ReconciliationContext struct {
 	// NewActionID generated unique IDs for actions. Defaults to generating UUIDv4s.
 	NewActionID func() string
 
+	// Now returns the current time. Defaults to time.Now.
+	Now func() time.Time
+
+	// AgentTimeout is the time after which a Scheduled or Running Workflow fails if its agent
+	// hasn't sent a heartbeat. Zero disables liveness checks.
+	AgentTimeout time.Duration
+
+	// CancelTimeout is the time a Cancelling Workflow waits for its agent to confirm cancellation
+	// before it transitions to Canceled regardless. Zero waits indefinitely.
+	CancelTimeout time.Duration
+
 	Log    logr.Logger
 	Client client.Client
 }
 
 // Reconcile reconciles the Workflow.
 func (rc ReconciliationContext) Reconcile(ctx context.Context) (reconcile.Result, error) {
+	// Terminal workflows are never modified.
+	if rc.Workflow.Status.State.IsTerminal() {
+		return reconcile.Result{}, nil
+	}
+
+	if rc.Workflow.Spec.Cancel {
+		return rc.cancel(), nil
+	}
+
+	// Only render the template and configure action status if its not been done before.
+	if len(rc.Workflow.Status.Actions) == 0 {
+		if result, err := rc.render(ctx); err != nil || !result.IsZero() {
+			return result, err
+		}
+	}
+
+	rc.updateState()
+
+	timeout := rc.enforceTimeout()
+	if rc.Workflow.Status.State.IsTerminal() {
+		return timeout, nil
+	}
+
+	liveness, err := rc.enforceAgentLiveness(ctx)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	return earliest(timeout, liveness), nil
+}
+
+// render renders the Template into the Workflow status actions and transitions the Workflow to
+// Pending.
+func (rc ReconciliationContext) render(ctx context.Context) (reconcile.Result, error) {
 	tmplRef := client.ObjectKey{
 		Name:      rc.Workflow.Spec.TemplateRef.Name,
 		Namespace: rc.Workflow.Namespace,
@@ -38,6 +87,7 @@ func (rc ReconciliationContext) Reconcile(ctx context.Context) (reconcile.Result
 		if errors.IsNotFound(err) {
 			// The Template may yet to be submitted to the cluster so just requeue.
 			rc.Log.Info("Template not found; requeue in 5 seconds", "ref", tmplRef)
+			rc.setRenderedCondition(tinkv1.ConditionStatusFalse, tinkv1.WorkflowReasonTemplateNotFound, err.Error())
 			return reconcile.Result{RequeueAfter: 5 * time.Second}, nil
 		}
 		return reconcile.Result{}, err
@@ -51,25 +101,236 @@ func (rc ReconciliationContext) Reconcile(ctx context.Context) (reconcile.Result
 	if err := rc.Client.Get(ctx, hwRef, &hw); err != nil {
 		if errors.IsNotFound(err) {
 			// The Hardware may yet to be submitted to the cluster so just requeue.
-			rc.Log.Info("Hardware not found; requeue in 5 seconds", "ref", tmplRef)
+			rc.Log.Info("Hardware not found; requeue in 5 seconds", "ref", hwRef)
+			rc.setRenderedCondition(tinkv1.ConditionStatusFalse, tinkv1.WorkflowReasonHardwareNotFound, err.Error())
 			return reconcile.Result{RequeueAfter: 5 * time.Second}, nil
 		}
 		return reconcile.Result{}, err
 	}
 
-	// Only render the template and configure action status if its not been done before.
-	if len(rc.Workflow.Status.Actions) == 0 {
-		tmpl, err := rc.renderTemplate(tmpl, &hw)
+	rendered, err := rc.renderTemplate(tmpl, &hw)
+	if err != nil {
+		rc.setRenderedCondition(tinkv1.ConditionStatusFalse, tinkv1.WorkflowReasonRenderFailed, err.Error())
+		return reconcile.Result{}, err
+	}
+
+	rc.Workflow.Status.Actions = rc.toActionStatus(rendered.Spec)
+	rc.setRenderedCondition(tinkv1.ConditionStatusTrue, "", "")
+	rc.setState(tinkv1.WorkflowStatePending)
+
+	return reconcile.Result{}, nil
+}
+
+// cancel transitions the Workflow toward Canceled. Workflows that may be executing on an agent
+// transition to Cancelling and remain there until the agent confirms cancellation or
+// CancelTimeout elapses.
+func (rc ReconciliationContext) cancel() reconcile.Result {
+	switch rc.Workflow.Status.State {
+	case tinkv1.WorkflowStateScheduled, tinkv1.WorkflowStateRunning:
+		rc.Log.Info("Requesting agent cancel workflow")
+		rc.setState(tinkv1.WorkflowStateCancelling)
+		return reconcile.Result{RequeueAfter: rc.CancelTimeout}
+
+	case tinkv1.WorkflowStateCancelling:
+		// Await confirmation from the agent. If it doesn't respond, typically because it's
+		// offline, cancel the workflow regardless so it isn't stuck forever.
+		if rc.CancelTimeout <= 0 {
+			return reconcile.Result{}
+		}
+		remaining := rc.Workflow.Status.LastTransition.Add(rc.CancelTimeout).Sub(rc.now())
+		if remaining > 0 {
+			return reconcile.Result{RequeueAfter: remaining}
+		}
+		rc.Log.Info("Agent did not confirm cancellation within timeout; canceling workflow", "timeout", rc.CancelTimeout)
+		rc.setState(tinkv1.WorkflowStateCanceled)
+
+	default:
+		rc.Log.Info("Workflow canceled before dispatch")
+		rc.setState(tinkv1.WorkflowStateCanceled)
+	}
+
+	return reconcile.Result{}
+}
+
+// updateState derives the Workflow state from its action states. Transitions to Pending and
+// Scheduled are driven by rendering and dispatch respectively so are not handled here.
+func (rc ReconciliationContext) updateState() {
+	status := &rc.Workflow.Status
+
+	var started, succeeded int
+	for _, action := range status.Actions {
+		switch action.State {
+		case tinkv1.ActionStateFailed, tinkv1.ActionStateRunning:
+			started++
+		// Skipped actions have completed so count toward the workflow succeeding.
+		case tinkv1.ActionStateSucceeded, tinkv1.ActionStateSkipped:
+			succeeded++
+			started++
+		}
+
+		if action.StartedAt != nil && (status.StartedAt == nil || action.StartedAt.Before(status.StartedAt)) {
+			status.StartedAt = action.StartedAt.DeepCopy()
+		}
+	}
+
+	switch {
+	// Actions grouped with a failed action continue until they finish.
+	case rc.Workflow.GroupFailed():
+		rc.setState(tinkv1.WorkflowStateFailed)
+	case len(status.Actions) > 0 && succeeded == len(status.Actions):
+		rc.setState(tinkv1.WorkflowStateSucceeded)
+	case started > 0 && status.State != tinkv1.WorkflowStateCancelling:
+		rc.setState(tinkv1.WorkflowStateRunning)
+	}
+}
+
+// enforceTimeout fails the Workflow if it has exceeded its TimeoutSeconds. If the Workflow is
+// running and has not exceeded its timeout it returns a result that requeues at the deadline.
+func (rc ReconciliationContext) enforceTimeout() reconcile.Result {
+	status := &rc.Workflow.Status
+	if rc.Workflow.Spec.TimeoutSeconds <= 0 || status.StartedAt == nil || status.State.IsTerminal() {
+		return reconcile.Result{}
+	}
+
+	timeout := time.Duration(rc.Workflow.Spec.TimeoutSeconds) * time.Second
+	remaining := status.StartedAt.Add(timeout).Sub(rc.now())
+	if remaining > 0 {
+		return reconcile.Result{RequeueAfter: remaining}
+	}
+
+	now := metav1.NewTime(rc.now())
+	message := fmt.Sprintf("workflow exceeded timeout of %v", timeout)
+	for i := range status.Actions {
+		action := &status.Actions[i]
+		if action.State == tinkv1.ActionStateRunning {
+			action.State = tinkv1.ActionStateFailed
+			action.LastTransition = &now
+			action.FailureReason = tinkv1.WorkflowReasonTimeout
+			action.FailureMessage = message
+		}
+	}
+
+	status.Conditions.Set(tinkv1.Condition{
+		Type:           tinkv1.WorkflowConditionTimedOut,
+		Status:         tinkv1.ConditionStatusTrue,
+		LastTransition: now,
+		Reason:         ptr.String(tinkv1.WorkflowReasonTimeout),
+		Message:        &message,
+	})
+	rc.setState(tinkv1.WorkflowStateFailed)
+
+	return reconcile.Result{}
+}
+
+// enforceAgentLiveness fails Scheduled and Running Workflows whose agent hasn't sent a heartbeat
+// within AgentTimeout. Agents are identified by the MACs of the Workflow's Hardware. Workflows
+// whose agents have never sent a heartbeat are unaffected as their liveness is unknown. If the
+// agent is alive it returns a result that requeues when the agent would be considered lost.
+func (rc ReconciliationContext) enforceAgentLiveness(ctx context.Context) (reconcile.Result, error) {
+	status := &rc.Workflow.Status
+	if rc.AgentTimeout <= 0 {
+		return reconcile.Result{}, nil
+	}
+	if status.State != tinkv1.WorkflowStateScheduled && status.State != tinkv1.WorkflowStateRunning {
+		return reconcile.Result{}, nil
+	}
+
+	var hw tinkv1.Hardware
+	hwRef := client.ObjectKey{Name: rc.Workflow.Spec.HardwareRef.Name, Namespace: rc.Workflow.Namespace}
+	if err := rc.Client.Get(ctx, hwRef, &hw); err != nil {
+		return reconcile.Result{}, client.IgnoreNotFound(err)
+	}
+
+	var lastSeen *time.Time
+	for _, mac := range hw.GetMACs() {
+		var agent tinkv1.Agent
+		err := rc.Client.Get(ctx, client.ObjectKey{Name: tinkv1.AgentName(mac), Namespace: hw.Namespace}, &agent)
+		if errors.IsNotFound(err) {
+			continue
+		}
 		if err != nil {
 			return reconcile.Result{}, err
 		}
+		if lastSeen == nil || agent.Status.LastSeen.After(*lastSeen) {
+			lastSeen = &agent.Status.LastSeen.Time
+		}
+	}
+	if lastSeen == nil {
+		return reconcile.Result{}, nil
+	}
+
+	// Measure from the last transition if it's more recent so a stale heartbeat doesn't fail a
+	// workflow that has just been dispatched.
+	since := *lastSeen
+	if status.LastTransition.After(since) {
+		since = status.LastTransition.Time
+	}
 
-		rc.Workflow.Status.Actions = rc.toActionStatus(tmpl.Spec.Actions)
+	remaining := since.Add(rc.AgentTimeout).Sub(rc.now())
+	if remaining > 0 {
+		return reconcile.Result{RequeueAfter: remaining}, nil
 	}
 
+	now := metav1.NewTime(rc.now())
+	message := fmt.Sprintf("agent sent no heartbeat for %v", rc.AgentTimeout)
+	for i := range status.Actions {
+		action := &status.Actions[i]
+		if action.State == tinkv1.ActionStateRunning {
+			action.State = tinkv1.ActionStateFailed
+			action.LastTransition = &now
+			action.FailureReason = tinkv1.WorkflowReasonHeartbeatTimeout
+			action.FailureMessage = message
+		}
+	}
+	status.Conditions.Set(tinkv1.Condition{
+		Type:           tinkv1.WorkflowConditionAgentLost,
+		Status:         tinkv1.ConditionStatusTrue,
+		LastTransition: now,
+		Reason:         ptr.String(tinkv1.WorkflowReasonHeartbeatTimeout),
+		Message:        &message,
+	})
+	rc.setState(tinkv1.WorkflowStateFailed)
+	rc.Log.Info("Agent lost; failing workflow", "lastSeen", *lastSeen)
 	return reconcile.Result{}, nil
 }
 
+// earliest returns the result that requeues soonest. Results that don't requeue are ignored.
+func earliest(a, b reconcile.Result) reconcile.Result {
+	switch {
+	case a.RequeueAfter == 0:
+		return b
+	case b.RequeueAfter == 0 || a.RequeueAfter < b.RequeueAfter:
+		return a
+	default:
+		return b
+	}
+}
+
+// setState transitions the Workflow to state updating the last transition time if the state
+// changed.
+func (rc ReconciliationContext) setState(state tinkv1.WorkflowState) {
+	if rc.Workflow.Status.State == state {
+		return
+	}
+	rc.Workflow.Status.State = state
+	rc.Workflow.Status.LastTransition = metav1.NewTime(rc.now())
+}
+
+func (rc ReconciliationContext) setRenderedCondition(status tinkv1.ConditionStatus, reason, message string) {
+	cond := tinkv1.Condition{
+		Type:           tinkv1.WorkflowConditionTemplateRendered,
+		Status:         status,
+		LastTransition: metav1.NewTime(rc.now()),
+	}
+	if reason != "" {
+		cond.Reason = &reason
+	}
+	if message != "" {
+		cond.Message = &message
+	}
+	rc.Workflow.Status.Conditions.Set(cond)
+}
+
 func (rc ReconciliationContext) renderTemplate(tpl tinkv1.Template, hw *tinkv1.Hardware) (tinkv1.Template, error) {
 	tplYAML, err := yaml.Marshal(tpl)
 	if err != nil {
@@ -98,12 +359,45 @@ func (rc ReconciliationContext) renderTemplate(tpl tinkv1.Template, hw *tinkv1.H
 		return tinkv1.Template{}, err
 	}
 
+	// Catch mistakes the agent can only report once the action runs before the workflow is
+	// dispatched.
+	for _, action := range tpl.Spec.Actions {
+		if err := condition.Validate(action.When); err != nil {
+			return tinkv1.Template{}, fmt.Errorf("action %v: %w", action.Name, err)
+		}
+
+		if action.Retries < 0 || action.Retries > maxRetries {
+			return tinkv1.Template{}, fmt.Errorf("action %v: retries must be between 0 and %d: %v", action.Name, maxRetries, action.Retries)
+		}
+
+		if ns := action.Namespace; ns != nil && ns.PID != nil && *ns.PID != 1 {
+			return tinkv1.Template{}, fmt.Errorf("action %v: unsupported pid namespace: %v", action.Name, *ns.PID)
+		}
+	}
+
 	return tpl, nil
 }
 
-func (rc ReconciliationContext) toActionStatus(actions []tinkv1.Action) []tinkv1.ActionStatus {
+// toActionStatus builds action statuses for all actions in spec. Template level environment
+// variables and volumes are merged into each action with action level environment variables taking
+// precedence.
+func (rc ReconciliationContext) toActionStatus(spec tinkv1.TemplateSpec) []tinkv1.ActionStatus {
 	var status []tinkv1.ActionStatus
-	for _, action := range actions {
+	for _, action := range spec.Actions {
+		if len(spec.Env) > 0 {
+			env := make(map[string]string, len(spec.Env)+len(action.Env))
+			for k, v := range spec.Env {
+				env[k] = v
+			}
+			for k, v := range action.Env {
+				env[k] = v
+			}
+			action.Env = env
+		}
+		if len(spec.Volumes) > 0 {
+			action.Volumes = append(append([]tinkv1.Volume{}, spec.Volumes...), action.Volumes...)
+		}
+
 		status = append(status, tinkv1.ActionStatus{
 			Rendered: action,
 			ID:       rc.newActionID(),
@@ -113,6 +407,13 @@ func (rc ReconciliationContext) toActionStatus(actions []tinkv1.Action) []tinkv1
 	return status
 }
 
+func (rc ReconciliationContext) now() time.Time {
+	if rc.Now != nil {
+		return rc.Now()
+	}
+	return time.Now()
+}
+
 func (rc ReconciliationContext) newActionID() string {
 	if rc.NewActionID != nil {
 		return rc.NewActionID()
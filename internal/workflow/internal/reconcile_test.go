@@ -4,20 +4,25 @@ import (
 	"context"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/go-logr/zerologr"
 	"github.com/google/go-cmp/cmp"
 	"github.com/rs/zerolog"
 	tinkv1 "github.com/tinkerbell/tink/api/v1alpha2"
 	"github.com/tinkerbell/tink/internal/ptr"
+	"github.com/tinkerbell/tink/internal/testtime"
 	. "github.com/tinkerbell/tink/internal/workflow/internal" //nolint:revive // Dot imports should not be used. Problem for another time though.
 	corev1 "k8s.io/api/core/v1"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	machineryruntimeutil "k8s.io/apimachinery/pkg/util/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
 
+var testTime = testtime.NewFrozenTimeUnix(1637361793)
+
 func TestReconcileContext(t *testing.T) {
 	ctx := context.Background()
 
@@ -49,6 +54,15 @@ func TestReconcileContext(t *testing.T) {
 			ID:    newActionID(),
 		},
 	}
+	expectWrkflw.Status.State = tinkv1.WorkflowStatePending
+	expectWrkflw.Status.LastTransition = *testTime.MetaV1Now()
+	expectWrkflw.Status.Conditions = tinkv1.Conditions{
+		{
+			Type:           tinkv1.WorkflowConditionTemplateRendered,
+			Status:         tinkv1.ConditionStatusTrue,
+			LastTransition: *testTime.MetaV1Now(),
+		},
+	}
 
 	zl := zerolog.New(os.Stdout)
 	logger := zerologr.New(&zl)
@@ -66,6 +80,7 @@ func TestReconcileContext(t *testing.T) {
 		Log:         logger,
 		Workflow:    wrkflw,
 		NewActionID: newActionID,
+		Now:         testTime.Now,
 	}
 	_, err := reconcileCtx.Reconcile(ctx)
 	if err != nil {
@@ -77,6 +92,665 @@ func TestReconcileContext(t *testing.T) {
 	}
 }
 
+func TestReconcileContext_TemplateEnvAndVolumes(t *testing.T) {
+	ctx := context.Background()
+
+	hw := newHardware(func(*tinkv1.Hardware) {})
+	tmpl := newTemplate(func(t *tinkv1.Template) {
+		t.Spec.Env = map[string]string{"SHARED": "template", "TEMPLATE": "template"}
+		t.Spec.Volumes = []tinkv1.Volume{"/template:/template"}
+		t.Spec.Actions = []tinkv1.Action{
+			{
+				Name:    "action",
+				Image:   "image",
+				Env:     map[string]string{"SHARED": "action"},
+				Volumes: []tinkv1.Volume{"/action:/action"},
+			},
+		}
+	})
+	wrkflw := newWorkflow(func(w *tinkv1.Workflow) {
+		w.Spec.HardwareRef = corev1.LocalObjectReference{Name: hw.Name}
+		w.Spec.TemplateRef = corev1.LocalObjectReference{Name: tmpl.Name}
+	})
+
+	scheme := runtime.NewScheme()
+	machineryruntimeutil.Must(tinkv1.AddToScheme(scheme))
+
+	clnt := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(hw, tmpl).
+		Build()
+
+	zl := zerolog.New(os.Stdout)
+	reconcileCtx := ReconciliationContext{
+		Client:      clnt,
+		Log:         zerologr.New(&zl),
+		Workflow:    wrkflw,
+		NewActionID: newActionID,
+	}
+	if _, err := reconcileCtx.Reconcile(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	expect := newAction(func(a *tinkv1.Action) {
+		a.Name = "action"
+		a.Image = "image"
+		a.Env = map[string]string{"SHARED": "action", "TEMPLATE": "template"}
+		a.Volumes = []tinkv1.Volume{"/template:/template", "/action:/action"}
+	})
+	if len(wrkflw.Status.Actions) != 1 {
+		t.Fatalf("Expected 1 action; received %v", len(wrkflw.Status.Actions))
+	}
+	if !cmp.Equal(expect, wrkflw.Status.Actions[0].Rendered) {
+		t.Fatal(cmp.Diff(expect, wrkflw.Status.Actions[0].Rendered))
+	}
+}
+
+func TestReconcileContext_RetryPolicy(t *testing.T) {
+	ctx := context.Background()
+
+	hw := newHardware(func(*tinkv1.Hardware) {})
+	tmpl := newTemplate(func(t *tinkv1.Template) {
+		t.Spec.Actions = []tinkv1.Action{
+			{
+				Name:    "action",
+				Image:   "image",
+				Retries: 3,
+				Backoff: &v1.Duration{Duration: 5 * time.Second},
+				RetryOn: []string{"1", "ImagePull"},
+			},
+		}
+	})
+	wrkflw := newWorkflow(func(w *tinkv1.Workflow) {
+		w.Spec.HardwareRef = corev1.LocalObjectReference{Name: hw.Name}
+		w.Spec.TemplateRef = corev1.LocalObjectReference{Name: tmpl.Name}
+	})
+
+	scheme := runtime.NewScheme()
+	machineryruntimeutil.Must(tinkv1.AddToScheme(scheme))
+
+	clnt := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(hw, tmpl).
+		Build()
+
+	zl := zerolog.New(os.Stdout)
+	reconcileCtx := ReconciliationContext{
+		Client:      clnt,
+		Log:         zerologr.New(&zl),
+		Workflow:    wrkflw,
+		NewActionID: newActionID,
+	}
+	if _, err := reconcileCtx.Reconcile(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	expect := newAction(func(a *tinkv1.Action) {
+		a.Name = "action"
+		a.Image = "image"
+		a.Retries = 3
+		a.Backoff = &v1.Duration{Duration: 5 * time.Second}
+		a.RetryOn = []string{"1", "ImagePull"}
+	})
+	if len(wrkflw.Status.Actions) != 1 {
+		t.Fatalf("Expected 1 action; received %v", len(wrkflw.Status.Actions))
+	}
+	if !cmp.Equal(expect, wrkflw.Status.Actions[0].Rendered) {
+		t.Fatal(cmp.Diff(expect, wrkflw.Status.Actions[0].Rendered))
+	}
+}
+
+func TestReconcileContext_RetryPolicyExceedsMaximum(t *testing.T) {
+	hw := newHardware(func(*tinkv1.Hardware) {})
+	tmpl := newTemplate(func(t *tinkv1.Template) {
+		t.Spec.Actions = []tinkv1.Action{{Name: "action", Image: "image", Retries: 1000}}
+	})
+	wrkflw := newWorkflow(func(w *tinkv1.Workflow) {
+		w.Spec.HardwareRef = corev1.LocalObjectReference{Name: hw.Name}
+		w.Spec.TemplateRef = corev1.LocalObjectReference{Name: tmpl.Name}
+	})
+
+	scheme := runtime.NewScheme()
+	machineryruntimeutil.Must(tinkv1.AddToScheme(scheme))
+
+	clnt := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(hw, tmpl).
+		Build()
+
+	zl := zerolog.New(os.Stdout)
+	reconcileCtx := ReconciliationContext{
+		Client:      clnt,
+		Log:         zerologr.New(&zl),
+		Workflow:    wrkflw,
+		NewActionID: newActionID,
+	}
+	if _, err := reconcileCtx.Reconcile(context.Background()); err == nil {
+		t.Fatal("Expected error")
+	}
+
+	cond := wrkflw.Status.Conditions.Get(tinkv1.WorkflowConditionTemplateRendered)
+	if cond == nil || cond.Reason == nil || *cond.Reason != tinkv1.WorkflowReasonRenderFailed {
+		t.Fatalf("Expected %v condition with reason %v; received %+v", tinkv1.WorkflowConditionTemplateRendered, tinkv1.WorkflowReasonRenderFailed, cond)
+	}
+}
+
+func TestReconcileContext_Condition(t *testing.T) {
+	cases := []struct {
+		Name        string
+		When        string
+		ExpectWhen  string
+		ExpectError bool
+	}{
+		{Name: "HardwareInterpolated", When: "gt {{ len .Hardware.StorageDevices }} 1", ExpectWhen: "gt 2 1"},
+		{Name: "OutputsPreserved", When: `eq .Outputs.detect.raid "true"`, ExpectWhen: `eq .Outputs.detect.raid "true"`},
+		{Name: "Invalid", When: "eq (", ExpectError: true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.Name, func(t *testing.T) {
+			hw := newHardware(func(h *tinkv1.Hardware) {
+				h.Spec.StorageDevices = []tinkv1.StorageDevice{"/dev/sda", "/dev/sdb"}
+			})
+			tmpl := newTemplate(func(t *tinkv1.Template) {
+				t.Spec.Actions = []tinkv1.Action{{Name: "action", Image: "image", When: tc.When}}
+			})
+			wrkflw := newWorkflow(func(w *tinkv1.Workflow) {
+				w.Spec.HardwareRef = corev1.LocalObjectReference{Name: hw.Name}
+				w.Spec.TemplateRef = corev1.LocalObjectReference{Name: tmpl.Name}
+			})
+
+			scheme := runtime.NewScheme()
+			machineryruntimeutil.Must(tinkv1.AddToScheme(scheme))
+
+			clnt := fake.NewClientBuilder().
+				WithScheme(scheme).
+				WithObjects(hw, tmpl).
+				Build()
+
+			zl := zerolog.New(os.Stdout)
+			reconcileCtx := ReconciliationContext{
+				Client:      clnt,
+				Log:         zerologr.New(&zl),
+				Workflow:    wrkflw,
+				NewActionID: newActionID,
+			}
+			_, err := reconcileCtx.Reconcile(context.Background())
+			if tc.ExpectError {
+				if err == nil {
+					t.Fatal("Expected error")
+				}
+				cond := wrkflw.Status.Conditions.Get(tinkv1.WorkflowConditionTemplateRendered)
+				if cond == nil || cond.Reason == nil || *cond.Reason != tinkv1.WorkflowReasonRenderFailed {
+					t.Fatalf("Expected %v condition with reason %v; received %+v", tinkv1.WorkflowConditionTemplateRendered, tinkv1.WorkflowReasonRenderFailed, cond)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(wrkflw.Status.Actions) != 1 {
+				t.Fatalf("Expected 1 action; received %v", len(wrkflw.Status.Actions))
+			}
+			if when := wrkflw.Status.Actions[0].Rendered.When; when != tc.ExpectWhen {
+				t.Fatalf("Expected condition %q; received %q", tc.ExpectWhen, when)
+			}
+		})
+	}
+}
+
+func TestReconcileContext_PIDNamespace(t *testing.T) {
+	cases := []struct {
+		Name        string
+		PID         *int
+		ExpectError bool
+	}{
+		{Name: "Unset"},
+		{Name: "Host", PID: ptr.Int(1)},
+		{Name: "Unsupported", PID: ptr.Int(42), ExpectError: true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.Name, func(t *testing.T) {
+			hw := newHardware(func(*tinkv1.Hardware) {})
+			tmpl := newTemplate(func(t *tinkv1.Template) {
+				t.Spec.Actions = []tinkv1.Action{{
+					Name:      "action",
+					Image:     "image",
+					Namespace: &tinkv1.Namespace{PID: tc.PID},
+				}}
+			})
+			wrkflw := newWorkflow(func(w *tinkv1.Workflow) {
+				w.Spec.HardwareRef = corev1.LocalObjectReference{Name: hw.Name}
+				w.Spec.TemplateRef = corev1.LocalObjectReference{Name: tmpl.Name}
+			})
+
+			scheme := runtime.NewScheme()
+			machineryruntimeutil.Must(tinkv1.AddToScheme(scheme))
+
+			clnt := fake.NewClientBuilder().
+				WithScheme(scheme).
+				WithObjects(hw, tmpl).
+				Build()
+
+			zl := zerolog.New(os.Stdout)
+			reconcileCtx := ReconciliationContext{
+				Client:      clnt,
+				Log:         zerologr.New(&zl),
+				Workflow:    wrkflw,
+				NewActionID: newActionID,
+			}
+			_, err := reconcileCtx.Reconcile(context.Background())
+			if !tc.ExpectError {
+				if err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+
+			if err == nil {
+				t.Fatal("Expected error")
+			}
+			cond := wrkflw.Status.Conditions.Get(tinkv1.WorkflowConditionTemplateRendered)
+			if cond == nil || cond.Reason == nil || *cond.Reason != tinkv1.WorkflowReasonRenderFailed {
+				t.Fatalf("Expected %v condition with reason %v; received %+v", tinkv1.WorkflowConditionTemplateRendered, tinkv1.WorkflowReasonRenderFailed, cond)
+			}
+		})
+	}
+}
+
+func TestReconcileContext_State(t *testing.T) {
+	started := testTime.MetaV1Before(30 * time.Second)
+
+	cases := []struct {
+		Name          string
+		Workflow      func(*tinkv1.Workflow)
+		ExpectState   tinkv1.WorkflowState
+		ExpectStarted *v1.Time
+		ExpectRequeue time.Duration
+		ExpectActions []tinkv1.ActionState
+	}{
+		{
+			Name: "ScheduledRemainsScheduled",
+			Workflow: func(w *tinkv1.Workflow) {
+				w.Status.State = tinkv1.WorkflowStateScheduled
+			},
+			ExpectState:   tinkv1.WorkflowStateScheduled,
+			ExpectActions: []tinkv1.ActionState{tinkv1.ActionStatePending, tinkv1.ActionStatePending},
+		},
+		{
+			Name: "ActionRunning",
+			Workflow: func(w *tinkv1.Workflow) {
+				w.Status.State = tinkv1.WorkflowStateScheduled
+				w.Status.Actions[0].State = tinkv1.ActionStateRunning
+				w.Status.Actions[0].StartedAt = started
+			},
+			ExpectState:   tinkv1.WorkflowStateRunning,
+			ExpectStarted: started,
+			ExpectActions: []tinkv1.ActionState{tinkv1.ActionStateRunning, tinkv1.ActionStatePending},
+		},
+		{
+			Name: "AllActionsSucceeded",
+			Workflow: func(w *tinkv1.Workflow) {
+				w.Status.State = tinkv1.WorkflowStateRunning
+				w.Status.StartedAt = started
+				w.Status.Actions[0].State = tinkv1.ActionStateSucceeded
+				w.Status.Actions[1].State = tinkv1.ActionStateSucceeded
+			},
+			ExpectState:   tinkv1.WorkflowStateSucceeded,
+			ExpectStarted: started,
+			ExpectActions: []tinkv1.ActionState{tinkv1.ActionStateSucceeded, tinkv1.ActionStateSucceeded},
+		},
+		{
+			Name: "ActionFailed",
+			Workflow: func(w *tinkv1.Workflow) {
+				w.Status.State = tinkv1.WorkflowStateRunning
+				w.Status.StartedAt = started
+				w.Status.Actions[0].State = tinkv1.ActionStateFailed
+			},
+			ExpectState:   tinkv1.WorkflowStateFailed,
+			ExpectStarted: started,
+			ExpectActions: []tinkv1.ActionState{tinkv1.ActionStateFailed, tinkv1.ActionStatePending},
+		},
+		{
+			Name: "GroupedActionFailed",
+			Workflow: func(w *tinkv1.Workflow) {
+				w.Status.State = tinkv1.WorkflowStateRunning
+				w.Status.StartedAt = started
+				w.Status.Actions[0].State = tinkv1.ActionStateFailed
+				w.Status.Actions[0].Rendered.Group = "group"
+				w.Status.Actions[1].State = tinkv1.ActionStateRunning
+				w.Status.Actions[1].Rendered.Group = "group"
+			},
+			ExpectState:   tinkv1.WorkflowStateRunning,
+			ExpectStarted: started,
+			ExpectActions: []tinkv1.ActionState{tinkv1.ActionStateFailed, tinkv1.ActionStateRunning},
+		},
+		{
+			Name: "GroupedActionFailedBeforeSiblingStarted",
+			Workflow: func(w *tinkv1.Workflow) {
+				w.Status.State = tinkv1.WorkflowStateRunning
+				w.Status.StartedAt = started
+				w.Status.Actions[0].State = tinkv1.ActionStateFailed
+				w.Status.Actions[0].Rendered.Group = "group"
+				w.Status.Actions[1].Rendered.Group = "group"
+			},
+			ExpectState:   tinkv1.WorkflowStateRunning,
+			ExpectStarted: started,
+			ExpectActions: []tinkv1.ActionState{tinkv1.ActionStateFailed, tinkv1.ActionStatePending},
+		},
+		{
+			Name: "ActionSkipped",
+			Workflow: func(w *tinkv1.Workflow) {
+				w.Status.State = tinkv1.WorkflowStateRunning
+				w.Status.StartedAt = started
+				w.Status.Actions[0].State = tinkv1.ActionStateSucceeded
+				w.Status.Actions[1].State = tinkv1.ActionStateSkipped
+			},
+			ExpectState:   tinkv1.WorkflowStateSucceeded,
+			ExpectStarted: started,
+			ExpectActions: []tinkv1.ActionState{tinkv1.ActionStateSucceeded, tinkv1.ActionStateSkipped},
+		},
+		{
+			Name: "WithinTimeout",
+			Workflow: func(w *tinkv1.Workflow) {
+				w.Spec.TimeoutSeconds = 60
+				w.Status.State = tinkv1.WorkflowStateRunning
+				w.Status.StartedAt = started
+				w.Status.Actions[0].State = tinkv1.ActionStateRunning
+			},
+			ExpectState:   tinkv1.WorkflowStateRunning,
+			ExpectStarted: started,
+			ExpectRequeue: 30 * time.Second,
+			ExpectActions: []tinkv1.ActionState{tinkv1.ActionStateRunning, tinkv1.ActionStatePending},
+		},
+		{
+			Name: "TimedOut",
+			Workflow: func(w *tinkv1.Workflow) {
+				w.Spec.TimeoutSeconds = 10
+				w.Status.State = tinkv1.WorkflowStateRunning
+				w.Status.StartedAt = started
+				w.Status.Actions[0].State = tinkv1.ActionStateRunning
+			},
+			ExpectState:   tinkv1.WorkflowStateFailed,
+			ExpectStarted: started,
+			ExpectActions: []tinkv1.ActionState{tinkv1.ActionStateFailed, tinkv1.ActionStatePending},
+		},
+		{
+			Name: "CancelRunning",
+			Workflow: func(w *tinkv1.Workflow) {
+				w.Spec.Cancel = true
+				w.Status.State = tinkv1.WorkflowStateRunning
+				w.Status.StartedAt = started
+				w.Status.Actions[0].State = tinkv1.ActionStateRunning
+			},
+			ExpectState:   tinkv1.WorkflowStateCancelling,
+			ExpectStarted: started,
+			ExpectActions: []tinkv1.ActionState{tinkv1.ActionStateRunning, tinkv1.ActionStatePending},
+		},
+		{
+			Name: "CancelPending",
+			Workflow: func(w *tinkv1.Workflow) {
+				w.Spec.Cancel = true
+				w.Status.State = tinkv1.WorkflowStatePending
+			},
+			ExpectState:   tinkv1.WorkflowStateCanceled,
+			ExpectActions: []tinkv1.ActionState{tinkv1.ActionStatePending, tinkv1.ActionStatePending},
+		},
+		{
+			Name: "CancellingAwaitsAgent",
+			Workflow: func(w *tinkv1.Workflow) {
+				w.Spec.Cancel = true
+				w.Status.State = tinkv1.WorkflowStateCancelling
+				w.Status.StartedAt = started
+				w.Status.Actions[0].State = tinkv1.ActionStateRunning
+			},
+			ExpectState:   tinkv1.WorkflowStateCancelling,
+			ExpectStarted: started,
+			ExpectActions: []tinkv1.ActionState{tinkv1.ActionStateRunning, tinkv1.ActionStatePending},
+		},
+		{
+			Name: "TerminalStateUnchanged",
+			Workflow: func(w *tinkv1.Workflow) {
+				w.Spec.TimeoutSeconds = 10
+				w.Status.State = tinkv1.WorkflowStateCanceled
+				w.Status.StartedAt = started
+				w.Status.Actions[0].State = tinkv1.ActionStateRunning
+			},
+			ExpectState:   tinkv1.WorkflowStateCanceled,
+			ExpectStarted: started,
+			ExpectActions: []tinkv1.ActionState{tinkv1.ActionStateRunning, tinkv1.ActionStatePending},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.Name, func(t *testing.T) {
+			wrkflw := newWorkflow(func(w *tinkv1.Workflow) {
+				w.Status.Actions = []tinkv1.ActionStatus{
+					{ID: "1", State: tinkv1.ActionStatePending},
+					{ID: "2", State: tinkv1.ActionStatePending},
+				}
+				tc.Workflow(w)
+			})
+
+			zl := zerolog.New(os.Stdout)
+			reconcileCtx := ReconciliationContext{
+				Client:   fake.NewClientBuilder().Build(),
+				Log:      zerologr.New(&zl),
+				Workflow: wrkflw,
+				Now:      testTime.Now,
+			}
+			result, err := reconcileCtx.Reconcile(context.Background())
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if wrkflw.Status.State != tc.ExpectState {
+				t.Fatalf("Expected state %v; received %v", tc.ExpectState, wrkflw.Status.State)
+			}
+			if !cmp.Equal(tc.ExpectStarted, wrkflw.Status.StartedAt) {
+				t.Fatal(cmp.Diff(tc.ExpectStarted, wrkflw.Status.StartedAt))
+			}
+			if result.RequeueAfter != tc.ExpectRequeue {
+				t.Fatalf("Expected requeue after %v; received %v", tc.ExpectRequeue, result.RequeueAfter)
+			}
+
+			var actions []tinkv1.ActionState
+			for _, a := range wrkflw.Status.Actions {
+				actions = append(actions, a.State)
+			}
+			if !cmp.Equal(tc.ExpectActions, actions) {
+				t.Fatal(cmp.Diff(tc.ExpectActions, actions))
+			}
+
+			if tc.Name == "TimedOut" {
+				cond := wrkflw.Status.Conditions.Get(tinkv1.WorkflowConditionTimedOut)
+				if cond == nil || cond.Status != tinkv1.ConditionStatusTrue {
+					t.Fatalf("Expected %v condition to be true; received %+v", tinkv1.WorkflowConditionTimedOut, cond)
+				}
+				if wrkflw.Status.Actions[0].FailureReason != tinkv1.WorkflowReasonTimeout {
+					t.Fatalf("Expected failure reason %v; received %v", tinkv1.WorkflowReasonTimeout, wrkflw.Status.Actions[0].FailureReason)
+				}
+			}
+		})
+	}
+}
+
+func TestReconcileContext_AgentLiveness(t *testing.T) {
+	cases := []struct {
+		Name           string
+		State          tinkv1.WorkflowState
+		LastTransition time.Duration
+		LastSeen       *time.Duration
+		ExpectState    tinkv1.WorkflowState
+		ExpectRequeue  time.Duration
+	}{
+		{
+			Name:           "AgentAlive",
+			State:          tinkv1.WorkflowStateRunning,
+			LastTransition: 10 * time.Minute,
+			LastSeen:       durationPtr(time.Minute),
+			ExpectState:    tinkv1.WorkflowStateRunning,
+			ExpectRequeue:  4 * time.Minute,
+		},
+		{
+			Name:           "AgentLost",
+			State:          tinkv1.WorkflowStateRunning,
+			LastTransition: 10 * time.Minute,
+			LastSeen:       durationPtr(6 * time.Minute),
+			ExpectState:    tinkv1.WorkflowStateFailed,
+		},
+		{
+			Name:           "RecentlyScheduled",
+			State:          tinkv1.WorkflowStateScheduled,
+			LastTransition: time.Minute,
+			LastSeen:       durationPtr(time.Hour),
+			ExpectState:    tinkv1.WorkflowStateScheduled,
+			ExpectRequeue:  4 * time.Minute,
+		},
+		{
+			Name:           "NoHeartbeats",
+			State:          tinkv1.WorkflowStateRunning,
+			LastTransition: time.Hour,
+			ExpectState:    tinkv1.WorkflowStateRunning,
+		},
+		{
+			Name:           "PendingUnaffected",
+			State:          tinkv1.WorkflowStatePending,
+			LastTransition: time.Hour,
+			LastSeen:       durationPtr(time.Hour),
+			ExpectState:    tinkv1.WorkflowStatePending,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.Name, func(t *testing.T) {
+			hw := newHardware(func(hw *tinkv1.Hardware) {
+				hw.Spec.NetworkInterfaces = tinkv1.NetworkInterfaces{"00:00:00:00:00:01": {}}
+			})
+			wrkflw := newWorkflow(func(w *tinkv1.Workflow) {
+				w.Spec.HardwareRef = corev1.LocalObjectReference{Name: hw.Name}
+				w.Status.State = tc.State
+				w.Status.LastTransition = *testTime.MetaV1Before(tc.LastTransition)
+				w.Status.Actions = []tinkv1.ActionStatus{
+					{ID: "1", State: tinkv1.ActionStateRunning},
+					{ID: "2", State: tinkv1.ActionStatePending},
+				}
+			})
+			if tc.State != tinkv1.WorkflowStateRunning {
+				wrkflw.Status.Actions[0].State = tinkv1.ActionStatePending
+			}
+
+			objs := []client.Object{hw}
+			if tc.LastSeen != nil {
+				objs = append(objs, &tinkv1.Agent{
+					ObjectMeta: v1.ObjectMeta{Name: "00-00-00-00-00-01"},
+					Status:     tinkv1.AgentStatus{LastSeen: *testTime.MetaV1Before(*tc.LastSeen)},
+				})
+			}
+
+			scheme := runtime.NewScheme()
+			machineryruntimeutil.Must(tinkv1.AddToScheme(scheme))
+
+			zl := zerolog.New(os.Stdout)
+			reconcileCtx := ReconciliationContext{
+				Client:       fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build(),
+				Log:          zerologr.New(&zl),
+				Workflow:     wrkflw,
+				Now:          testTime.Now,
+				AgentTimeout: 5 * time.Minute,
+			}
+			result, err := reconcileCtx.Reconcile(context.Background())
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if wrkflw.Status.State != tc.ExpectState {
+				t.Fatalf("Expected state %v; received %v", tc.ExpectState, wrkflw.Status.State)
+			}
+			if result.RequeueAfter != tc.ExpectRequeue {
+				t.Fatalf("Expected requeue after %v; received %v", tc.ExpectRequeue, result.RequeueAfter)
+			}
+
+			if tc.ExpectState == tinkv1.WorkflowStateFailed {
+				cond := wrkflw.Status.Conditions.Get(tinkv1.WorkflowConditionAgentLost)
+				if cond == nil || cond.Status != tinkv1.ConditionStatusTrue {
+					t.Fatalf("Expected %v condition to be true; received %+v", tinkv1.WorkflowConditionAgentLost, cond)
+				}
+				if wrkflw.Status.Actions[0].FailureReason != tinkv1.WorkflowReasonHeartbeatTimeout {
+					t.Fatalf("Expected failure reason %v; received %v", tinkv1.WorkflowReasonHeartbeatTimeout, wrkflw.Status.Actions[0].FailureReason)
+				}
+			}
+		})
+	}
+}
+
+func TestReconcileContext_CancelTimeout(t *testing.T) {
+	cases := []struct {
+		Name           string
+		State          tinkv1.WorkflowState
+		LastTransition time.Duration
+		ExpectState    tinkv1.WorkflowState
+		ExpectRequeue  time.Duration
+	}{
+		{
+			Name:           "CancelRunning",
+			State:          tinkv1.WorkflowStateRunning,
+			LastTransition: time.Hour,
+			ExpectState:    tinkv1.WorkflowStateCancelling,
+			ExpectRequeue:  time.Minute,
+		},
+		{
+			Name:           "CancellingWithinTimeout",
+			State:          tinkv1.WorkflowStateCancelling,
+			LastTransition: 20 * time.Second,
+			ExpectState:    tinkv1.WorkflowStateCancelling,
+			ExpectRequeue:  40 * time.Second,
+		},
+		{
+			Name:           "CancellingAfterTimeout",
+			State:          tinkv1.WorkflowStateCancelling,
+			LastTransition: 2 * time.Minute,
+			ExpectState:    tinkv1.WorkflowStateCanceled,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.Name, func(t *testing.T) {
+			wrkflw := newWorkflow(func(w *tinkv1.Workflow) {
+				w.Spec.Cancel = true
+				w.Status.State = tc.State
+				w.Status.LastTransition = *testTime.MetaV1Before(tc.LastTransition)
+				w.Status.Actions = []tinkv1.ActionStatus{
+					{ID: "1", State: tinkv1.ActionStateRunning},
+					{ID: "2", State: tinkv1.ActionStatePending},
+				}
+			})
+
+			zl := zerolog.New(os.Stdout)
+			reconcileCtx := ReconciliationContext{
+				Client:        fake.NewClientBuilder().Build(),
+				Log:           zerologr.New(&zl),
+				Workflow:      wrkflw,
+				Now:           testTime.Now,
+				CancelTimeout: time.Minute,
+			}
+			result, err := reconcileCtx.Reconcile(context.Background())
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if wrkflw.Status.State != tc.ExpectState {
+				t.Fatalf("Expected state %v; received %v", tc.ExpectState, wrkflw.Status.State)
+			}
+			if result.RequeueAfter != tc.ExpectRequeue {
+				t.Fatalf("Expected requeue after %v; received %v", tc.ExpectRequeue, result.RequeueAfter)
+			}
+		})
+	}
+}
+
 func newWorkflow(fn func(*tinkv1.Workflow)) *tinkv1.Workflow {
 	w := &tinkv1.Workflow{
 		TypeMeta: v1.TypeMeta{
@@ -125,6 +799,7 @@ func newAction(fn func(*tinkv1.Action)) tinkv1.Action {
 		Args:    []string{},
 		Env:     map[string]string{},
 		Volumes: []tinkv1.Volume{},
+		RetryOn: []string{},
 	}
 	fn(&a)
 	return a
@@ -133,3 +808,7 @@ func newAction(fn func(*tinkv1.Action)) tinkv1.Action {
 func newActionID() string {
 	return "8659e46f-00ff-40e4-a19b-c8661ca81167"
 }
+
+func durationPtr(d time.Duration) *time.Duration {
+	return &d
+}
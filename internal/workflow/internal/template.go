@@ -1,8 +1,23 @@
 package internal
 
 import (
+	"errors"
 	"fmt"
 	"strings"
+	"text/template"
+
+	"github.com/distribution/reference"
+	tinkv1 "github.com/tinkerbell/tink/api/v1alpha2"
+	"github.com/tinkerbell/tink/internal/agent/condition"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// maxNameLength is the maximum length of action names.
+	maxNameLength = 200
+
+	// maxRetries is the maximum number of times an action can be retried.
+	maxRetries = 100
 )
 
 // workflowTemplateFuncs defines the custom functions available to workflow templates.
@@ -33,3 +48,53 @@ func formatPartition(dev string, partition int) string {
 	}
 	return dev
 }
+
+// ValidateTemplate validates tpl without rendering it so invalid templates can be rejected before
+// a Workflow references them. Action names can't be templated so they're always validated. Images
+// and conditions are only validated when they don't contain template actions as their values
+// depend on the data used to render the template.
+func ValidateTemplate(tpl tinkv1.Template) error {
+	tplYAML, err := yaml.Marshal(tpl)
+	if err != nil {
+		return err
+	}
+
+	if _, err := template.New("").Funcs(workflowTemplateFuncs).Parse(string(tplYAML)); err != nil {
+		return fmt.Errorf("parse template: %w", err)
+	}
+
+	names := map[string]struct{}{}
+	for _, action := range tpl.Spec.Actions {
+		if action.Name == "" || len(action.Name) >= maxNameLength {
+			return fmt.Errorf("action name cannot be empty or have %d or more characters: %v", maxNameLength, action.Name)
+		}
+
+		if _, ok := names[action.Name]; ok {
+			return fmt.Errorf("action names must be unique: %v", action.Name)
+		}
+		names[action.Name] = struct{}{}
+
+		if !isTemplated(action.Image) {
+			if _, err := reference.ParseNormalizedNamed(action.Image); err != nil {
+				return fmt.Errorf("action %v: invalid image (%v): %w", action.Name, action.Image, err)
+			}
+		}
+
+		if !isTemplated(action.When) {
+			if err := condition.Validate(action.When); err != nil {
+				return fmt.Errorf("action %v: %w", action.Name, err)
+			}
+		}
+	}
+
+	if len(names) == 0 {
+		return errors.New("template must have at least one action")
+	}
+
+	return nil
+}
+
+// isTemplated determines if s contains template actions.
+func isTemplated(s string) bool {
+	return strings.Contains(s, "{{")
+}
@@ -7,31 +7,73 @@ import (
 	tinkv1 "github.com/tinkerbell/tink/api/v1alpha2"
 	"github.com/tinkerbell/tink/internal/workflow/internal"
 	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	kerrors "k8s.io/apimachinery/pkg/util/errors"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 )
 
+const (
+	// cancelPollInterval is the interval at which deleted workflows are checked for cancellation
+	// confirmation from the agent.
+	cancelPollInterval = 5 * time.Second
+
+	// defaultCancelGracePeriod is the default time a canceled or deleted workflow waits for the
+	// agent to confirm cancellation before it's canceled or the finalizer is removed regardless.
+	defaultCancelGracePeriod = time.Minute
+)
+
 // Reconciler reconciles Workflow instances.
 type Reconciler struct {
-	client  client.Client
-	nowFunc func() time.Time
+	client            client.Client
+	nowFunc           func() time.Time
+	agentTimeout      time.Duration
+	cancelGracePeriod time.Duration
+}
+
+// Option configures a Reconciler.
+type Option func(*Reconciler)
+
+// WithAgentTimeout fails Scheduled and Running workflows whose agent hasn't sent a heartbeat for
+// timeout. Zero disables liveness checks.
+func WithAgentTimeout(timeout time.Duration) Option {
+	return func(r *Reconciler) {
+		r.agentTimeout = timeout
+	}
+}
+
+// WithCancelGracePeriod sets the time a canceled or deleted workflow waits for the agent to
+// confirm cancellation before it's canceled or released regardless. Defaults to one minute;
+// non-positive periods are ignored.
+func WithCancelGracePeriod(period time.Duration) Option {
+	return func(r *Reconciler) {
+		if period > 0 {
+			r.cancelGracePeriod = period
+		}
+	}
 }
 
 // NewReconciler creates a Reconciler instance.
-func NewReconciler(clnt client.Client) *Reconciler {
-	return &Reconciler{
-		client:  clnt,
-		nowFunc: time.Now,
+func NewReconciler(clnt client.Client, opts ...Option) *Reconciler {
+	r := &Reconciler{
+		client:            clnt,
+		nowFunc:           time.Now,
+		cancelGracePeriod: defaultCancelGracePeriod,
+	}
+	for _, opt := range opts {
+		opt(r)
 	}
+	return r
 }
 
 // +kubebuilder:rbac:groups=tinkerbell.org,resources=hardware;hardware/status,verbs=get;list;watch;update;patch
 // +kubebuilder:rbac:groups=tinkerbell.org,resources=templates;templates/status,verbs=get;list;watch;update;patch
 // +kubebuilder:rbac:groups=tinkerbell.org,resources=workflows;workflows/status,verbs=get;list;watch;update;patch
 // +kubebuilder:rbac:groups=tinkerbell.org,resources=workflows;workflows/finalizers,verbs=update
+// +kubebuilder:rbac:groups=tinkerbell.org,resources=agents,verbs=get;list;watch
 
 func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (result reconcile.Result, rerr error) {
 	logger := ctrl.LoggerFrom(ctx)
@@ -45,20 +87,37 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (resu
 		return reconcile.Result{}, client.IgnoreNotFound(err)
 	}
 
-	// TODO(chrisdoherty)
 	if !wrkflw.DeletionTimestamp.IsZero() {
-		return reconcile.Result{}, nil
+		return r.reconcileDelete(ctx, wrkflw)
+	}
+
+	if !controllerutil.ContainsFinalizer(wrkflw, tinkv1.WorkflowFinalizer) {
+		patch := client.MergeFromWithOptions(wrkflw.DeepCopy(), client.MergeFromWithOptimisticLock{})
+		controllerutil.AddFinalizer(wrkflw, tinkv1.WorkflowFinalizer)
+		if err := r.client.Patch(ctx, wrkflw, patch); err != nil {
+			return requeueOnConflict(err)
+		}
 	}
 
 	rc := internal.ReconciliationContext{
-		Client:   r.client,
-		Log:      logger,
-		Workflow: wrkflw.DeepCopy(),
+		Client:        r.client,
+		Log:           logger,
+		Workflow:      wrkflw.DeepCopy(),
+		Now:           r.nowFunc,
+		AgentTimeout:  r.agentTimeout,
+		CancelTimeout: r.cancelGracePeriod,
 	}
 
-	// Always attempt to patch.
+	// Always attempt to patch. The server updates action status concurrently and merge patches
+	// replace the actions list so patches are rejected if the workflow changed since it was read.
 	defer func() {
-		if err := r.client.Status().Patch(ctx, rc.Workflow, client.MergeFrom(wrkflw)); err != nil {
+		patch := client.MergeFromWithOptions(wrkflw, client.MergeFromWithOptimisticLock{})
+		if err := r.client.Status().Patch(ctx, rc.Workflow, patch); err != nil {
+			if errors.IsConflict(err) {
+				logger.Info("Workflow modified during reconciliation; requeueing")
+				result, rerr = reconcile.Result{Requeue: true}, nil
+				return
+			}
 			rerr = kerrors.NewAggregate([]error{rerr, err})
 		}
 	}()
@@ -66,6 +125,56 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (resu
 	return rc.Reconcile(ctx)
 }
 
+// reconcileDelete ensures in-flight workflows are canceled on the agent before removing the
+// finalizer. Workflows that are yet to be dispatched, or have finished, are released immediately.
+func (r *Reconciler) reconcileDelete(ctx context.Context, wrkflw *tinkv1.Workflow) (reconcile.Result, error) {
+	logger := ctrl.LoggerFrom(ctx)
+
+	if !controllerutil.ContainsFinalizer(wrkflw, tinkv1.WorkflowFinalizer) {
+		return reconcile.Result{}, nil
+	}
+
+	switch wrkflw.Status.State {
+	case tinkv1.WorkflowStateScheduled, tinkv1.WorkflowStateRunning:
+		logger.Info("Cancelling workflow before deletion")
+		updated := wrkflw.DeepCopy()
+		updated.Status.State = tinkv1.WorkflowStateCancelling
+		updated.Status.LastTransition = metav1.NewTime(r.nowFunc())
+		patch := client.MergeFromWithOptions(wrkflw, client.MergeFromWithOptimisticLock{})
+		if err := r.client.Status().Patch(ctx, updated, patch); err != nil {
+			return requeueOnConflict(err)
+		}
+		return reconcile.Result{RequeueAfter: cancelPollInterval}, nil
+
+	case tinkv1.WorkflowStateCancelling:
+		// Give the agent an opportunity to stop the workflow. If it doesn't respond within the
+		// grace period we release the workflow regardless so deletion isn't blocked forever.
+		deadline := wrkflw.Status.LastTransition.Add(r.cancelGracePeriod)
+		if r.nowFunc().Before(deadline) {
+			return reconcile.Result{RequeueAfter: cancelPollInterval}, nil
+		}
+		logger.Info("Agent did not confirm cancellation within grace period; releasing workflow", "gracePeriod", r.cancelGracePeriod)
+	}
+
+	// Release the workflow only if it's unchanged so a late cancellation confirmation from the
+	// agent is observed.
+	patch := client.MergeFromWithOptions(wrkflw.DeepCopy(), client.MergeFromWithOptimisticLock{})
+	controllerutil.RemoveFinalizer(wrkflw, tinkv1.WorkflowFinalizer)
+	if err := r.client.Patch(ctx, wrkflw, patch); err != nil {
+		return requeueOnConflict(err)
+	}
+	return reconcile.Result{}, nil
+}
+
+// requeueOnConflict requeues reconciliation if err indicates the workflow was modified
+// concurrently. Other errors are returned as is.
+func requeueOnConflict(err error) (reconcile.Result, error) {
+	if errors.IsConflict(err) {
+		return reconcile.Result{Requeue: true}, nil
+	}
+	return reconcile.Result{}, err
+}
+
 func (r *Reconciler) SetupWithManager(mgr manager.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&tinkv1.Workflow{}).
@@ -1,9 +1,19 @@
 package workflow
 
 import (
+	"context"
+	"testing"
+	"time"
+
 	"github.com/tinkerbell/tink/api/v1alpha2"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 )
 
 var scheme = runtime.NewScheme()
@@ -12,3 +22,227 @@ func init() {
 	_ = clientgoscheme.AddToScheme(scheme)
 	_ = v1alpha2.AddToScheme(scheme)
 }
+
+func TestReconcileDelete(t *testing.T) {
+	now := time.Unix(1637361793, 0)
+
+	cases := []struct {
+		Name           string
+		State          v1alpha2.WorkflowState
+		LastTransition time.Time
+		GracePeriod    time.Duration
+		ExpectState    v1alpha2.WorkflowState
+		ExpectRequeue  time.Duration
+		ExpectDeleted  bool
+	}{
+		{
+			Name:          "Running",
+			State:         v1alpha2.WorkflowStateRunning,
+			ExpectState:   v1alpha2.WorkflowStateCancelling,
+			ExpectRequeue: cancelPollInterval,
+		},
+		{
+			Name:           "CancellingWithinGracePeriod",
+			State:          v1alpha2.WorkflowStateCancelling,
+			LastTransition: now.Add(-time.Second),
+			ExpectState:    v1alpha2.WorkflowStateCancelling,
+			ExpectRequeue:  cancelPollInterval,
+		},
+		{
+			Name:           "CancellingAfterGracePeriod",
+			State:          v1alpha2.WorkflowStateCancelling,
+			LastTransition: now.Add(-2 * defaultCancelGracePeriod),
+			ExpectDeleted:  true,
+		},
+		{
+			Name:           "CancellingWithinCustomGracePeriod",
+			State:          v1alpha2.WorkflowStateCancelling,
+			LastTransition: now.Add(-2 * defaultCancelGracePeriod),
+			GracePeriod:    3 * defaultCancelGracePeriod,
+			ExpectState:    v1alpha2.WorkflowStateCancelling,
+			ExpectRequeue:  cancelPollInterval,
+		},
+		{
+			Name:           "CancellingAfterCustomGracePeriod",
+			State:          v1alpha2.WorkflowStateCancelling,
+			LastTransition: now.Add(-2 * time.Second),
+			GracePeriod:    time.Second,
+			ExpectDeleted:  true,
+		},
+		{
+			Name:          "Pending",
+			State:         v1alpha2.WorkflowStatePending,
+			ExpectDeleted: true,
+		},
+		{
+			Name:          "Failed",
+			State:         v1alpha2.WorkflowStateFailed,
+			ExpectDeleted: true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.Name, func(t *testing.T) {
+			deleted := metav1.NewTime(now)
+			wrkflw := &v1alpha2.Workflow{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:              "workflow",
+					Namespace:         "default",
+					DeletionTimestamp: &deleted,
+					Finalizers:        []string{v1alpha2.WorkflowFinalizer},
+				},
+				Status: v1alpha2.WorkflowStatus{
+					State:          tc.State,
+					LastTransition: metav1.NewTime(tc.LastTransition),
+				},
+			}
+
+			clnt := fake.NewClientBuilder().
+				WithScheme(scheme).
+				WithObjects(wrkflw).
+				WithStatusSubresource(wrkflw).
+				Build()
+
+			r := NewReconciler(clnt, WithCancelGracePeriod(tc.GracePeriod))
+			r.nowFunc = func() time.Time { return now }
+
+			result, err := r.Reconcile(context.Background(), reconcile.Request{NamespacedName: client.ObjectKeyFromObject(wrkflw)})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if result.RequeueAfter != tc.ExpectRequeue {
+				t.Fatalf("Expected requeue after %v; received %v", tc.ExpectRequeue, result.RequeueAfter)
+			}
+
+			var got v1alpha2.Workflow
+			err = clnt.Get(context.Background(), client.ObjectKeyFromObject(wrkflw), &got)
+			if tc.ExpectDeleted {
+				if !errors.IsNotFound(err) {
+					t.Fatalf("Expected workflow to be deleted; received %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.Status.State != tc.ExpectState {
+				t.Fatalf("Expected state %v; received %v", tc.ExpectState, got.Status.State)
+			}
+		})
+	}
+}
+
+func TestReconcile_ConcurrentStatusUpdate(t *testing.T) {
+	now := time.Unix(1637361793, 0)
+	started := metav1.NewTime(now.Add(-2 * time.Minute))
+
+	wrkflw := &v1alpha2.Workflow{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       "workflow",
+			Namespace:  "default",
+			Finalizers: []string{v1alpha2.WorkflowFinalizer},
+		},
+		Spec: v1alpha2.WorkflowSpec{TimeoutSeconds: 60},
+		Status: v1alpha2.WorkflowStatus{
+			State:     v1alpha2.WorkflowStateRunning,
+			StartedAt: &started,
+			Actions: []v1alpha2.ActionStatus{
+				{ID: "1", State: v1alpha2.ActionStateRunning},
+			},
+		},
+	}
+
+	// Simulate the server recording the action's success after the reconciler read the workflow.
+	var modified bool
+	clnt := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(wrkflw).
+		WithStatusSubresource(wrkflw).
+		WithInterceptorFuncs(interceptor.Funcs{
+			Get: func(ctx context.Context, c client.WithWatch, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+				if err := c.Get(ctx, key, obj, opts...); err != nil || modified {
+					return err
+				}
+				modified = true
+				updated := obj.(*v1alpha2.Workflow).DeepCopy()
+				updated.Status.Actions[0].State = v1alpha2.ActionStateSucceeded
+				return c.Status().Update(ctx, updated)
+			},
+		}).
+		Build()
+
+	r := NewReconciler(clnt)
+	r.nowFunc = func() time.Time { return now }
+
+	result, err := r.Reconcile(context.Background(), reconcile.Request{NamespacedName: client.ObjectKeyFromObject(wrkflw)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.Requeue {
+		t.Fatal("Expected reconciliation to be requeued")
+	}
+
+	var got v1alpha2.Workflow
+	if err := clnt.Get(context.Background(), client.ObjectKeyFromObject(wrkflw), &got); err != nil {
+		t.Fatal(err)
+	}
+	if state := got.Status.Actions[0].State; state != v1alpha2.ActionStateSucceeded {
+		t.Fatalf("Expected action state %v; received %v", v1alpha2.ActionStateSucceeded, state)
+	}
+}
+
+func TestReconcileDelete_ConcurrentCancellation(t *testing.T) {
+	now := time.Unix(1637361793, 0)
+	deleted := metav1.NewTime(now)
+
+	wrkflw := &v1alpha2.Workflow{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "workflow",
+			Namespace:         "default",
+			DeletionTimestamp: &deleted,
+			Finalizers:        []string{v1alpha2.WorkflowFinalizer},
+		},
+		Status: v1alpha2.WorkflowStatus{
+			State:          v1alpha2.WorkflowStateCancelling,
+			LastTransition: metav1.NewTime(now.Add(-2 * defaultCancelGracePeriod)),
+		},
+	}
+
+	// Simulate the agent confirming cancellation after the reconciler read the workflow.
+	var modified bool
+	clnt := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(wrkflw).
+		WithStatusSubresource(wrkflw).
+		WithInterceptorFuncs(interceptor.Funcs{
+			Get: func(ctx context.Context, c client.WithWatch, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+				if err := c.Get(ctx, key, obj, opts...); err != nil || modified {
+					return err
+				}
+				modified = true
+				updated := obj.(*v1alpha2.Workflow).DeepCopy()
+				updated.Status.State = v1alpha2.WorkflowStateCanceled
+				return c.Status().Update(ctx, updated)
+			},
+		}).
+		Build()
+
+	r := NewReconciler(clnt)
+	r.nowFunc = func() time.Time { return now }
+
+	result, err := r.Reconcile(context.Background(), reconcile.Request{NamespacedName: client.ObjectKeyFromObject(wrkflw)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.Requeue {
+		t.Fatal("Expected reconciliation to be requeued")
+	}
+
+	var got v1alpha2.Workflow
+	if err := clnt.Get(context.Background(), client.ObjectKeyFromObject(wrkflw), &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Status.State != v1alpha2.WorkflowStateCanceled {
+		t.Fatalf("Expected state %v; received %v", v1alpha2.WorkflowStateCanceled, got.Status.State)
+	}
+}
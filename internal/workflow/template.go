@@ -0,0 +1,12 @@
+package workflow
+
+import (
+	tinkv1 "github.com/tinkerbell/tink/api/v1alpha2"
+	"github.com/tinkerbell/tink/internal/workflow/internal"
+)
+
+// ValidateTemplate validates tpl without rendering it so invalid Templates can be rejected before
+// a Workflow references them.
+func ValidateTemplate(tpl *tinkv1.Template) error {
+	return internal.ValidateTemplate(*tpl)
+}